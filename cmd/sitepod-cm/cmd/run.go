@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"sitepod.io/sitepod/pkg/api/v1"
 	"sitepod.io/sitepod/pkg/system"
+	"sitepod.io/sitepod/pkg/webapi"
 )
 
 var runCmd = &cobra.Command{
@@ -10,9 +12,31 @@ var runCmd = &cobra.Command{
 	Short: "run in server mode",
 	Run: func(cmd *cobra.Command, args []string) {
 
+		flags := cmd.Flags()
+		webConfig := webapi.DefaultConfig()
+		webConfig.BindAddress, _ = flags.GetString("web-bind-address")
+		webConfig.TLSCertFile, _ = flags.GetString("web-tls-cert")
+		webConfig.TLSKeyFile, _ = flags.GetString("web-tls-key")
+		webConfig.SecureCookies, _ = flags.GetBool("web-secure-cookies")
+		webConfig.SessionStore, _ = flags.GetString("session-store")
+		webConfig.SessionDir, _ = flags.GetString("session-dir")
+		webConfig.SessionKeysFile, _ = flags.GetString("session-keys-file")
+		webConfig.SessionKeysSecret, _ = flags.GetString("session-keys-secret")
+		webConfig.SessionMaxAge, _ = flags.GetInt("session-max-age")
+		webConfig.UIDir, _ = flags.GetString("ui-dir")
+		webConfig.PublicURL, _ = flags.GetString("public-url")
+		webConfig.MailTransport, _ = flags.GetString("mail-transport")
+		webConfig.MailFrom, _ = flags.GetString("mail-from")
+		webConfig.SMTPAddress, _ = flags.GetString("smtp-address")
+		webConfig.SMTPUsername, _ = flags.GetString("smtp-username")
+		webConfig.SMTPPasswordFile, _ = flags.GetString("smtp-password-file")
+		webConfig.MailDir, _ = flags.GetString("mail-dir")
+
 		config := &system.SimpleConfig{
 			ApiServer: cmd.Flag("apiserver").Value.String(),
 			Namespace: cmd.Flag("namespace").Value.String(),
+			Cluster:   cmd.Flag("cluster").Value.String(),
+			WebApi:    webConfig,
 		}
 
 		stopCh := make(chan struct{})
@@ -25,4 +49,22 @@ func init() {
 	RootCmd.AddCommand(runCmd)
 	runCmd.PersistentFlags().String("apiserver", "http://127.0.0.1:8080", "root URL to api-server e.g. https://127.0.0.1:6443")
 	runCmd.PersistentFlags().String("namespace", "default", "namespace to operate on")
+	runCmd.PersistentFlags().String("cluster", v1.DefaultClusterName, "name of the cluster resource to operate as, created on first start")
+	runCmd.PersistentFlags().String("web-bind-address", webapi.DefaultBindAddress, "address the web api listens on")
+	runCmd.PersistentFlags().String("web-tls-cert", "", "TLS certificate file, with --web-tls-key serves https")
+	runCmd.PersistentFlags().String("web-tls-key", "", "TLS private key file")
+	runCmd.PersistentFlags().Bool("web-secure-cookies", false, "mark session cookies secure when TLS is terminated by a proxy")
+	runCmd.PersistentFlags().String("session-store", webapi.SessionStoreFilesystem, "session store: filesystem, memory or shared (across instances)")
+	runCmd.PersistentFlags().String("session-dir", "", "directory of the filesystem session store, default os temp dir")
+	runCmd.PersistentFlags().String("session-keys-file", "", "file of base64 session keys, one per line, newest first")
+	runCmd.PersistentFlags().String("session-keys-secret", "", "secret holding session keys under "+webapi.SessionKeysSecretKey+", overrides --session-keys-file")
+	runCmd.PersistentFlags().Int("session-max-age", webapi.DefaultSessionMaxAge, "session lifetime in seconds")
+	runCmd.PersistentFlags().String("ui-dir", "", "directory of the frontend build served under /ui, default the embedded ui")
+	runCmd.PersistentFlags().String("public-url", "", "url users reach the panel at, for links in mails e.g. https://panel.example.com")
+	runCmd.PersistentFlags().String("mail-transport", webapi.MailTransportLog, "how mails are sent: smtp, file or log (development)")
+	runCmd.PersistentFlags().String("mail-from", webapi.DefaultMailFrom, "sender address of mails")
+	runCmd.PersistentFlags().String("smtp-address", "", "host:port of the smtp submission server")
+	runCmd.PersistentFlags().String("smtp-username", "", "smtp username, blank for no auth")
+	runCmd.PersistentFlags().String("smtp-password-file", "", "file holding the smtp password")
+	runCmd.PersistentFlags().String("mail-dir", "", "directory the file mail transport writes to")
 }
@@ -0,0 +1,213 @@
+// Copyright © 2016 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"k8s.io/kubernetes/pkg/labels"
+	"sitepod.io/sitepod/pkg/api/v1"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Create, list and restore backups of sitepod home data",
+	Long:  "Create, list and restore backups of sitepod home data",
+}
+
+var backupCreateCmd = &cobra.Command{
+	Use:   "create SITEPOD",
+	Short: "Backup a sitepod now, or on a recurring schedule with --every",
+	Long: `Backup a sitepod now, or on a recurring schedule with --every.
+
+The archive is written to a local or NFS path mounted on the controller manager
+(--target-path) or an S3 compatible endpoint (--s3-endpoint and --s3-bucket)`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdutil.CheckErr(RunBackupCreate(cmd, args))
+	},
+}
+
+var backupListCmd = &cobra.Command{
+	Use:   "list [SITEPOD]",
+	Short: "List backups, optionally only those of a sitepod",
+	Long:  "List backups, optionally only those of a sitepod",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdutil.CheckErr(RunBackupList(cmd, args))
+	},
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore BACKUP",
+	Short: "Restore a completed backup into its sitepod",
+	Long:  "Restore a completed backup into its sitepod",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdutil.CheckErr(RunBackupRestore(cmd, args))
+	},
+}
+
+func backupTargetFromFlags(cmd *cobra.Command) (v1.BackupTarget, error) {
+
+	target := v1.BackupTarget{}
+	path, _ := cmd.Flags().GetString("target-path")
+	endpoint, _ := cmd.Flags().GetString("s3-endpoint")
+
+	switch {
+	case len(path) > 0 && len(endpoint) > 0:
+		return target, cmdutil.UsageError(cmd, "only one of --target-path and --s3-endpoint may be given")
+	case len(path) > 0:
+		target.Type = v1.BackupTargetPath
+		target.Path = path
+	case len(endpoint) > 0:
+		target.Type = v1.BackupTargetS3
+		target.Endpoint = endpoint
+		target.Bucket, _ = cmd.Flags().GetString("s3-bucket")
+		target.CredentialsSecret, _ = cmd.Flags().GetString("s3-secret")
+		target.Secure, _ = cmd.Flags().GetBool("s3-secure")
+		if len(target.Bucket) == 0 {
+			return target, cmdutil.UsageError(cmd, "--s3-bucket is required with --s3-endpoint")
+		}
+	default:
+		return target, cmdutil.UsageError(cmd, "one of --target-path or --s3-endpoint is required")
+	}
+
+	return target, nil
+}
+
+func RunBackupCreate(cmd *cobra.Command, args []string) error {
+
+	if len(args) != 1 {
+		return cmdutil.UsageError(cmd, "args should be SITEPOD only")
+	}
+
+	target, err := backupTargetFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	websites, _ := cmd.Flags().GetStringSlice("website")
+	every, _ := cmd.Flags().GetInt("every")
+	retain, _ := cmd.Flags().GetInt("retain")
+
+	client := newClient(cmd)
+
+	sitepod, err := findSitepod(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	if every > 0 {
+		schedule := client.BackupSchedules().NewEmpty()
+		schedule.GenerateName = sitepod.Name + "-backup-"
+		schedule.Labels["sitepod"] = string(sitepod.UID)
+		schedule.Spec.Websites = websites
+		schedule.Spec.Target = target
+		schedule.Spec.IntervalMinutes = every
+		schedule.Spec.Retention = retain
+		schedule = client.BackupSchedules().Add(schedule)
+		fmt.Printf("Created backup schedule %s\n", schedule.Name)
+		return nil
+	}
+
+	b := client.Backups().NewEmpty()
+	b.GenerateName = sitepod.Name + "-"
+	b.Labels["sitepod"] = string(sitepod.UID)
+	b.Spec.Websites = websites
+	b.Spec.Target = target
+	b = client.Backups().Add(b)
+	fmt.Printf("Created backup %s\n", b.Name)
+	return nil
+}
+
+func RunBackupList(cmd *cobra.Command, args []string) error {
+
+	if len(args) > 1 {
+		return cmdutil.UsageError(cmd, "args should be SITEPOD or nothing")
+	}
+
+	client := newClient(cmd)
+
+	selector := labels.Everything()
+	if len(args) == 1 {
+		sitepod, err := findSitepod(client, args[0])
+		if err != nil {
+			return err
+		}
+		selector = labels.SelectorFromSet(labels.Set{"sitepod": string(sitepod.UID)})
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSCHEDULE\tCOMPLETED\tARCHIVE")
+	for _, b := range client.Backups().FetchList(selector) {
+		completed := "-"
+		if b.Status.Completed {
+			completed = b.Status.CompletedAt.Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", b.Name, b.Labels["backupschedule"], completed, b.Status.Archive)
+	}
+	return w.Flush()
+}
+
+func RunBackupRestore(cmd *cobra.Command, args []string) error {
+
+	if len(args) != 1 {
+		return cmdutil.UsageError(cmd, "args should be BACKUP only")
+	}
+
+	client := newClient(cmd)
+
+	var backup *v1.Backup
+	for _, b := range client.Backups().FetchList(labels.Everything()) {
+		if b.Name == args[0] {
+			backup = b
+			break
+		}
+	}
+
+	if backup == nil {
+		return fmt.Errorf("backup %s not found", args[0])
+	}
+
+	if !backup.Status.Completed {
+		return fmt.Errorf("backup %s has not completed", backup.Name)
+	}
+
+	restore := client.Restores().NewEmpty()
+	restore.GenerateName = backup.Name + "-restore-"
+	restore.Labels["sitepod"] = backup.Labels["sitepod"]
+	restore.Spec.Backup = backup.Name
+	restore = client.Restores().Add(restore)
+	fmt.Printf("Created restore %s\n", restore.Name)
+	return nil
+}
+
+func init() {
+	RootCmd.AddCommand(backupCmd)
+	backupCmd.AddCommand(backupCreateCmd)
+	backupCmd.AddCommand(backupListCmd)
+	backupCmd.AddCommand(backupRestoreCmd)
+
+	backupCreateCmd.Flags().StringSlice("website", []string{}, "website to include, may be repeated (default all of /home)")
+	backupCreateCmd.Flags().String("target-path", "", "local or NFS directory on the controller manager")
+	backupCreateCmd.Flags().String("s3-endpoint", "", "S3 compatible endpoint e.g. minio.default:9000")
+	backupCreateCmd.Flags().String("s3-bucket", "", "bucket on the S3 endpoint")
+	backupCreateCmd.Flags().String("s3-secret", "", "secret holding accessKey and secretKey for the S3 endpoint")
+	backupCreateCmd.Flags().Bool("s3-secure", false, "use https for the S3 endpoint")
+	backupCreateCmd.Flags().Int("every", 0, "schedule a backup every N minutes instead of once")
+	backupCreateCmd.Flags().Int("retain", v1.DefaultBackupRetention, "completed scheduled backups to keep")
+}
@@ -0,0 +1,137 @@
+// Copyright © 2016 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"sitepod.io/sitepod/pkg/bundle"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export SITEPOD -o BUNDLE",
+	Short: "Export a sitepod as a single portable bundle",
+	Long: `Export a sitepod as a single portable bundle.
+
+The bundle holds the sitepod, its system users (with hashed passwords), app
+components (with generated config files) and websites, and with --data the
+home volume.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdutil.CheckErr(RunExport(cmd, args))
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import BUNDLE",
+	Short: "Import a sitepod bundle as a new sitepod",
+	Long: `Import a sitepod bundle as a new sitepod.
+
+All resources get new UIDs and file UIDs are allocated from the cluster so the
+bundle may be imported into another cluster, or the same one under a new name.
+The new sitepod needs its own volume claims (--volume-claim), claims used by an
+existing sitepod are refused.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdutil.CheckErr(RunImport(cmd, args))
+	},
+}
+
+func RunExport(cmd *cobra.Command, args []string) error {
+
+	if len(args) != 1 {
+		return cmdutil.UsageError(cmd, "args should be SITEPOD only")
+	}
+
+	output, _ := cmd.Flags().GetString("output")
+	if len(output) == 0 {
+		return cmdutil.UsageError(cmd, "-o BUNDLE is required")
+	}
+
+	includeData, _ := cmd.Flags().GetBool("data")
+
+	client := newClient(cmd)
+
+	sitepod, err := findSitepod(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "-" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return bundle.Export(client, sitepod, includeData, w)
+}
+
+func RunImport(cmd *cobra.Command, args []string) error {
+
+	if len(args) != 1 {
+		return cmdutil.UsageError(cmd, "args should be BUNDLE only")
+	}
+
+	opts := bundle.ImportOptions{}
+	opts.Name, _ = cmd.Flags().GetString("name")
+	opts.VolumeClaims, _ = cmd.Flags().GetStringSlice("volume-claim")
+	if len(opts.VolumeClaims) == 0 {
+		return cmdutil.UsageError(cmd, "--volume-claim is required for the imported sitepod")
+	}
+	opts.Timeout, _ = cmd.Flags().GetDuration("timeout")
+
+	var r io.Reader = os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	client := newClient(cmd)
+
+	if len(opts.Name) > 0 {
+		if _, err := findSitepod(client, opts.Name); err == nil {
+			return fmt.Errorf("sitepod %s already exists", opts.Name)
+		}
+	}
+
+	sitepod, err := bundle.Import(client, r, opts)
+	if sitepod != nil {
+		fmt.Printf("Imported sitepod %s\n", sitepod.Name)
+	}
+	return err
+}
+
+func init() {
+	RootCmd.AddCommand(exportCmd)
+	RootCmd.AddCommand(importCmd)
+
+	exportCmd.Flags().StringP("output", "o", "", "bundle file to write, - for stdout")
+	exportCmd.Flags().Bool("data", false, "include home volume data")
+
+	importCmd.Flags().String("name", "", "name of the new sitepod (default the exported name)")
+	importCmd.Flags().StringSlice("volume-claim", []string{}, "volume claim for the new sitepod (required), may be repeated")
+	importCmd.Flags().Duration("timeout", 10*time.Minute, "how long to wait for the new sitepod before restoring data")
+}
@@ -0,0 +1,47 @@
+// Copyright © 2016 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kubernetes/pkg/labels"
+	"sitepod.io/sitepod/pkg/api/v1"
+	"sitepod.io/sitepod/pkg/client"
+	"sitepod.io/sitepod/pkg/system"
+)
+
+// newClient builds a client for the api server given by the global flags, the
+// informers are not started so lookups go direct to the api server
+func newClient(cmd *cobra.Command) *client.Client {
+
+	config := &system.SimpleConfig{
+		ApiServer: cmd.Flag("apiserver").Value.String(),
+		Namespace: cmd.Flag("namespace").Value.String(),
+		Cluster:   cmd.Flag("cluster").Value.String(),
+	}
+
+	return system.NewSimpleSystem(config).GetClient()
+}
+
+func findSitepod(c *client.Client, name string) (*v1.Sitepod, error) {
+	for _, sitepod := range c.Sitepods().FetchList(labels.Everything()) {
+		if sitepod.Name == name {
+			return sitepod, nil
+		}
+	}
+	return nil, fmt.Errorf("sitepod %s not found", name)
+}
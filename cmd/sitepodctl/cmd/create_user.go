@@ -17,7 +17,7 @@ package cmd
 import (
 	"github.com/spf13/cobra"
 	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
-	"sitepod.io/sitepod/pkg/system"
+	"sitepod.io/sitepod/pkg/api/v1"
 	"sitepod.io/sitepod/pkg/util"
 )
 
@@ -44,15 +44,18 @@ func RunCreateUser(cmd *cobra.Command, args []string) error {
 	email := args[0]
 	password := args[1]
 
-	config := &system.SimpleConfig{"http://localhost:9080", "default"}
-
-	ss := system.NewSimpleSystem(config)
-	client := ss.GetClient()
+	client := newClient(cmd)
 
 	sitepodUser := client.SitepodUsers().NewEmpty()
 	sitepodUser.Name = "sitepod-user-" + util.GetMD5Hash(email)
 	sitepodUser.Spec.Email = email
-	sitepodUser.Annotations["sitepod.io/plain-text-password"] = password
+	if err := sitepodUser.SetPassword(password); err != nil {
+		return err
+	}
+	sitepodUser.Status.Status = v1.SitepodUserPendingVerification
+	if verified, _ := cmd.Flags().GetBool("verified"); verified {
+		sitepodUser.Status.Status = v1.SitepodUserActive
+	}
 
 	client.SitepodUsers().Add(sitepodUser)
 	return nil
@@ -69,5 +72,5 @@ func init() {
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
-
+	createuserCmd.Flags().Bool("verified", false, "mark the email verified, otherwise the user verifies it from the panel")
 }
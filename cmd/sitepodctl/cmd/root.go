@@ -20,6 +20,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"sitepod.io/sitepod/pkg/api/v1"
 )
 
 var cfgFile string
@@ -56,6 +57,9 @@ func init() {
 	// will be global for your application.
 
 	RootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.sitepodctl.yaml)")
+	RootCmd.PersistentFlags().String("apiserver", "http://localhost:9080", "root URL to api-server e.g. https://127.0.0.1:6443")
+	RootCmd.PersistentFlags().String("namespace", "default", "namespace to operate on")
+	RootCmd.PersistentFlags().String("cluster", v1.DefaultClusterName, "name of the cluster resource to operate on")
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	RootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
@@ -0,0 +1,94 @@
+// Copyright © 2016 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	kerrors "k8s.io/kubernetes/pkg/api/errors"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"sitepod.io/sitepod/pkg/webapi"
+)
+
+var rotateSessionKeyCmd = &cobra.Command{
+	Use:   "rotate-session-key SECRET",
+	Short: "Add a new web api session key to a secret, creating it if needed",
+	Long: `Add a new web api session key to a secret, creating it if needed.
+
+The new key signs sessions once the web api instances are restarted, older keys
+still verify existing sessions. Keys beyond --keep are dropped, oldest first.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdutil.CheckErr(RunRotateSessionKey(cmd, args))
+	},
+}
+
+func RunRotateSessionKey(cmd *cobra.Command, args []string) error {
+
+	if len(args) != 1 {
+		return cmdutil.UsageError(cmd, "args should be SECRET only")
+	}
+
+	keep, _ := cmd.Flags().GetInt("keep")
+	if keep < 1 {
+		return cmdutil.UsageError(cmd, "--keep must be at least 1")
+	}
+
+	client := newClient(cmd)
+
+	secret, err := client.Secrets().FetchByName(args[0])
+	isNew := err != nil && kerrors.IsNotFound(err)
+	if err != nil && !isNew {
+		return err
+	}
+
+	if isNew {
+		secret = client.Secrets().NewEmpty()
+		secret.GenerateName = ""
+		secret.Name = args[0]
+	}
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+
+	keys := []string{webapi.GenerateSessionKey()}
+	for _, line := range strings.Split(string(secret.Data[webapi.SessionKeysSecretKey]), "\n") {
+		if line = strings.TrimSpace(line); len(line) > 0 && !strings.HasPrefix(line, "#") {
+			keys = append(keys, line)
+		}
+	}
+	if len(keys) > keep {
+		keys = keys[:keep]
+	}
+	secret.Data[webapi.SessionKeysSecretKey] = []byte(strings.Join(keys, "\n") + "\n")
+
+	if isNew {
+		_, err = client.Secrets().TryAdd(secret)
+	} else {
+		_, err = client.Secrets().TryUpdate(secret)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Session keys of %s rotated, %d keys kept\n", args[0], len(keys))
+	return nil
+}
+
+func init() {
+	adminCmd.AddCommand(rotateSessionKeyCmd)
+	rotateSessionKeyCmd.Flags().Int("keep", 2, "number of keys to keep including the new one")
+}
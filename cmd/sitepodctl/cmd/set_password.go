@@ -0,0 +1,75 @@
+// Copyright © 2016 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"sitepod.io/sitepod/pkg/util"
+)
+
+var setPasswordCmd = &cobra.Command{
+	Use:   "set-password EMAIL",
+	Short: "Set the password of a sitepod user, read from stdin",
+	Long: `Set the password of a sitepod user, read from stdin.
+
+The password is hashed locally with the default scheme, only the hash is sent to
+the api server`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdutil.CheckErr(RunSetPassword(cmd, args))
+	},
+}
+
+func RunSetPassword(cmd *cobra.Command, args []string) error {
+
+	if len(args) != 1 {
+		return cmdutil.UsageError(cmd, "args should be EMAIL only")
+	}
+
+	email := strings.ToLower(strings.TrimSpace(args[0]))
+
+	client := newClient(cmd)
+
+	sitepodUser, err := client.SitepodUsers().FetchByName("sitepod-user-" + util.GetMD5Hash(email))
+	if err != nil {
+		return fmt.Errorf("sitepod user %s not found: %v", email, err)
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && len(password) == 0 {
+		return err
+	}
+
+	if err = sitepodUser.SetPassword(strings.TrimRight(password, "\r\n")); err != nil {
+		return err
+	}
+
+	if _, err = client.SitepodUsers().TryUpdate(sitepodUser); err != nil {
+		return err
+	}
+
+	fmt.Printf("Password of %s set\n", email)
+	return nil
+}
+
+func init() {
+	adminCmd.AddCommand(setPasswordCmd)
+}
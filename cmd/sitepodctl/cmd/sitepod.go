@@ -0,0 +1,141 @@
+// Copyright © 2016 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"sitepod.io/sitepod/pkg/api/v1"
+	"sitepod.io/sitepod/pkg/util"
+)
+
+var sitepodCmd = &cobra.Command{
+	Use:   "sitepod",
+	Short: "Manage sitepods",
+	Long:  "Manage sitepods",
+}
+
+var sitepodCloneCmd = &cobra.Command{
+	Use:   "clone SRC DST",
+	Short: "Clone a sitepod, its users, app components, websites and home data",
+	Long: `Clone a sitepod, its users, app components, websites and home data.
+
+The destination sitepod needs its own volume claims (--volume-claim), pin these to
+another node to move a sitepod between nodes. With --migrate the source sitepod
+is deleted once the clone has completed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdutil.CheckErr(RunSitepodClone(cmd, args))
+	},
+}
+
+func RunSitepodClone(cmd *cobra.Command, args []string) error {
+
+	if len(args) != 2 {
+		return cmdutil.UsageError(cmd, "args should be SRC and DST only")
+	}
+
+	volumeClaims, _ := cmd.Flags().GetStringSlice("volume-claim")
+	if len(volumeClaims) == 0 {
+		return cmdutil.UsageError(cmd, "--volume-claim is required for the destination sitepod")
+	}
+
+	migrate, _ := cmd.Flags().GetBool("migrate")
+	noData, _ := cmd.Flags().GetBool("no-data")
+
+	client := newClient(cmd)
+
+	source, err := findSitepod(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	if _, err := findSitepod(client, args[1]); err == nil {
+		return fmt.Errorf("sitepod %s already exists", args[1])
+	}
+
+	migration := client.SitepodMigrations().NewEmpty()
+	migration.Labels["sitepod"] = string(source.UID)
+	migration.Spec.Source = source.Name
+	migration.Spec.Destination = args[1]
+	migration.Spec.VolumeClaims = volumeClaims
+	migration.Spec.CopyData = !noData
+	migration.Spec.DeleteSource = migrate
+	migration = client.SitepodMigrations().Add(migration)
+
+	fmt.Printf("Created sitepod migration %s\n", migration.Name)
+	return nil
+}
+
+var sitepodSetOwnerCmd = &cobra.Command{
+	Use:   "set-owner SITEPOD EMAIL",
+	Short: "Make a sitepod user the owner of a sitepod",
+	Long: `Make a sitepod user the owner of a sitepod.
+
+A reseller is made the owner of each sitepod they manage and adds their customers
+as members through the web api. The previous owner loses access unless they are
+also a member.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdutil.CheckErr(RunSitepodSetOwner(cmd, args))
+	},
+}
+
+func RunSitepodSetOwner(cmd *cobra.Command, args []string) error {
+
+	if len(args) != 2 {
+		return cmdutil.UsageError(cmd, "args should be SITEPOD and EMAIL only")
+	}
+
+	client := newClient(cmd)
+
+	sitepod, err := findSitepod(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(args[1]))
+	owner, err := client.SitepodUsers().FetchByName("sitepod-user-" + util.GetMD5Hash(email))
+	if err != nil {
+		return fmt.Errorf("sitepod user %s not found: %v", email, err)
+	}
+
+	sitepod.Spec.Owner = owner.Name
+	members := []v1.SitepodMember{}
+	for _, member := range sitepod.Spec.Members {
+		if member.User != owner.Name {
+			members = append(members, member)
+		}
+	}
+	sitepod.Spec.Members = members
+
+	if _, err = client.Sitepods().TryUpdate(sitepod); err != nil {
+		return err
+	}
+
+	fmt.Printf("Sitepod %s is owned by %s\n", sitepod.Name, email)
+	return nil
+}
+
+func init() {
+	RootCmd.AddCommand(sitepodCmd)
+	sitepodCmd.AddCommand(sitepodCloneCmd)
+	sitepodCmd.AddCommand(sitepodSetOwnerCmd)
+
+	sitepodCloneCmd.Flags().StringSlice("volume-claim", []string{}, "volume claim for the destination sitepod, may be repeated")
+	sitepodCloneCmd.Flags().Bool("migrate", false, "delete the source sitepod once cloned")
+	sitepodCloneCmd.Flags().Bool("no-data", false, "do not copy home data")
+}
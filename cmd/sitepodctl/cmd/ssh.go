@@ -0,0 +1,129 @@
+// Copyright © 2016 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	kerrors "k8s.io/kubernetes/pkg/api/errors"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"k8s.io/kubernetes/pkg/labels"
+	"sitepod.io/sitepod/pkg/specgen"
+)
+
+var sshCmd = &cobra.Command{
+	Use:   "ssh",
+	Short: "Manage the ssh server of a sitepod",
+	Long:  "Manage the ssh server of a sitepod",
+}
+
+var sshRotateHostKeysCmd = &cobra.Command{
+	Use:   "rotate-hostkeys SITEPOD",
+	Short: "Replace the host keys of a sitepod's ssh server and print the new fingerprints",
+	Long: `Replace the host keys of a sitepod's ssh server and print the new fingerprints.
+
+The ssh server is restarted with the new keys, clients will warn of a changed
+host key until their known_hosts is updated with the printed fingerprints`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdutil.CheckErr(RunSSHRotateHostKeys(cmd, args))
+	},
+}
+
+func RunSSHRotateHostKeys(cmd *cobra.Command, args []string) error {
+
+	if len(args) != 1 {
+		return cmdutil.UsageError(cmd, "args should be SITEPOD only")
+	}
+
+	client := newClient(cmd)
+
+	sitepod, err := findSitepod(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "APPCOMPONENT\tTYPE\tFINGERPRINT")
+
+	rotated := 0
+	selector := labels.SelectorFromSet(labels.Set{"sitepod": string(sitepod.UID)})
+	for _, ac := range client.AppComps().FetchList(selector) {
+
+		if ac.Spec.SSH == nil {
+			continue
+		}
+
+		data, err := specgen.GenerateHostKeys()
+		if err != nil {
+			return err
+		}
+
+		secretName := specgen.HostKeysSecretName(ac)
+		secret, err := client.Secrets().FetchByName(secretName)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+
+		if err != nil {
+			// not generated yet, the controller would label it the same way
+			secret = client.Secrets().NewEmpty()
+			secret.GenerateName = ""
+			secret.Name = secretName
+			if secret.Labels == nil {
+				secret.Labels = make(map[string]string)
+			}
+			secret.Labels["sitepod"] = string(sitepod.UID)
+			secret.Labels["appcomponent"] = ac.Name
+			secret.Labels["secret-type"] = specgen.HostKeysSecretType
+			secret.Data = data
+			if _, err = client.Secrets().TryAdd(secret); err != nil {
+				return err
+			}
+		} else {
+			secret.Data = data
+			if _, err = client.Secrets().TryUpdate(secret); err != nil {
+				return err
+			}
+		}
+
+		fingerprints, err := specgen.HostKeyFingerprints(data)
+		if err != nil {
+			return err
+		}
+
+		ac.Status.HostKeyFingerprints = fingerprints
+		if _, err = client.AppComps().TryUpdate(ac); err != nil {
+			return err
+		}
+
+		for _, fingerprint := range fingerprints {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", ac.Name, fingerprint.Type, fingerprint.Fingerprint)
+		}
+		rotated++
+	}
+
+	if rotated == 0 {
+		return fmt.Errorf("sitepod %s has no ssh server", sitepod.Name)
+	}
+	return w.Flush()
+}
+
+func init() {
+	RootCmd.AddCommand(sshCmd)
+	sshCmd.AddCommand(sshRotateHostKeysCmd)
+}
@@ -0,0 +1,182 @@
+// Copyright © 2016 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"k8s.io/kubernetes/pkg/labels"
+	"sitepod.io/sitepod/pkg/api/v1"
+	"sitepod.io/sitepod/pkg/util"
+	"sitepod.io/sitepod/pkg/webapi"
+)
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage web api tokens",
+	Long:  "Manage web api tokens",
+}
+
+var tokenCreateCmd = &cobra.Command{
+	Use:   "create EMAIL",
+	Short: "Create an api token acting as a sitepod user",
+	Long: `Create an api token acting as a sitepod user.
+
+The token is printed once, only a hash of it is stored. Pass it to the web api as
+"Authorization: Bearer TOKEN".`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdutil.CheckErr(RunTokenCreate(cmd, args))
+	},
+}
+
+var tokenListCmd = &cobra.Command{
+	Use:   "list [EMAIL]",
+	Short: "List api tokens, optionally of one sitepod user",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdutil.CheckErr(RunTokenList(cmd, args))
+	},
+}
+
+var tokenRevokeCmd = &cobra.Command{
+	Use:   "revoke NAME",
+	Short: "Revoke an api token by name",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdutil.CheckErr(RunTokenRevoke(cmd, args))
+	},
+}
+
+func RunTokenCreate(cmd *cobra.Command, args []string) error {
+
+	if len(args) != 1 {
+		return cmdutil.UsageError(cmd, "args should be EMAIL only")
+	}
+
+	tokenType, _ := cmd.Flags().GetString("type")
+	scopes, _ := cmd.Flags().GetStringSlice("scope")
+	description, _ := cmd.Flags().GetString("description")
+	expiresIn, _ := cmd.Flags().GetDuration("expires-in")
+
+	client := newClient(cmd)
+
+	email := strings.ToLower(strings.TrimSpace(args[0]))
+	user, err := client.SitepodUsers().FetchByName("sitepod-user-" + util.GetMD5Hash(email))
+	if err != nil {
+		return fmt.Errorf("sitepod user %s not found: %v", email, err)
+	}
+
+	name, secret, token := webapi.GenerateAPIToken()
+
+	apiToken := client.ApiTokens().NewEmpty()
+	apiToken.GenerateName = ""
+	apiToken.Name = name
+	apiToken.Spec.User = user.Name
+	apiToken.Spec.Type = tokenType
+	apiToken.Spec.Description = description
+	apiToken.Spec.Scopes = scopes
+	apiToken.Spec.SecretHash = webapi.HashAPITokenSecret(secret)
+	if expiresIn > 0 {
+		expiresAt := unversioned.NewTime(time.Now().Add(expiresIn))
+		apiToken.Spec.ExpiresAt = &expiresAt
+	}
+
+	if err = apiToken.Validate(); err != nil {
+		return cmdutil.UsageError(cmd, err.Error())
+	}
+
+	if _, err = client.ApiTokens().TryAdd(apiToken); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Created api token %s for %s, it will not be shown again\n", name, email)
+	fmt.Println(token)
+	return nil
+}
+
+func RunTokenList(cmd *cobra.Command, args []string) error {
+
+	if len(args) > 1 {
+		return cmdutil.UsageError(cmd, "args should be at most EMAIL")
+	}
+
+	client := newClient(cmd)
+
+	emails := make(map[string]string)
+	for _, user := range client.SitepodUsers().FetchList(labels.Everything()) {
+		emails[user.Name] = user.Spec.Email
+	}
+
+	var onlyUser string
+	if len(args) == 1 {
+		onlyUser = "sitepod-user-" + util.GetMD5Hash(strings.ToLower(strings.TrimSpace(args[0])))
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tUSER\tTYPE\tSCOPES\tEXPIRES\tDESCRIPTION")
+	for _, token := range client.ApiTokens().FetchList(labels.Everything()) {
+		if len(onlyUser) > 0 && token.Spec.User != onlyUser {
+			continue
+		}
+		expires := "never"
+		if token.Spec.ExpiresAt != nil {
+			expires = token.Spec.ExpiresAt.Format(time.RFC3339)
+			if token.IsExpired(time.Now()) {
+				expires += " (expired)"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", token.Name, emails[token.Spec.User], token.Spec.Type,
+			strings.Join(token.Spec.Scopes, ","), expires, token.Spec.Description)
+	}
+	return w.Flush()
+}
+
+func RunTokenRevoke(cmd *cobra.Command, args []string) error {
+
+	if len(args) != 1 {
+		return cmdutil.UsageError(cmd, "args should be NAME only")
+	}
+
+	client := newClient(cmd)
+
+	token, err := client.ApiTokens().FetchByName(args[0])
+	if err != nil {
+		return fmt.Errorf("api token %s not found: %v", args[0], err)
+	}
+
+	if err = client.ApiTokens().TryDelete(token); err != nil {
+		return err
+	}
+
+	fmt.Printf("Revoked api token %s\n", token.Name)
+	return nil
+}
+
+func init() {
+	RootCmd.AddCommand(tokenCmd)
+	tokenCmd.AddCommand(tokenCreateCmd)
+	tokenCmd.AddCommand(tokenListCmd)
+	tokenCmd.AddCommand(tokenRevokeCmd)
+
+	tokenCreateCmd.Flags().String("type", v1.ApiTokenPersonal, "personal or service")
+	tokenCreateCmd.Flags().StringSlice("scope", []string{v1.ScopeRead}, "read, write or billing, may be repeated")
+	tokenCreateCmd.Flags().String("description", "", "what the token is used for")
+	tokenCreateCmd.Flags().Duration("expires-in", 90*24*time.Hour, "lifetime of the token, 0 for no expiry")
+}
@@ -0,0 +1,67 @@
+// Copyright © 2016 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"sitepod.io/sitepod/pkg/util"
+)
+
+var disableTwoFactorCmd = &cobra.Command{
+	Use:   "disable-2fa EMAIL",
+	Short: "Disable two-factor authentication of a sitepod user who lost their authenticator",
+	Long: `Disable two-factor authentication of a sitepod user who lost their authenticator
+and recovery codes. The user logs in with the password alone and may enrol again.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdutil.CheckErr(RunDisableTwoFactor(cmd, args))
+	},
+}
+
+func RunDisableTwoFactor(cmd *cobra.Command, args []string) error {
+
+	if len(args) != 1 {
+		return cmdutil.UsageError(cmd, "args should be EMAIL only")
+	}
+
+	email := strings.ToLower(strings.TrimSpace(args[0]))
+
+	client := newClient(cmd)
+
+	sitepodUser, err := client.SitepodUsers().FetchByName("sitepod-user-" + util.GetMD5Hash(email))
+	if err != nil {
+		return fmt.Errorf("sitepod user %s not found: %v", email, err)
+	}
+
+	if !sitepodUser.TwoFactorEnabled() && len(sitepodUser.Spec.TwoFactor.PendingTOTPSecret) == 0 {
+		fmt.Printf("Two-factor authentication of %s is not enabled\n", email)
+		return nil
+	}
+
+	sitepodUser.DisableTwoFactor()
+	if _, err = client.SitepodUsers().TryUpdate(sitepodUser); err != nil {
+		return err
+	}
+
+	fmt.Printf("Two-factor authentication of %s disabled\n", email)
+	return nil
+}
+
+func init() {
+	adminCmd.AddCommand(disableTwoFactorCmd)
+}
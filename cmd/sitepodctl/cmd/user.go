@@ -0,0 +1,118 @@
+// Copyright © 2016 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"k8s.io/kubernetes/pkg/labels"
+	"sitepod.io/sitepod/pkg/api/v1"
+	"sitepod.io/sitepod/pkg/util"
+)
+
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Manage the system users of a sitepod",
+	Long:  "Manage the system users of a sitepod",
+}
+
+var userSetPasswordCmd = &cobra.Command{
+	Use:   "set-password SITEPOD USERNAME",
+	Short: "Set the password of a system user, read from stdin",
+	Long: `Set the password of a system user, read from stdin.
+
+The password is hashed locally with SHA-512 crypt, only the hash is sent to the
+api server`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdutil.CheckErr(RunUserSetPassword(cmd, args))
+	},
+}
+
+func RunUserSetPassword(cmd *cobra.Command, args []string) error {
+
+	if len(args) != 2 {
+		return cmdutil.UsageError(cmd, "args should be SITEPOD and USERNAME only")
+	}
+
+	rounds, _ := cmd.Flags().GetInt("rounds")
+
+	client := newClient(cmd)
+
+	sitepod, err := findSitepod(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	var user *v1.SystemUser
+	selector := labels.SelectorFromSet(labels.Set{"sitepod": string(sitepod.UID)})
+	for _, candidate := range client.SystemUsers().FetchList(selector) {
+		if candidate.GetUsername() == args[1] {
+			user = candidate
+			break
+		}
+	}
+
+	if user == nil {
+		return fmt.Errorf("user %s not found in sitepod %s", args[1], sitepod.Name)
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && len(password) == 0 {
+		return err
+	}
+	password = strings.TrimRight(password, "\r\n")
+	if len(password) == 0 {
+		return fmt.Errorf("password must not be empty")
+	}
+
+	salt := util.RandomSalt(util.Sha512CryptMaxSalt)
+	if err = util.CheckSha512CryptParams(salt, rounds); err != nil {
+		return cmdutil.UsageError(cmd, err.Error())
+	}
+	hash, err := util.Sha512CryptRounds(password, salt, rounds)
+	if err != nil {
+		return err
+	}
+
+	changedAt := unversioned.Now()
+	user.Spec.Password = v1.HashedPassword{
+		Scheme:       util.SchemeSha512Crypt,
+		Salt:         salt,
+		CombinedHash: hash,
+		Rounds:       rounds,
+		ChangedAt:    &changedAt,
+	}
+
+	if _, err = client.SystemUsers().TryUpdate(user); err != nil {
+		return err
+	}
+
+	fmt.Printf("Password of %s set\n", user.GetUsername())
+	return nil
+}
+
+func init() {
+	RootCmd.AddCommand(userCmd)
+	userCmd.AddCommand(userSetPasswordCmd)
+
+	userSetPasswordCmd.Flags().Int("rounds", 100000, "SHA-512 crypt rounds")
+}
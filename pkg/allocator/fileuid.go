@@ -0,0 +1,156 @@
+package allocator
+
+// File UIDs are allocated from the Cluster resource. More than one worker (or
+// sitepodctl) may allocate at once so every change is a compare and swap on the
+// cluster resourceVersion, retried from a fresh read on conflict.
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	kerrors "k8s.io/kubernetes/pkg/api/errors"
+	"sitepod.io/sitepod/pkg/api/v1"
+	cc "sitepod.io/sitepod/pkg/client"
+)
+
+var (
+	MaxConflictRetries = 10
+	ConflictRetryDelay = 100 * time.Millisecond
+)
+
+type FileUIDAllocator struct {
+	client      *cc.Client
+	clusterName string
+}
+
+func NewFileUIDAllocator(client *cc.Client, clusterName string) *FileUIDAllocator {
+	return &FileUIDAllocator{client, clusterName}
+}
+
+// Allocate reserves count file UIDs, they are persisted on the cluster before return
+func (a *FileUIDAllocator) Allocate(count int) ([]int, error) {
+
+	var uids []int
+	err := a.update(func(cluster *v1.Cluster) {
+		uids = []int{}
+		for i := 0; i < count; i++ {
+			uids = append(uids, cluster.NextFileUID())
+		}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	glog.Infof("Allocated file uids %v from cluster %s", uids, a.clusterName)
+	return uids, nil
+}
+
+// Release returns a file UID to the free list, see v1.Cluster.ReleaseFileUID
+func (a *FileUIDAllocator) Release(uid int, quarantine bool) error {
+
+	err := a.update(func(cluster *v1.Cluster) {
+		cluster.ReleaseFileUID(uid, quarantine)
+	})
+
+	if err == nil {
+		glog.Infof("Released file uid %d to cluster %s (quarantine: %t)", uid, a.clusterName, quarantine)
+	}
+	return err
+}
+
+// Reconcile repairs the cluster against the file UIDs actually assigned to system users,
+// the counter is moved past the highest assigned and assigned UIDs are removed from the
+// free list. File UIDs assigned to more than one user are returned, these cannot be
+// repaired automatically as files on disk are already owned by them.
+func (a *FileUIDAllocator) Reconcile(users []*v1.SystemUser) (map[int][]string, error) {
+
+	duplicates := FindDuplicateFileUIDs(users)
+
+	assigned := make(map[int]bool)
+	highest := 0
+	for _, user := range users {
+		uid := user.Status.AssignedFileUID
+		if uid == 0 {
+			continue
+		}
+		assigned[uid] = true
+		if uid > highest {
+			highest = uid
+		}
+	}
+
+	err := a.update(func(cluster *v1.Cluster) {
+		if cluster.Spec.FileUIDCount < highest {
+			glog.Warningf("Cluster %s file uid count %d behind assigned uid %d, advancing",
+				cluster.Name, cluster.Spec.FileUIDCount, highest)
+			cluster.Spec.FileUIDCount = highest
+		}
+		released := []v1.ReleasedFileUID{}
+		for _, r := range cluster.Spec.ReleasedFileUIDs {
+			if assigned[r.UID] {
+				glog.Warningf("Cluster %s released file uid %d is assigned, removing from free list",
+					cluster.Name, r.UID)
+				continue
+			}
+			released = append(released, r)
+		}
+		cluster.Spec.ReleasedFileUIDs = released
+	})
+
+	return duplicates, err
+}
+
+// FindDuplicateFileUIDs maps each file UID assigned to more than one system user to
+// the names of those users
+func FindDuplicateFileUIDs(users []*v1.SystemUser) map[int][]string {
+
+	byUID := make(map[int][]string)
+	for _, user := range users {
+		if uid := user.Status.AssignedFileUID; uid != 0 {
+			byUID[uid] = append(byUID[uid], user.Name)
+		}
+	}
+
+	duplicates := make(map[int][]string)
+	for uid, names := range byUID {
+		if len(names) > 1 {
+			sort.Strings(names)
+			duplicates[uid] = names
+		}
+	}
+	return duplicates
+}
+
+func (a *FileUIDAllocator) update(fn func(*v1.Cluster)) error {
+
+	for attempt := 0; attempt < MaxConflictRetries; attempt++ {
+
+		cluster, err := a.client.Clusters().FetchByName(a.clusterName)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				return fmt.Errorf("cluster %s does not exist", a.clusterName)
+			}
+			return err
+		}
+
+		fn(cluster)
+
+		_, err = a.client.Clusters().TryUpdate(cluster)
+		if err == nil {
+			return nil
+		}
+
+		if !kerrors.IsConflict(err) {
+			return err
+		}
+
+		glog.Infof("Conflict updating cluster %s (attempt %d), retrying", a.clusterName, attempt+1)
+		time.Sleep(ConflictRetryDelay + time.Duration(rand.Int63n(int64(ConflictRetryDelay))))
+	}
+
+	return fmt.Errorf("gave up updating cluster %s after %d conflicts", a.clusterName, MaxConflictRetries)
+}
@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/kubernetes/pkg/api/meta"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/api/v1"
+)
+
+const (
+	// ApiTokenPersonal is a token a person uses from scripts in place of logging in
+	ApiTokenPersonal = "personal"
+	// ApiTokenService is a token of a system such as CI or billing, acting as the user
+	// that created it
+	ApiTokenService = "service"
+
+	ScopeRead    = "read"
+	ScopeWrite   = "write"
+	ScopeBilling = "billing"
+)
+
+// ApiToken authenticates web api calls as a sitepod user, limited to its scopes. Only a
+// hash of the secret is kept, the token itself is shown once on creation.
+type ApiToken struct {
+	unversioned.TypeMeta `json:",inline"`
+	ObjectMeta           `json:"metadata,omitempty"`
+	Spec                 ApiTokenSpec `json:"spec"`
+}
+
+type ApiTokenSpec struct {
+	// User is the name of the sitepod user the token acts as
+	User        string   `json:"user"`
+	Type        string   `json:"type,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Scopes      []string `json:"scopes"`
+	// SecretHash is the hex sha256 of the token secret
+	SecretHash string            `json:"secretHash"`
+	ExpiresAt  *unversioned.Time `json:"expiresAt,omitempty"`
+}
+
+func (t *ApiToken) SetDefaults() {
+	t.ObjectMeta.Labels = make(map[string]string)
+	t.ObjectMeta.Annotations = make(map[string]string)
+}
+
+func (t *ApiToken) IsExpired(now time.Time) bool {
+	return t.Spec.ExpiresAt != nil && !now.Before(t.Spec.ExpiresAt.Time)
+}
+
+func (t *ApiToken) HasScope(scope string) bool {
+	for _, s := range t.Spec.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidScope(scope string) bool {
+	return scope == ScopeRead || scope == ScopeWrite || scope == ScopeBilling
+}
+
+func (t *ApiToken) Validate() error {
+	if len(t.Spec.User) == 0 {
+		return fmt.Errorf("Token has no user")
+	}
+	if t.Spec.Type != ApiTokenPersonal && t.Spec.Type != ApiTokenService {
+		return fmt.Errorf("Unknown token type %s", t.Spec.Type)
+	}
+	if len(t.Spec.Scopes) == 0 {
+		return fmt.Errorf("Token needs at least one scope")
+	}
+	for _, scope := range t.Spec.Scopes {
+		if !IsValidScope(scope) {
+			return fmt.Errorf("Unknown scope %s", scope)
+		}
+	}
+	if len(t.Spec.SecretHash) == 0 {
+		return fmt.Errorf("Token has no secret")
+	}
+	return nil
+}
+
+func (t *ApiToken) GetObjectMeta() meta.Object {
+	om := v1.ObjectMeta(t.ObjectMeta)
+	return &om
+}
+
+func (t *ApiToken) GetObjectKind() unversioned.ObjectKind {
+	return &t.TypeMeta
+}
+
+type ApiTokenList struct {
+	unversioned.TypeMeta `json:",inline"`
+	ListMeta             `json:"metadata,omitempty"`
+	Items                []ApiToken `json:"items"`
+}
+
+func (t *ApiTokenList) GetObjectKind() unversioned.ObjectKind {
+	return &t.TypeMeta
+}
+
+func (t *ApiTokenList) GetListMeta() unversioned.List {
+	lm := unversioned.ListMeta(t.ListMeta)
+	return &lm
+}
@@ -39,16 +39,39 @@ type AppComponentSpec struct {
 	MountHome        bool                     `json:"mountHome,omitempty"`
 	MountEtcs        bool                     `json:"mountEtcs,omitempty"`
 	ConfigFiles      []AppComponentConfigFile `json:"configFiles,omitempty"`
+	SSH              *SSHOptions              `json:"ssh,omitempty"`
+}
+
+// SSHOptions drive the sshd_config of an ssh app component
+type SSHOptions struct {
+	// ChrootHome confines each user to their home directory
+	ChrootHome bool `json:"chrootHome,omitempty"`
+	// SFTPOnly denies shell access
+	SFTPOnly                bool `json:"sftpOnly,omitempty"`
+	PasswordAuthentication  bool `json:"passwordAuthentication,omitempty"`
+	PublicKeyAuthentication bool `json:"publicKeyAuthentication,omitempty"`
 }
 
 type AppComponentStatus struct {
 	//TODO figure out high level conditions
+	// HostKeyFingerprints are published for ssh app components so users can verify the server
+	HostKeyFingerprints []SSHHostKeyFingerprint `json:"hostKeyFingerprints,omitempty"`
+}
+
+type SSHHostKeyFingerprint struct {
+	Type        string `json:"type"`
+	Fingerprint string `json:"fingerprint"`
 }
 
 func (s *Appcomponent) GetObjectKind() unversioned.ObjectKind {
 	return &s.TypeMeta
 }
 
+func (s *Appcomponent) SetDefaults() {
+	s.ObjectMeta.Labels = make(map[string]string)
+	s.ObjectMeta.Annotations = make(map[string]string)
+}
+
 func (s *Appcomponent) GetObjectMeta() meta.Object {
 	om := v1.ObjectMeta(s.ObjectMeta)
 	return &om
@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"k8s.io/kubernetes/pkg/api/meta"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/api/v1"
+)
+
+const (
+	BackupTargetPath = "path"
+	BackupTargetS3   = "s3"
+)
+
+// BackupTarget describes where backup archives are written to and read from.
+// A path target is a local or NFS directory mounted on the controller manager,
+// an s3 target any S3 compatible endpoint (e.g. minio).
+type BackupTarget struct {
+	Type              string `json:"type,omitempty"`
+	Path              string `json:"path,omitempty"`
+	Endpoint          string `json:"endpoint,omitempty"`
+	Bucket            string `json:"bucket,omitempty"`
+	Secure            bool   `json:"secure,omitempty"`
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+type Backup struct {
+	unversioned.TypeMeta `json:",inline"`
+	ObjectMeta           `json:"metadata,omitempty"`
+	Spec                 BackupSpec   `json:"spec"`
+	Status               BackupStatus `json:"status"`
+}
+
+func (b *Backup) SetDefaults() {
+	b.ObjectMeta.Labels = make(map[string]string)
+	b.ObjectMeta.Annotations = make(map[string]string)
+}
+
+type BackupSpec struct {
+	// Websites to include, by website name. Empty backs up all of /home
+	Websites []string     `json:"websites,omitempty"`
+	Target   BackupTarget `json:"target"`
+}
+
+type BackupStatus struct {
+	Archive     string           `json:"archive,omitempty"`
+	Completed   bool             `json:"completed,omitempty"`
+	CompletedAt unversioned.Time `json:"completedAt,omitempty"`
+	// Pruning is set before the archive is deleted, the backup can no longer be restored
+	// and is deleted once the archive is gone
+	Pruning bool `json:"pruning,omitempty"`
+}
+
+func (b *Backup) SetCondition(condition string, val bool) {
+	if condition == "Completed" {
+		b.Status.Completed = val
+		b.Status.CompletedAt = unversioned.Now()
+	}
+}
+
+func (s *Backup) GetObjectKind() unversioned.ObjectKind {
+	return &s.TypeMeta
+}
+
+func (s *Backup) GetObjectMeta() meta.Object {
+	om := v1.ObjectMeta(s.ObjectMeta)
+	return &om
+}
+
+type BackupList struct {
+	unversioned.TypeMeta `json:",inline"`
+	ListMeta             `json:"metadata,omitempty"`
+	Items                []Backup `json:"items"`
+}
+
+func (s *BackupList) GetObjectKind() unversioned.ObjectKind {
+	return &s.TypeMeta
+}
+
+func (s *BackupList) GetListMeta() unversioned.List {
+	lm := unversioned.ListMeta(s.ListMeta)
+	return &lm
+}
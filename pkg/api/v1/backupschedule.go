@@ -0,0 +1,62 @@
+package v1
+
+import (
+	"k8s.io/kubernetes/pkg/api/meta"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/api/v1"
+)
+
+const (
+	DefaultBackupRetention = 7
+)
+
+type Backupschedule struct {
+	unversioned.TypeMeta `json:",inline"`
+	ObjectMeta           `json:"metadata,omitempty"`
+	Spec                 BackupScheduleSpec   `json:"spec"`
+	Status               BackupScheduleStatus `json:"status"`
+}
+
+func (b *Backupschedule) SetDefaults() {
+	b.ObjectMeta.Labels = make(map[string]string)
+	b.ObjectMeta.Annotations = make(map[string]string)
+	b.Spec.IntervalMinutes = 24 * 60
+	b.Spec.Retention = DefaultBackupRetention
+}
+
+type BackupScheduleSpec struct {
+	Websites        []string     `json:"websites,omitempty"`
+	Target          BackupTarget `json:"target"`
+	IntervalMinutes int          `json:"intervalMinutes"`
+	// Retention is the number of completed backups kept, older ones are pruned
+	Retention int `json:"retention"`
+}
+
+type BackupScheduleStatus struct {
+	LastBackup string           `json:"lastBackup,omitempty"`
+	LastRunAt  unversioned.Time `json:"lastRunAt,omitempty"`
+}
+
+func (s *Backupschedule) GetObjectKind() unversioned.ObjectKind {
+	return &s.TypeMeta
+}
+
+func (s *Backupschedule) GetObjectMeta() meta.Object {
+	om := v1.ObjectMeta(s.ObjectMeta)
+	return &om
+}
+
+type BackupscheduleList struct {
+	unversioned.TypeMeta `json:",inline"`
+	ListMeta             `json:"metadata,omitempty"`
+	Items                []Backupschedule `json:"items"`
+}
+
+func (s *BackupscheduleList) GetObjectKind() unversioned.ObjectKind {
+	return &s.TypeMeta
+}
+
+func (s *BackupscheduleList) GetListMeta() unversioned.List {
+	lm := unversioned.ListMeta(s.ListMeta)
+	return &lm
+}
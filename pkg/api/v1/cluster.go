@@ -1,6 +1,10 @@
 package v1
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"k8s.io/kubernetes/pkg/api/meta"
 	"k8s.io/kubernetes/pkg/api/unversioned"
 	"k8s.io/kubernetes/pkg/api/v1"
@@ -16,22 +20,86 @@ type Cluster struct {
 func (c *Cluster) SetDefaults() {
 	c.ObjectMeta.Labels = make(map[string]string)
 	c.ObjectMeta.Annotations = make(map[string]string)
-	c.Spec.FileUIDCount = 2001
+	c.Spec.FileUIDCount = MinFileUID
+	c.Spec.FileUIDQuarantineHours = DefaultFileUIDQuarantineHours
+}
+
+// Validate checks the cluster is safe to allocate from
+func (c *Cluster) Validate() error {
+
+	if len(c.Name) == 0 {
+		return errors.New("Cluster name is required")
+	}
+
+	if c.Spec.FileUIDCount < MinFileUID {
+		return fmt.Errorf("Cluster %s file uid count %d must be at least %d", c.Name, c.Spec.FileUIDCount, MinFileUID)
+	}
+
+	if c.Spec.FileUIDQuarantineHours < 0 {
+		return fmt.Errorf("Cluster %s file uid quarantine must not be negative", c.Name)
+	}
+
+	return nil
 }
 
+const (
+	DefaultClusterName = "sitepod-alpha"
+
+	// File UIDs below are reserved for the base image and the shared sitepod user
+	MinFileUID = 2001
+
+	// Released file UIDs are not reused until any files left owned by them are long gone
+	DefaultFileUIDQuarantineHours = 30 * 24
+)
+
 type ClusterSpec struct {
-	DisplayName     string `json:"displayName,omitempty"`
-	Description     string `json:"description,omitempty"`
-	FileUIDCount    int    `json:"fileUidCount"`
-	UseLoadBalancer bool   `json:"useLoadBalancer"`
+	DisplayName            string            `json:"displayName,omitempty"`
+	Description            string            `json:"description,omitempty"`
+	FileUIDCount           int               `json:"fileUidCount"`
+	FileUIDQuarantineHours int               `json:"fileUidQuarantineHours,omitempty"`
+	ReleasedFileUIDs       []ReleasedFileUID `json:"releasedFileUids,omitempty"`
+	UseLoadBalancer        bool              `json:"useLoadBalancer"`
+}
+
+type ReleasedFileUID struct {
+	UID        int              `json:"uid"`
+	ReleasedAt unversioned.Time `json:"releasedAt"`
 }
 
+// NextFileUID takes a released file UID that has served its quarantine, otherwise the
+// next never used one. Only the in memory cluster is changed, the caller must update it
+// with a compare and swap on resourceVersion (see pkg/allocator) before using the UID.
 func (s *Cluster) NextFileUID() int {
-	//NOTE do we need atomic increment here if more than one worker?
+
+	quarantine := time.Duration(s.Spec.FileUIDQuarantineHours) * time.Hour
+	for idx, released := range s.Spec.ReleasedFileUIDs {
+		if time.Since(released.ReleasedAt.Time) >= quarantine {
+			s.Spec.ReleasedFileUIDs = append(s.Spec.ReleasedFileUIDs[:idx], s.Spec.ReleasedFileUIDs[idx+1:]...)
+			return released.UID
+		}
+	}
+
 	s.Spec.FileUIDCount = s.Spec.FileUIDCount + 1
 	return s.Spec.FileUIDCount
 }
 
+// ReleaseFileUID puts the file UID on the free list, with quarantine false it is
+// available immediately (e.g. it was allocated but never assigned)
+func (s *Cluster) ReleaseFileUID(uid int, quarantine bool) {
+
+	for _, released := range s.Spec.ReleasedFileUIDs {
+		if released.UID == uid {
+			return
+		}
+	}
+
+	releasedAt := unversioned.Now()
+	if !quarantine {
+		releasedAt = unversioned.Time{}
+	}
+	s.Spec.ReleasedFileUIDs = append(s.Spec.ReleasedFileUIDs, ReleasedFileUID{uid, releasedAt})
+}
+
 func (s *Cluster) GetObjectMeta() meta.Object {
 	om := v1.ObjectMeta(s.ObjectMeta)
 	return &om
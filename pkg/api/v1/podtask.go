@@ -32,6 +32,23 @@ type PodTaskSpec struct {
 	BehalfType      string   `json:"behalfType,omitempty"`
 	BehalfOf        string   `json:"behalfOf,omitempty"`
 	BehalfCondition string   `json:"behalfCondition,omitempty"`
+	// Optional streams, stdin is read from and stdout written to a backup
+	// target object instead of being captured in status
+	StdinFrom *PodTaskStream `json:"stdinFrom,omitempty"`
+	StdoutTo  *PodTaskStream `json:"stdoutTo,omitempty"`
+	// Optional stdin piped from the stdout of a command on another pod
+	StdinFromPod *PodTaskSource `json:"stdinFromPod,omitempty"`
+}
+
+type PodTaskStream struct {
+	Target BackupTarget `json:"target"`
+	Object string       `json:"object"`
+}
+
+type PodTaskSource struct {
+	PodName       string   `json:"podName"`
+	ContainerName string   `json:"containerName"`
+	Command       []string `json:"command"`
 }
 
 type PodTaskStatus struct {
@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"k8s.io/kubernetes/pkg/api/meta"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/api/v1"
+)
+
+type Restore struct {
+	unversioned.TypeMeta `json:",inline"`
+	ObjectMeta           `json:"metadata,omitempty"`
+	Spec                 RestoreSpec   `json:"spec"`
+	Status               RestoreStatus `json:"status"`
+}
+
+func (r *Restore) SetDefaults() {
+	r.ObjectMeta.Labels = make(map[string]string)
+	r.ObjectMeta.Annotations = make(map[string]string)
+}
+
+type RestoreSpec struct {
+	Backup string `json:"backup"`
+}
+
+type RestoreStatus struct {
+	Completed   bool             `json:"completed,omitempty"`
+	CompletedAt unversioned.Time `json:"completedAt,omitempty"`
+}
+
+func (r *Restore) SetCondition(condition string, val bool) {
+	if condition == "Completed" {
+		r.Status.Completed = val
+		r.Status.CompletedAt = unversioned.Now()
+	}
+}
+
+func (s *Restore) GetObjectKind() unversioned.ObjectKind {
+	return &s.TypeMeta
+}
+
+func (s *Restore) GetObjectMeta() meta.Object {
+	om := v1.ObjectMeta(s.ObjectMeta)
+	return &om
+}
+
+type RestoreList struct {
+	unversioned.TypeMeta `json:",inline"`
+	ListMeta             `json:"metadata,omitempty"`
+	Items                []Restore `json:"items"`
+}
+
+func (s *RestoreList) GetObjectKind() unversioned.ObjectKind {
+	return &s.TypeMeta
+}
+
+func (s *RestoreList) GetListMeta() unversioned.List {
+	lm := unversioned.ListMeta(s.ListMeta)
+	return &lm
+}
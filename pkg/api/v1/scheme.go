@@ -26,6 +26,11 @@ func AddToScheme(s *runtime.Scheme) (string, *unversioned.GroupVersion, *unversi
 	s.AddKnownTypes(internalGV, &SystemUserList{})
 	s.AddKnownTypes(externalGV, &SystemUserList{})
 
+	s.AddKnownTypes(internalGV, &SystemGroup{})
+	s.AddKnownTypes(externalGV, &SystemGroup{})
+	s.AddKnownTypes(internalGV, &SystemGroupList{})
+	s.AddKnownTypes(externalGV, &SystemGroupList{})
+
 	s.AddKnownTypes(internalGV, &SitepodUser{})
 	s.AddKnownTypes(externalGV, &SitepodUser{})
 	s.AddKnownTypes(internalGV, &SitepodUserList{})
@@ -55,6 +60,36 @@ func AddToScheme(s *runtime.Scheme) (string, *unversioned.GroupVersion, *unversi
 	s.AddKnownTypes(externalGV, &Podtask{})
 	s.AddKnownTypes(internalGV, &PodtaskList{})
 	s.AddKnownTypes(externalGV, &PodtaskList{})
+
+	s.AddKnownTypes(internalGV, &Backup{})
+	s.AddKnownTypes(externalGV, &Backup{})
+	s.AddKnownTypes(internalGV, &BackupList{})
+	s.AddKnownTypes(externalGV, &BackupList{})
+
+	s.AddKnownTypes(internalGV, &Restore{})
+	s.AddKnownTypes(externalGV, &Restore{})
+	s.AddKnownTypes(internalGV, &RestoreList{})
+	s.AddKnownTypes(externalGV, &RestoreList{})
+
+	s.AddKnownTypes(internalGV, &Backupschedule{})
+	s.AddKnownTypes(externalGV, &Backupschedule{})
+	s.AddKnownTypes(internalGV, &BackupscheduleList{})
+	s.AddKnownTypes(externalGV, &BackupscheduleList{})
+
+	s.AddKnownTypes(internalGV, &SitepodMigration{})
+	s.AddKnownTypes(externalGV, &SitepodMigration{})
+	s.AddKnownTypes(internalGV, &SitepodMigrationList{})
+	s.AddKnownTypes(externalGV, &SitepodMigrationList{})
+
+	s.AddKnownTypes(internalGV, &WebSession{})
+	s.AddKnownTypes(externalGV, &WebSession{})
+	s.AddKnownTypes(internalGV, &WebSessionList{})
+	s.AddKnownTypes(externalGV, &WebSessionList{})
+
+	s.AddKnownTypes(internalGV, &ApiToken{})
+	s.AddKnownTypes(externalGV, &ApiToken{})
+	s.AddKnownTypes(internalGV, &ApiTokenList{})
+	s.AddKnownTypes(externalGV, &ApiTokenList{})
 	//TODO k8s reflector uses api.ListOptions, can we escape this
 	//dependency without rewriting?
 	s.AddKnownTypes(externalGV, &k8s_v1.ListOptions{})
@@ -2,6 +2,7 @@ package v1
 
 import (
 	"errors"
+	"fmt"
 	"k8s.io/kubernetes/pkg/api/meta"
 	"k8s.io/kubernetes/pkg/api/unversioned"
 	"k8s.io/kubernetes/pkg/api/v1"
@@ -18,6 +19,26 @@ type SitepodSpec struct {
 	DisplayName  string   `json:"displayName,omitempty"`
 	Description  string   `json:"description,omitempty"`
 	VolumeClaims []string `json:"volumeClaims,omitempty"`
+	// Owner is the name of the sitepod user the sitepod belongs to
+	Owner string `json:"owner,omitempty"`
+	// Members are further sitepod users with a role on the sitepod, e.g. the customers of
+	// a reseller owner or developers working on it
+	Members []SitepodMember `json:"members,omitempty"`
+}
+
+const (
+	RoleOwner         = "owner"
+	RoleDeveloper     = "developer"
+	RoleBillingViewer = "billing-viewer"
+)
+
+type SitepodMember struct {
+	// User is the name of a sitepod user, the user need not exist yet in which case the
+	// role applies once a user with Email is created
+	User string `json:"user"`
+	Role string `json:"role"`
+	// Email the member was added by
+	Email string `json:"email,omitempty"`
 }
 
 type SitepodStatus struct {
@@ -40,6 +61,62 @@ func (s *Sitepod) SetCondition(condition string, val bool) {
 	s.Status.StorageSetup = val
 }
 
+func (s *Sitepod) IsOwnedBy(sitepodUserName string) bool {
+	return s.RoleOf(sitepodUserName) == RoleOwner
+}
+
+// RoleOf is the role of a sitepod user on the sitepod, blank if none. The owner has the
+// owner role whatever the members say.
+func (s *Sitepod) RoleOf(sitepodUserName string) string {
+	if len(sitepodUserName) == 0 {
+		return ""
+	}
+	if s.Spec.Owner == sitepodUserName {
+		return RoleOwner
+	}
+	for _, member := range s.Spec.Members {
+		if member.User == sitepodUserName {
+			return member.Role
+		}
+	}
+	return ""
+}
+
+// MemberUsers are the names of the sitepod users with any role, the owner first
+func (s *Sitepod) MemberUsers() []string {
+	users := []string{}
+	if len(s.Spec.Owner) > 0 {
+		users = append(users, s.Spec.Owner)
+	}
+	for _, member := range s.Spec.Members {
+		if member.User != s.Spec.Owner {
+			users = append(users, member.User)
+		}
+	}
+	return users
+}
+
+func IsValidRole(role string) bool {
+	return role == RoleOwner || role == RoleDeveloper || role == RoleBillingViewer
+}
+
+func (s *Sitepod) ValidateMembers() error {
+	seen := make(map[string]bool)
+	for _, member := range s.Spec.Members {
+		if len(member.User) == 0 {
+			return fmt.Errorf("Member without a user")
+		}
+		if !IsValidRole(member.Role) {
+			return fmt.Errorf("Member %s has unknown role %s", member.User, member.Role)
+		}
+		if seen[member.User] || member.User == s.Spec.Owner {
+			return fmt.Errorf("Member %s is listed more than once", member.User)
+		}
+		seen[member.User] = true
+	}
+	return nil
+}
+
 func (s *Sitepod) GetRootStorageName() (string, error) {
 
 	if len(s.Status.LocalStorage) == 0 {
@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"k8s.io/kubernetes/pkg/api/meta"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/api/v1"
+)
+
+// SitepodMigration clones a sitepod, its system users, app components and websites
+// into a new sitepod (typically on volume claims pinned to another node) and streams
+// the home data across. With DeleteSource the source sitepod is removed afterwards.
+type SitepodMigration struct {
+	unversioned.TypeMeta `json:",inline"`
+	ObjectMeta           `json:"metadata,omitempty"`
+	Spec                 SitepodMigrationSpec   `json:"spec"`
+	Status               SitepodMigrationStatus `json:"status"`
+}
+
+func (s *SitepodMigration) SetDefaults() {
+	s.ObjectMeta.Labels = make(map[string]string)
+	s.ObjectMeta.Annotations = make(map[string]string)
+	s.Spec.CopyData = true
+}
+
+type SitepodMigrationSpec struct {
+	Source       string   `json:"source"`
+	Destination  string   `json:"destination"`
+	VolumeClaims []string `json:"volumeClaims,omitempty"`
+	CopyData     bool     `json:"copyData,omitempty"`
+	DeleteSource bool     `json:"deleteSource,omitempty"`
+}
+
+type SitepodMigrationStatus struct {
+	SourceUID       string `json:"sourceUID,omitempty"`
+	DestinationUID  string `json:"destinationUID,omitempty"`
+	DataTransferred bool   `json:"dataTransferred,omitempty"`
+	ResourcesCopied bool   `json:"resourcesCopied,omitempty"`
+	// OwnershipTransferred is set once the copied homes are owned by the file UIDs of the
+	// copied users
+	OwnershipTransferred bool `json:"ownershipTransferred,omitempty"`
+	Completed            bool `json:"completed,omitempty"`
+}
+
+func (s *SitepodMigration) SetCondition(condition string, val bool) {
+	if condition == "DataTransferred" {
+		s.Status.DataTransferred = val
+	}
+	if condition == "OwnershipTransferred" {
+		s.Status.OwnershipTransferred = val
+	}
+}
+
+func (s *SitepodMigration) GetObjectKind() unversioned.ObjectKind {
+	return &s.TypeMeta
+}
+
+func (s *SitepodMigration) GetObjectMeta() meta.Object {
+	om := v1.ObjectMeta(s.ObjectMeta)
+	return &om
+}
+
+type SitepodMigrationList struct {
+	unversioned.TypeMeta `json:",inline"`
+	ListMeta             `json:"metadata,omitempty"`
+	Items                []SitepodMigration `json:"items"`
+}
+
+func (s *SitepodMigrationList) GetObjectKind() unversioned.ObjectKind {
+	return &s.TypeMeta
+}
+
+func (s *SitepodMigrationList) GetListMeta() unversioned.List {
+	lm := unversioned.ListMeta(s.ListMeta)
+	return &lm
+}
@@ -1,6 +1,9 @@
 package v1
 
 import (
+	"crypto/subtle"
+	"errors"
+
 	"k8s.io/kubernetes/pkg/api/meta"
 	"k8s.io/kubernetes/pkg/api/unversioned"
 	"k8s.io/kubernetes/pkg/api/v1"
@@ -8,12 +11,29 @@ import (
 )
 
 type SitepodUserSpec struct {
-	Email          string
+	Email string
+	// PasswordHash is a modular crypt format string, see util.HashPassword
+	PasswordHash string
+	// SaltedPassword and Salt are the legacy sha512 crypt password, replaced by a
+	// PasswordHash on the next successful login
 	SaltedPassword string
 	Salt           string
+	// TwoFactor holds the second login factor, see sitepod_user_twofactor.go
+	TwoFactor TwoFactorSpec
+	// SessionGeneration is raised to log out every web session of the user, a session
+	// is only valid with the generation it was started under
+	SessionGeneration int64
 }
 
+const (
+	// SitepodUserPendingVerification users have not yet confirmed their email address
+	SitepodUserPendingVerification = "PendingVerification"
+	SitepodUserActive              = "Active"
+)
+
 type SitepodUserStatus struct {
+	// Status is PendingVerification or Active, blank for users from before verification
+	// who count as Active
 	Status string
 }
 
@@ -50,22 +70,60 @@ func (s *SitepodUser) SetDefaults() {
 	s.ObjectMeta.Namespace = "default"
 }
 
+// EmailVerified is whether the user has confirmed owning the email address
+func (s *SitepodUser) EmailVerified() bool {
+	return s.Status.Status != SitepodUserPendingVerification
+}
+
+const legacyPlainTextPasswordAnnotation = "sitepod.io/plain-text-password"
+
+// BeforeAdd makes sure a plain text password can't reach the api server through the
+// annotation earlier versions hashed on add, use SetPassword instead
 func (s *SitepodUser) BeforeAdd() {
+	delete(s.ObjectMeta.Annotations, legacyPlainTextPasswordAnnotation)
+}
+
+// SetPassword hashes the password with the default (memory hard) scheme, the plain text
+// is never kept
+func (s *SitepodUser) SetPassword(password string) error {
 
-	if ptPasswd := s.ObjectMeta.Annotations["sitepod.io/plain-text-password"]; ptPasswd != "" {
+	if len(password) == 0 {
+		return errors.New("Password must not be empty")
+	}
+
+	hash, err := util.HashPassword("", password)
+	if err != nil {
+		return err
+	}
+
+	s.Spec.PasswordHash = hash
+	s.Spec.SaltedPassword = ""
+	s.Spec.Salt = ""
+	return nil
+}
 
-		salt := util.RandomSalt(8)
-		saltedPasswd, err := util.Sha512Crypt(ptPasswd, salt)
+// RevokeSessions logs out every web session of the user once the user is updated
+func (s *SitepodUser) RevokeSessions() {
+	s.Spec.SessionGeneration++
+}
 
-		if err != nil {
-			panic(err)
-		}
+// VerifyPassword checks the password in constant time, needsUpgrade means the caller
+// should SetPassword with the password it holds and update the user
+func (s *SitepodUser) VerifyPassword(password string) (matched bool, needsUpgrade bool, err error) {
 
-		s.Spec.Salt = salt
-		s.Spec.SaltedPassword = saltedPasswd
+	if len(s.Spec.PasswordHash) > 0 {
+		return util.VerifyPassword(password, s.Spec.PasswordHash)
+	}
 
-		delete(s.ObjectMeta.Annotations, "sitepod.io/plain-text-password")
+	if len(s.Spec.SaltedPassword) == 0 {
+		return false, false, nil
+	}
 
+	saltedPassword, err := util.Sha512Crypt(password, s.Spec.Salt)
+	if err != nil {
+		return false, false, err
 	}
 
+	matched = subtle.ConstantTimeCompare([]byte(saltedPassword), []byte(s.Spec.SaltedPassword)) == 1
+	return matched, matched, nil
 }
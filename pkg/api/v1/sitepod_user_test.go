@@ -0,0 +1,65 @@
+package v1
+
+import (
+	"testing"
+
+	"sitepod.io/sitepod/pkg/util"
+)
+
+func TestVerifyPasswordLegacySaltedPassword(t *testing.T) {
+	saltedPassword, err := util.Sha512Crypt("correct horse", "legacysalt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &SitepodUser{Spec: SitepodUserSpec{SaltedPassword: saltedPassword, Salt: "legacysalt"}}
+
+	matched, needsUpgrade, err := user.VerifyPassword("correct horse")
+	if err != nil || !matched || !needsUpgrade {
+		t.Errorf("legacy password: matched %v, needsUpgrade %v, err %v", matched, needsUpgrade, err)
+	}
+
+	matched, needsUpgrade, err = user.VerifyPassword("wrong horse")
+	if err != nil || matched || needsUpgrade {
+		t.Errorf("wrong legacy password: matched %v, needsUpgrade %v, err %v", matched, needsUpgrade, err)
+	}
+}
+
+func TestSetPasswordUpgradesLegacy(t *testing.T) {
+	saltedPassword, _ := util.Sha512Crypt("correct horse", "legacysalt")
+	user := &SitepodUser{Spec: SitepodUserSpec{SaltedPassword: saltedPassword, Salt: "legacysalt"}}
+
+	if err := user.SetPassword("correct horse"); err != nil {
+		t.Fatal(err)
+	}
+	if len(user.Spec.SaltedPassword) > 0 || len(user.Spec.Salt) > 0 {
+		t.Error("legacy password kept after SetPassword")
+	}
+
+	matched, needsUpgrade, err := user.VerifyPassword("correct horse")
+	if err != nil || !matched || needsUpgrade {
+		t.Errorf("upgraded password: matched %v, needsUpgrade %v, err %v", matched, needsUpgrade, err)
+	}
+}
+
+func TestVerifyPasswordNeedsUpgradeOtherScheme(t *testing.T) {
+	hash, err := util.HashPassword(util.SchemeBcrypt, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &SitepodUser{Spec: SitepodUserSpec{PasswordHash: hash}}
+
+	matched, needsUpgrade, err := user.VerifyPassword("correct horse")
+	if err != nil || !matched || !needsUpgrade {
+		t.Errorf("bcrypt password: matched %v, needsUpgrade %v, err %v", matched, needsUpgrade, err)
+	}
+}
+
+func TestVerifyPasswordWithoutPassword(t *testing.T) {
+	user := &SitepodUser{}
+	if matched, _, _ := user.VerifyPassword(""); matched {
+		t.Error("user without a password matched the empty password")
+	}
+	if err := user.SetPassword(""); err == nil {
+		t.Error("empty password accepted")
+	}
+}
@@ -0,0 +1,137 @@
+package v1
+
+// Two-factor authentication of panel logins with an authenticator app (TOTP) and single
+// use recovery codes for when the app is lost. Only hashes of recovery codes are stored.
+// Methods verifying a code consume it, the caller must update the user for that to hold
+// and a conflicting update means the code was used concurrently.
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"sitepod.io/sitepod/pkg/util"
+)
+
+// RecoveryCodeCount is how many recovery codes are issued at a time
+const RecoveryCodeCount = 10
+
+type TwoFactorSpec struct {
+	// TOTPSecret is the base32 secret of the confirmed authenticator, 2FA is enabled
+	// while it is set
+	TOTPSecret string
+	// PendingTOTPSecret is an enrolment awaiting its first valid code
+	PendingTOTPSecret string
+	// LastTOTPCounter is the time step of the last accepted code, older and equal steps
+	// are refused so a code is only good once
+	LastTOTPCounter int64
+	// RecoveryCodes are sha256 hashes of the unused recovery codes
+	RecoveryCodes []string
+}
+
+func (s *SitepodUser) TwoFactorEnabled() bool {
+	return len(s.Spec.TwoFactor.TOTPSecret) > 0
+}
+
+// BeginTOTPEnrolment generates a secret for the authenticator app, it takes effect once
+// confirmed with a code
+func (s *SitepodUser) BeginTOTPEnrolment() string {
+	s.Spec.TwoFactor.PendingTOTPSecret = util.GenerateTOTPSecret()
+	return s.Spec.TwoFactor.PendingTOTPSecret
+}
+
+// ConfirmTOTPEnrolment enables 2FA with the pending secret if the code matches it,
+// returning fresh recovery codes to show the user once
+func (s *SitepodUser) ConfirmTOTPEnrolment(code string, now time.Time) ([]string, error) {
+
+	pending := s.Spec.TwoFactor.PendingTOTPSecret
+	if len(pending) == 0 {
+		return nil, errors.New("No two-factor enrolment in progress")
+	}
+
+	matched, counter := util.VerifyTOTP(pending, normalizeCode(code), now)
+	if !matched {
+		return nil, errors.New("Code incorrect")
+	}
+
+	s.Spec.TwoFactor.TOTPSecret = pending
+	s.Spec.TwoFactor.PendingTOTPSecret = ""
+	s.Spec.TwoFactor.LastTOTPCounter = counter
+	return s.GenerateRecoveryCodes(), nil
+}
+
+// VerifyTOTP checks a code of the enrolled authenticator, refusing replays
+func (s *SitepodUser) VerifyTOTP(code string, now time.Time) bool {
+
+	if !s.TwoFactorEnabled() {
+		return false
+	}
+
+	matched, counter := util.VerifyTOTP(s.Spec.TwoFactor.TOTPSecret, normalizeCode(code), now)
+	if !matched || counter <= s.Spec.TwoFactor.LastTOTPCounter {
+		return false
+	}
+
+	s.Spec.TwoFactor.LastTOTPCounter = counter
+	return true
+}
+
+// VerifySecondFactor accepts an authenticator code or a recovery code, a recovery code
+// is removed once used
+func (s *SitepodUser) VerifySecondFactor(code string, now time.Time) bool {
+
+	if s.VerifyTOTP(code, now) {
+		return true
+	}
+
+	if !s.TwoFactorEnabled() {
+		return false
+	}
+
+	hash := hashRecoveryCode(code)
+	for i, stored := range s.Spec.TwoFactor.RecoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(stored)) == 1 {
+			codes := s.Spec.TwoFactor.RecoveryCodes
+			s.Spec.TwoFactor.RecoveryCodes = append(codes[:i:i], codes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateRecoveryCodes replaces the recovery codes, returning them in plain text
+func (s *SitepodUser) GenerateRecoveryCodes() []string {
+
+	codes := make([]string, RecoveryCodeCount)
+	hashes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		// 80 bits as xxxx-xxxx-xxxx-xxxx-xxxx
+		raw := hex.EncodeToString(util.RandomBytes(10))
+		groups := make([]string, 0, 5)
+		for j := 0; j < len(raw); j += 4 {
+			groups = append(groups, raw[j:j+4])
+		}
+		codes[i] = strings.Join(groups, "-")
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	s.Spec.TwoFactor.RecoveryCodes = hashes
+	return codes
+}
+
+func (s *SitepodUser) DisableTwoFactor() {
+	s.Spec.TwoFactor = TwoFactorSpec{}
+}
+
+// normalizeCode drops the spaces and dashes users type or paste along with codes
+func normalizeCode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code)))
+}
+
+func hashRecoveryCode(code string) string {
+	hash := sha256.Sum256([]byte(normalizeCode(code)))
+	return hex.EncodeToString(hash[:])
+}
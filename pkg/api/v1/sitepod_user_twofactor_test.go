@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"sitepod.io/sitepod/pkg/util"
+)
+
+// enrolledUser is a user with 2FA confirmed at now, and the recovery codes issued
+func enrolledUser(t *testing.T, now time.Time) (*SitepodUser, []byte, []string) {
+	user := &SitepodUser{}
+	secret := user.BeginTOTPEnrolment()
+	key, err := util.DecodeTOTPSecret(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recoveryCodes, err := user.ConfirmTOTPEnrolment(util.TOTP(key, now, util.TOTPDigits), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !user.TwoFactorEnabled() || len(recoveryCodes) != RecoveryCodeCount {
+		t.Fatalf("enrolment: enabled %v, %d recovery codes", user.TwoFactorEnabled(), len(recoveryCodes))
+	}
+	return user, key, recoveryCodes
+}
+
+func TestConfirmTOTPEnrolment(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	user := &SitepodUser{}
+
+	if _, err := user.ConfirmTOTPEnrolment("123456", now); err == nil {
+		t.Error("confirmed without an enrolment in progress")
+	}
+
+	key, _ := util.DecodeTOTPSecret(user.BeginTOTPEnrolment())
+	stale := now.Add(-5 * util.TOTPPeriod * time.Second)
+	if _, err := user.ConfirmTOTPEnrolment(util.TOTP(key, stale, util.TOTPDigits), now); err == nil || user.TwoFactorEnabled() {
+		t.Error("confirmed with a wrong code")
+	}
+}
+
+func TestVerifyTOTPRefusesReplay(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	user, key, _ := enrolledUser(t, now)
+
+	// the enrolment code's step is used up
+	if user.VerifyTOTP(util.TOTP(key, now, util.TOTPDigits), now) {
+		t.Error("enrolment code accepted again")
+	}
+
+	later := now.Add(util.TOTPPeriod * time.Second)
+	code := util.TOTP(key, later, util.TOTPDigits)
+	if !user.VerifyTOTP(code, later) {
+		t.Fatal("code of the next step refused")
+	}
+	if user.Spec.TwoFactor.LastTOTPCounter != util.TOTPCounter(later) {
+		t.Errorf("LastTOTPCounter %d, want %d", user.Spec.TwoFactor.LastTOTPCounter, util.TOTPCounter(later))
+	}
+	if user.VerifyTOTP(code, later) {
+		t.Error("replayed code accepted")
+	}
+
+	// a code of an earlier step still within the skew is refused too
+	if user.VerifyTOTP(util.TOTP(key, now, util.TOTPDigits), later) {
+		t.Error("code of an earlier step accepted")
+	}
+}
+
+func TestRecoveryCodeSingleUse(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	user, _, recoveryCodes := enrolledUser(t, now)
+
+	if !user.VerifySecondFactor(recoveryCodes[0], now) {
+		t.Fatal("recovery code refused")
+	}
+	if len(user.Spec.TwoFactor.RecoveryCodes) != RecoveryCodeCount-1 {
+		t.Errorf("%d recovery codes left, want %d", len(user.Spec.TwoFactor.RecoveryCodes), RecoveryCodeCount-1)
+	}
+	if user.VerifySecondFactor(recoveryCodes[0], now) {
+		t.Error("recovery code accepted twice")
+	}
+
+	// codes are accepted as typed, in upper case and without dashes
+	if !user.VerifySecondFactor(" "+strings.ToUpper(strings.Replace(recoveryCodes[1], "-", "", -1)), now) {
+		t.Error("recovery code without dashes refused")
+	}
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	user, key, recoveryCodes := enrolledUser(t, now)
+
+	user.DisableTwoFactor()
+	later := now.Add(util.TOTPPeriod * time.Second)
+	if user.TwoFactorEnabled() || user.VerifySecondFactor(util.TOTP(key, later, util.TOTPDigits), later) ||
+		user.VerifySecondFactor(recoveryCodes[0], later) {
+		t.Error("second factor accepted after disabling 2FA")
+	}
+}
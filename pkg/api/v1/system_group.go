@@ -0,0 +1,74 @@
+package v1
+
+import (
+	"strings"
+
+	"k8s.io/kubernetes/pkg/api/meta"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/api/v1"
+)
+
+// SystemGroup is a supplementary unix group within a sitepod, e.g. several sftp users
+// sharing a website directory
+type SystemGroup struct {
+	unversioned.TypeMeta `json:",inline"`
+	ObjectMeta           `json:"metadata,omitempty"`
+	Spec                 SystemGroupSpec   `json:"spec"`
+	Status               SystemGroupStatus `json:"status"`
+}
+
+type SystemGroupSpec struct {
+	Groupname string `json:"groupname,omitempty"`
+	// Members are usernames of system users in the same sitepod
+	Members []string `json:"members,omitempty"`
+}
+
+type SystemGroupStatus struct {
+	// GIDs are drawn from the cluster file UID pool so a GID never collides with a UID
+	AssignedGID int `json:"assignedGID,omitempty"`
+}
+
+func (s *SystemGroup) GetObjectMeta() meta.Object {
+	om := v1.ObjectMeta(s.ObjectMeta)
+	return &om
+}
+
+func (s *SystemGroup) GetGroupname() string {
+	if len(s.Spec.Groupname) > 0 {
+		return s.Spec.Groupname
+	}
+	return strings.TrimPrefix(s.Name, "systemgroup-")
+}
+
+// HasValidGroupname guards the etc group file, same rules as usernames
+func (s *SystemGroup) HasValidGroupname() bool {
+	return validName(s.GetGroupname())
+}
+
+func (s *SystemGroup) HasMember(username string) bool {
+	for _, member := range s.Spec.Members {
+		if member == username {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SystemGroup) GetObjectKind() unversioned.ObjectKind {
+	return &s.TypeMeta
+}
+
+type SystemGroupList struct {
+	unversioned.TypeMeta `json:",inline"`
+	ListMeta             `json:"metadata,omitempty"`
+	Items                []SystemGroup `json:"items"`
+}
+
+func (s *SystemGroupList) GetObjectKind() unversioned.ObjectKind {
+	return &s.TypeMeta
+}
+
+func (s *SystemGroupList) GetListMeta() unversioned.List {
+	lm := unversioned.ListMeta(s.ListMeta)
+	return &lm
+}
@@ -1,9 +1,15 @@
 package v1
 
 import (
+	"fmt"
+	"path"
+	"regexp"
+
+	"golang.org/x/crypto/ssh"
 	"k8s.io/kubernetes/pkg/api/meta"
 	"k8s.io/kubernetes/pkg/api/unversioned"
 	"k8s.io/kubernetes/pkg/api/v1"
+	"sitepod.io/sitepod/pkg/util"
 	"strings"
 )
 
@@ -12,17 +18,66 @@ type HashedPassword struct {
 	Salt         string `json:"salt,omitempty"`
 	CombinedHash string `json:"combinedHash,omitempty"`
 	Rounds       int    `json:"rounds,omitempty"`
+	// ChangedAt is the date of last password change in the shadow file
+	ChangedAt *unversioned.Time `json:"changedAt,omitempty"`
 }
 
 func (hp HashedPassword) IsValid() bool {
 	return len(hp.CombinedHash) > 0
 }
 
+// CryptString is the password in crypt(3) form for the shadow file. For schemes other
+// than sha512-crypt CombinedHash holds the complete modular crypt format string.
+func (hp HashedPassword) CryptString() string {
+	if len(hp.Scheme) > 0 && hp.Scheme != util.SchemeSha512Crypt {
+		return hp.CombinedHash
+	}
+	return util.FormatSha512Crypt(hp.Salt, hp.CombinedHash, hp.Rounds)
+}
+
+// ShadowAging are the password aging fields of the shadow file, unset fields are left
+// empty which disables the check
+type ShadowAging struct {
+	MinAgeDays     *int              `json:"minAgeDays,omitempty"`
+	MaxAgeDays     *int              `json:"maxAgeDays,omitempty"`
+	WarnDays       *int              `json:"warnDays,omitempty"`
+	InactiveDays   *int              `json:"inactiveDays,omitempty"`
+	AccountExpires *unversioned.Time `json:"accountExpires,omitempty"`
+}
+
+const (
+	// HomeArchive moves the home directory aside to /home/.archived, owned by root, on
+	// deletion
+	HomeArchive = "archive"
+	// HomeRemove deletes the home directory on deletion
+	HomeRemove = "remove"
+	// HomeRetain leaves the home directory in place on deletion, the file UID stays
+	// allocated as it still owns the files
+	HomeRetain = "retain"
+
+	AccessShell      = "shell"
+	AccessSFTP       = "sftp"
+	AccessSFTPChroot = "sftp-chroot"
+
+	// NoLoginShell is the passwd shell of sftp only users
+	NoLoginShell = "/sbin/nologin"
+)
+
 type SystemUserSpec struct {
 	Username string         `json:"username,omitempty"`
 	Shell    string         `json:"shell,omitempty"`
 	Password HashedPassword `json:"hashedPassword,omitempty"`
 	Sitepod  string         `json:"sitepod,omitempty"`
+	// HomeDeletionPolicy is one of archive (default), remove or retain
+	HomeDeletionPolicy string `json:"homeDeletionPolicy,omitempty"`
+	// AuthorizedKeys are public keys in authorized_keys format, one per entry
+	AuthorizedKeys []string `json:"authorizedKeys,omitempty"`
+	// AccessMode is one of shell (default), sftp or sftp-chroot
+	AccessMode string `json:"accessMode,omitempty"`
+	// AllowedPaths scope sftp users, an sftp user starts in the first and an sftp-chroot
+	// user is confined to it (at most one)
+	AllowedPaths []string    `json:"allowedPaths,omitempty"`
+	Aging        ShadowAging `json:"aging,omitempty"`
 }
 
 type SystemUserStatus struct {
@@ -38,12 +93,21 @@ type SystemUser struct {
 	Status               SystemUserStatus `json:"status"`
 }
 
+func (s *SystemUser) SetDefaults() {
+	s.ObjectMeta.Labels = make(map[string]string)
+	s.ObjectMeta.Annotations = make(map[string]string)
+}
+
 func (s *SystemUser) GetObjectMeta() meta.Object {
 	om := v1.ObjectMeta(s.ObjectMeta)
 	return &om
 }
 
 func (s *SystemUser) GetUsername() string {
+	// An explicit username allows the same login in more than one sitepod
+	if len(s.Spec.Username) > 0 {
+		return s.Spec.Username
+	}
 	systemUsername := strings.TrimPrefix(s.Name, "systemuser-")
 	//if systemUsername == s.Spec.Username {
 	//panic("bad state. system username are expected to start with systemuser-")
@@ -51,6 +115,106 @@ func (s *SystemUser) GetUsername() string {
 	return systemUsername
 }
 
+var usernamePattern = regexp.MustCompile("^[a-z_][a-z0-9_-]{0,31}$")
+
+// ReservedNames are accounts and groups of the images, and sitepod whose home holds the
+// document roots of all websites, they can not be used for system users or groups
+var ReservedNames = map[string]bool{
+	"root": true, "daemon": true, "bin": true, "sys": true, "adm": true, "sync": true,
+	"shutdown": true, "halt": true, "mail": true, "news": true, "uucp": true, "operator": true,
+	"man": true, "lp": true, "games": true, "ftp": true, "proxy": true, "www-data": true,
+	"backup": true, "list": true, "irc": true, "gnats": true, "nobody": true, "nogroup": true,
+	"sshd": true, "nginx": true, "shadow": true, "staff": true, "sudo": true, "wheel": true,
+	"users": true, "utmp": true, "tty": true, "disk": true, "kmem": true, "sitepod": true,
+}
+
+func validName(name string) bool {
+	return usernamePattern.MatchString(name) && !ReservedNames[name]
+}
+
+// HasValidUsername guards paths and etc files built from the username
+func (s *SystemUser) HasValidUsername() bool {
+	return validName(s.GetUsername())
+}
+
+// ValidateAuthorizedKeys checks each entry is exactly one valid authorized_keys line
+func (s *SystemUser) ValidateAuthorizedKeys() error {
+	for idx, authorizedKey := range s.Spec.AuthorizedKeys {
+		_, _, _, rest, err := ssh.ParseAuthorizedKey([]byte(authorizedKey))
+		if err != nil {
+			return fmt.Errorf("Authorized key %d of %s is invalid: %s", idx, s.Name, err)
+		}
+		if len(strings.TrimSpace(string(rest))) > 0 {
+			return fmt.Errorf("Authorized key %d of %s contains more than one key", idx, s.Name)
+		}
+	}
+	return nil
+}
+
+func (s *SystemUser) GetAccessMode() string {
+	if len(s.Spec.AccessMode) > 0 {
+		return s.Spec.AccessMode
+	}
+	return AccessShell
+}
+
+func (s *SystemUser) IsSFTPOnly() bool {
+	mode := s.GetAccessMode()
+	return mode == AccessSFTP || mode == AccessSFTPChroot
+}
+
+// ValidateAccess checks the access mode and allowed paths, paths end up in sshd_config
+// so must be clean absolute paths within /home
+func (s *SystemUser) ValidateAccess() error {
+
+	switch s.GetAccessMode() {
+	case AccessShell, AccessSFTP:
+	case AccessSFTPChroot:
+		if len(s.Spec.AllowedPaths) > 1 {
+			return fmt.Errorf("User %s may only be chrooted to one allowed path", s.Name)
+		}
+	default:
+		return fmt.Errorf("User %s has unknown access mode %s", s.Name, s.Spec.AccessMode)
+	}
+
+	for _, allowedPath := range s.Spec.AllowedPaths {
+		if path.Clean(allowedPath) != allowedPath || !strings.HasPrefix(allowedPath, "/home/") ||
+			!allowedPathPattern.MatchString(allowedPath) {
+			return fmt.Errorf("User %s allowed path %s must be a clean path within /home", s.Name, allowedPath)
+		}
+	}
+	return nil
+}
+
+var allowedPathPattern = regexp.MustCompile("^[A-Za-z0-9_./-]+$")
+
+// GetChrootDirectory is where an sftp-chroot user is confined, the directory and its
+// parents must be owned by root and not group or world writable
+func (s *SystemUser) GetChrootDirectory() string {
+	if s.GetAccessMode() != AccessSFTPChroot {
+		return ""
+	}
+	if len(s.Spec.AllowedPaths) > 0 {
+		return s.Spec.AllowedPaths[0]
+	}
+	return s.GetHomeDirectory()
+}
+
+// GetStartDirectory is where an sftp user starts, blank for the home directory
+func (s *SystemUser) GetStartDirectory() string {
+	if s.GetAccessMode() != AccessSFTP || len(s.Spec.AllowedPaths) == 0 {
+		return ""
+	}
+	return s.Spec.AllowedPaths[0]
+}
+
+func (s *SystemUser) GetHomeDeletionPolicy() string {
+	if len(s.Spec.HomeDeletionPolicy) > 0 {
+		return s.Spec.HomeDeletionPolicy
+	}
+	return HomeArchive
+}
+
 func (s *SystemUser) GetHomeDirectory() string {
 	//TODO this shouldnt be hardcoded and should be flexible strategy
 	return "/home/" + s.GetUsername()
@@ -58,6 +222,9 @@ func (s *SystemUser) GetHomeDirectory() string {
 
 func (s *SystemUser) GetShell() string {
 	//TODO this shouldnt be hardcoded and should be flexible strategy
+	if s.IsSFTPOnly() {
+		return NoLoginShell
+	}
 	if len(s.Spec.Shell) > 0 {
 		return s.Spec.Shell
 	} else {
@@ -65,6 +232,48 @@ func (s *SystemUser) GetShell() string {
 	}
 }
 
+// AllowedShells are the login shells a sitepod user may give a system user
+var AllowedShells = []string{"/bin/bash", "/bin/sh", NoLoginShell}
+
+var shellPattern = regexp.MustCompile("^/[A-Za-z0-9_./-]+$")
+
+// HasValidShell guards the passwd line built from the shell, it must be a clean absolute
+// path so it can't carry field separators or further lines
+func (s *SystemUser) HasValidShell() bool {
+	shell := s.GetShell()
+	return shellPattern.MatchString(shell) && path.Clean(shell) == shell
+}
+
+// ValidateShell checks the shell is one of AllowedShells
+func (s *SystemUser) ValidateShell() error {
+	shell := s.GetShell()
+	for _, allowed := range AllowedShells {
+		if shell == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("User %s shell %s is not one of %s", s.Name, shell, strings.Join(AllowedShells, ", "))
+}
+
+func (s *SystemUser) ValidateHomeDeletionPolicy() error {
+	switch s.GetHomeDeletionPolicy() {
+	case HomeArchive, HomeRemove, HomeRetain:
+		return nil
+	}
+	return fmt.Errorf("User %s has unknown home deletion policy %s", s.Name, s.Spec.HomeDeletionPolicy)
+}
+
+// ValidateAging checks the shadow day counts, -1 as in shadow(5) disables a check
+func (s *SystemUser) ValidateAging() error {
+	aging := s.Spec.Aging
+	for _, days := range []*int{aging.MinAgeDays, aging.MaxAgeDays, aging.WarnDays, aging.InactiveDays} {
+		if days != nil && *days < -1 {
+			return fmt.Errorf("User %s password aging days must be -1 or more", s.Name)
+		}
+	}
+	return nil
+}
+
 func (s *SystemUser) GetObjectKind() unversioned.ObjectKind {
 	return &s.TypeMeta
 }
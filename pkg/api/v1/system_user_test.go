@@ -0,0 +1,32 @@
+package v1
+
+import "testing"
+
+func TestHasValidUsername(t *testing.T) {
+	for _, username := range []string{"alice", "_svc", "web-1", "a23456789012345678901234567890ab"} {
+		user := &SystemUser{Spec: SystemUserSpec{Username: username}}
+		if !user.HasValidUsername() {
+			t.Errorf("username %q refused", username)
+		}
+	}
+
+	invalid := []string{"", "Alice", "1alice", "-alice", "al ice", "alice:x", "../etc", "a234567890123456789012345678901ab",
+		"root", "sitepod", "sshd", "nobody", "www-data", "sudo"}
+	for _, username := range invalid {
+		user := &SystemUser{Spec: SystemUserSpec{Username: username}}
+		if user.HasValidUsername() {
+			t.Errorf("username %q accepted", username)
+		}
+	}
+}
+
+func TestHasValidGroupname(t *testing.T) {
+	if group := (&SystemGroup{Spec: SystemGroupSpec{Groupname: "developers"}}); !group.HasValidGroupname() {
+		t.Error("groupname developers refused")
+	}
+	for _, groupname := range []string{"root", "sitepod", "wheel", "sudo", "Dev"} {
+		if group := (&SystemGroup{Spec: SystemGroupSpec{Groupname: groupname}}); group.HasValidGroupname() {
+			t.Errorf("groupname %q accepted", groupname)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"time"
+
+	"k8s.io/kubernetes/pkg/api/meta"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/api/v1"
+)
+
+// WebSession holds web api session values so any web api instance can serve a session
+type WebSession struct {
+	unversioned.TypeMeta `json:",inline"`
+	ObjectMeta           `json:"metadata,omitempty"`
+	Spec                 WebSessionSpec `json:"spec"`
+}
+
+type WebSessionSpec struct {
+	// Data is the session values encoded and authenticated with the session keys
+	Data      string           `json:"data"`
+	ExpiresAt unversioned.Time `json:"expiresAt"`
+}
+
+func (s *WebSession) IsExpired(now time.Time) bool {
+	return !s.Spec.ExpiresAt.IsZero() && !now.Before(s.Spec.ExpiresAt.Time)
+}
+
+func (s *WebSession) GetObjectMeta() meta.Object {
+	om := v1.ObjectMeta(s.ObjectMeta)
+	return &om
+}
+
+func (s *WebSession) GetObjectKind() unversioned.ObjectKind {
+	return &s.TypeMeta
+}
+
+type WebSessionList struct {
+	unversioned.TypeMeta `json:",inline"`
+	ListMeta             `json:"metadata,omitempty"`
+	Items                []WebSession `json:"items"`
+}
+
+func (s *WebSessionList) GetObjectKind() unversioned.ObjectKind {
+	return &s.TypeMeta
+}
+
+func (s *WebSessionList) GetListMeta() unversioned.List {
+	lm := unversioned.ListMeta(s.ListMeta)
+	return &lm
+}
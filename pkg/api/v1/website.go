@@ -1,6 +1,9 @@
 package v1
 
 import (
+	"fmt"
+	"regexp"
+
 	"k8s.io/kubernetes/pkg/api/meta"
 	"k8s.io/kubernetes/pkg/api/unversioned"
 	"k8s.io/kubernetes/pkg/api/v1"
@@ -25,6 +28,30 @@ func (c *Website) GetPrimaryDomain() string {
 type WebsiteSpec struct {
 	Name   string `json:"name,omitempty"`
 	Domain string `json:"domain,omitempty"`
+	// Group is the groupname of a system group in the same sitepod owning the document
+	// root, its members can write to it
+	Group string `json:"group,omitempty"`
+}
+
+// WebsitesDirectory holds the document roots, one per domain
+const WebsitesDirectory = "/home/sitepod/websites/"
+
+// domainPattern is a lowercase RFC 1123 hostname
+var domainPattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?(\.[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?)*$`)
+
+// HasValidDomain guards the document root and the server config built from the domain
+func (c *Website) HasValidDomain() bool {
+	domain := c.GetPrimaryDomain()
+	return len(domain) <= 253 && domainPattern.MatchString(domain)
+}
+
+// GetDocumentRoot refuses a domain that is not a hostname, it is used in paths of
+// commands run as root
+func (c *Website) GetDocumentRoot() (string, error) {
+	if !c.HasValidDomain() {
+		return "", fmt.Errorf("Domain %q of website %s is not a valid hostname", c.GetPrimaryDomain(), c.Name)
+	}
+	return WebsitesDirectory + c.GetPrimaryDomain(), nil
 }
 
 func (s *Website) GetObjectMeta() meta.Object {
@@ -0,0 +1,26 @@
+package v1
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetDocumentRoot(t *testing.T) {
+	for _, domain := range []string{"example.com", "www.example.com", "xn--bcher-kva.example", "a", "1-2.io"} {
+		website := &Website{Spec: WebsiteSpec{Domain: domain}}
+		documentRoot, err := website.GetDocumentRoot()
+		if err != nil || documentRoot != WebsitesDirectory+domain {
+			t.Errorf("domain %q: document root %q, err %v", domain, documentRoot, err)
+		}
+	}
+
+	invalid := []string{"", "../../etc", "example.com/..", ".", "..", "Example.com", "-example.com",
+		"example-.com", "example..com", "example.com.", "exa mple.com", "example.com;", "example.com\nlisten 80;",
+		strings.Repeat("a", 64) + ".com", strings.Repeat("a.", 127) + "aa"}
+	for _, domain := range invalid {
+		website := &Website{Spec: WebsiteSpec{Domain: domain}}
+		if documentRoot, err := website.GetDocumentRoot(); err == nil {
+			t.Errorf("domain %q accepted as %q", domain, documentRoot)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package backup
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FilesystemTarget keeps archives under a directory, typically a NFS mount
+type FilesystemTarget struct {
+	Root string
+}
+
+func NewFilesystemTarget(root string) *FilesystemTarget {
+	return &FilesystemTarget{root}
+}
+
+func (t *FilesystemTarget) path(object string) string {
+	return filepath.Join(t.Root, filepath.Clean("/"+object))
+}
+
+func (t *FilesystemTarget) Put(object string, r io.Reader) error {
+
+	dest := t.path(object)
+	if err := os.MkdirAll(filepath.Dir(dest), 0700); err != nil {
+		return err
+	}
+
+	// write alongside and rename so a failed stream never replaces a good archive
+	tmp, err := ioutil.TempFile(filepath.Dir(dest), ".partial-")
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), dest)
+}
+
+func (t *FilesystemTarget) Get(object string) (io.ReadCloser, error) {
+	return os.Open(t.path(object))
+}
+
+func (t *FilesystemTarget) List(prefix string) ([]string, error) {
+
+	objects := []string{}
+	err := filepath.Walk(t.Root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".partial-") {
+			return nil
+		}
+		rel, err := filepath.Rel(t.Root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if strings.HasPrefix(rel, prefix) {
+			objects = append(objects, rel)
+		}
+		return nil
+	})
+
+	return objects, err
+}
+
+func (t *FilesystemTarget) Delete(object string) error {
+	err := os.Remove(t.path(object))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+package backup
+
+import (
+	"io"
+
+	"github.com/minio/minio-go"
+)
+
+// S3Target keeps archives in a bucket of any S3 compatible endpoint
+type S3Target struct {
+	client *minio.Client
+	bucket string
+}
+
+func NewS3Target(endpoint string, bucket string, accessKey string, secretKey string, secure bool) (*S3Target, error) {
+
+	client, err := minio.New(endpoint, accessKey, secretKey, secure)
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3Target{client, bucket}, nil
+}
+
+func (t *S3Target) Put(object string, r io.Reader) error {
+	// an upload interrupted by a reader error is never completed by the server
+	_, err := t.client.PutObject(t.bucket, object, r, "application/gzip")
+	return err
+}
+
+func (t *S3Target) Get(object string) (io.ReadCloser, error) {
+	return t.client.GetObject(t.bucket, object)
+}
+
+func (t *S3Target) List(prefix string) ([]string, error) {
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	objects := []string{}
+	for info := range t.client.ListObjects(t.bucket, prefix, true, doneCh) {
+		if info.Err != nil {
+			return nil, info.Err
+		}
+		objects = append(objects, info.Key)
+	}
+	return objects, nil
+}
+
+func (t *S3Target) Delete(object string) error {
+	return t.client.RemoveObject(t.bucket, object)
+}
@@ -0,0 +1,52 @@
+package backup
+
+// Pluggable destinations for backup archives. Archives are streamed by the
+// podtask controller straight out of sitepod-manager into a target (and back
+// again for a restore) so nothing is buffered in memory.
+
+import (
+	"fmt"
+	"io"
+
+	"sitepod.io/sitepod/pkg/api/v1"
+	cc "sitepod.io/sitepod/pkg/client"
+)
+
+type Target interface {
+	// Put stores everything read from r under object, a failed read must not
+	// leave a partial object behind
+	Put(object string, r io.Reader) error
+	Get(object string) (io.ReadCloser, error)
+	List(prefix string) ([]string, error)
+	Delete(object string) error
+}
+
+// Open builds the target described by spec, credentials for s3 targets are
+// read from the accessKey and secretKey fields of spec.CredentialsSecret
+func Open(client *cc.Client, spec v1.BackupTarget) (Target, error) {
+	switch spec.Type {
+	case v1.BackupTargetPath, "":
+		if len(spec.Path) == 0 {
+			return nil, fmt.Errorf("backup target of type %s requires a path", v1.BackupTargetPath)
+		}
+		return NewFilesystemTarget(spec.Path), nil
+	case v1.BackupTargetS3:
+		var accessKey, secretKey string
+		if len(spec.CredentialsSecret) > 0 {
+			secret, exists := client.Secrets().MaybeGetByKey(spec.CredentialsSecret)
+			if !exists {
+				return nil, fmt.Errorf("backup target credentials secret %s not found", spec.CredentialsSecret)
+			}
+			accessKey = string(secret.Data["accessKey"])
+			secretKey = string(secret.Data["secretKey"])
+		}
+		return NewS3Target(spec.Endpoint, spec.Bucket, accessKey, secretKey, spec.Secure)
+	default:
+		return nil, fmt.Errorf("unknown backup target type %s", spec.Type)
+	}
+}
+
+// ArchiveName is the object name of a backup archive within a target
+func ArchiveName(sitepodKey string, backupName string) string {
+	return fmt.Sprintf("%s/%s.tar.gz", sitepodKey, backupName)
+}
@@ -0,0 +1,332 @@
+package bundle
+
+// A bundle is a portable gzipped tarball of a sitepod holding a manifest.json of the
+// sitepod and its system users, app components (including generated config files) and
+// websites, and optionally home.tar.gz of the sitepod home volume. On import all
+// resources are created afresh so they get new UIDs, the sitepod label is rewired to the
+// new sitepod and file UIDs are allocated from the target cluster.
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/golang/glog"
+	k8s_api "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/labels"
+	"sitepod.io/sitepod/pkg/allocator"
+	"sitepod.io/sitepod/pkg/api/v1"
+	cc "sitepod.io/sitepod/pkg/client"
+	"sitepod.io/sitepod/pkg/controller/shared"
+)
+
+const (
+	ManifestVersion = "v1"
+	manifestEntry   = "manifest.json"
+	homeEntry       = "home.tar.gz"
+)
+
+type Manifest struct {
+	Version       string            `json:"version"`
+	ExportedAt    unversioned.Time  `json:"exportedAt"`
+	Sitepod       v1.Sitepod        `json:"sitepod"`
+	SystemUsers   []v1.SystemUser   `json:"systemUsers"`
+	AppComponents []v1.Appcomponent `json:"appComponents"`
+	Websites      []v1.Website      `json:"websites"`
+	HasHomeData   bool              `json:"hasHomeData"`
+}
+
+// Export writes the bundle of sitepod to w, with includeData the home volume is
+// streamed out of sitepod-manager into the bundle
+func Export(client *cc.Client, sitepod *v1.Sitepod, includeData bool, w io.Writer) error {
+
+	sitepodKey := string(sitepod.UID)
+	selector := labels.SelectorFromSet(labels.Set{"sitepod": sitepodKey})
+
+	manifest := &Manifest{
+		Version:    ManifestVersion,
+		ExportedAt: unversioned.Now(),
+		Sitepod:    *sitepod,
+	}
+	for _, user := range client.SystemUsers().FetchList(selector) {
+		manifest.SystemUsers = append(manifest.SystemUsers, *user)
+	}
+	for _, ac := range client.AppComps().FetchList(selector) {
+		manifest.AppComponents = append(manifest.AppComponents, *ac)
+	}
+	for _, website := range client.Websites().FetchList(selector) {
+		manifest.Websites = append(manifest.Websites, *website)
+	}
+
+	// the tar header needs the size up front so spool the home data first
+	var homeData *os.File
+	if includeData {
+		pod, err := readyPod(client, sitepodKey)
+		if err != nil {
+			return err
+		}
+
+		homeData, err = ioutil.TempFile("", "sitepod-export-")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(homeData.Name())
+		defer homeData.Close()
+
+		stderr := bytes.NewBuffer([]byte{})
+		err = client.Exec(pod.Name, "sitepod-manager", []string{"/bin/tar", "-czpf", "-", "-C", "/home", "."},
+			nil, homeData, stderr)
+		if err != nil {
+			return fmt.Errorf("exporting home data failed: %v %s", err, stderr.String())
+		}
+		manifest.HasHomeData = true
+	}
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	err = tw.WriteHeader(&tar.Header{Name: manifestEntry, Mode: 0600, Size: int64(len(manifestBytes)),
+		ModTime: time.Now()})
+	if err != nil {
+		return err
+	}
+	if _, err := tw.Write(manifestBytes); err != nil {
+		return err
+	}
+
+	if homeData != nil {
+		info, err := homeData.Stat()
+		if err != nil {
+			return err
+		}
+		if _, err := homeData.Seek(0, 0); err != nil {
+			return err
+		}
+		err = tw.WriteHeader(&tar.Header{Name: homeEntry, Mode: 0600, Size: info.Size(), ModTime: time.Now()})
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, homeData); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+type ImportOptions struct {
+	// Name of the new sitepod, defaults to the exported name
+	Name string
+	// VolumeClaims of the new sitepod are required, they must not be used by an existing
+	// sitepod as the imported home data is extracted and chowned on them
+	VolumeClaims []string
+	// How long to wait for the new sitepod pod when restoring home data
+	Timeout time.Duration
+}
+
+// Import creates a new sitepod from the bundle read from r
+func Import(client *cc.Client, r io.Reader, opts ImportOptions) (*v1.Sitepod, error) {
+
+	if err := checkVolumeClaims(client, opts.VolumeClaims); err != nil {
+		return nil, err
+	}
+
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gr)
+
+	header, err := tr.Next()
+	if err != nil {
+		return nil, err
+	}
+	if header.Name != manifestEntry {
+		return nil, fmt.Errorf("bundle should start with %s not %s", manifestEntry, header.Name)
+	}
+
+	manifest := &Manifest{}
+	if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+		return nil, err
+	}
+	if manifest.Version != ManifestVersion {
+		return nil, fmt.Errorf("unsupported bundle version %s", manifest.Version)
+	}
+
+	sitepod := &manifest.Sitepod
+	exportedName := sitepod.Name
+	resetObjectMeta(&sitepod.ObjectMeta)
+	sitepod.Name = exportedName
+	if len(opts.Name) > 0 {
+		sitepod.Name = opts.Name
+	}
+	sitepod.Spec.VolumeClaims = opts.VolumeClaims
+	sitepod.Status = v1.SitepodStatus{}
+	sitepod = client.Sitepods().Add(sitepod)
+	sitepodKey := string(sitepod.UID)
+	glog.Infof("Imported sitepod %s as %s", exportedName, sitepodKey)
+
+	// file UIDs must not collide with users already on the target cluster
+	fileUIDs, err := allocator.NewFileUIDAllocator(client, client.ClusterName()).Allocate(len(manifest.SystemUsers))
+	if err != nil {
+		return sitepod, err
+	}
+
+	users := []*v1.SystemUser{}
+	for i := range manifest.SystemUsers {
+		user := &manifest.SystemUsers[i]
+		username := user.GetUsername()
+		resetObjectMeta(&user.ObjectMeta)
+		user.Labels["sitepod"] = sitepodKey
+		user.GenerateName = "systemuser-" + username + "-"
+		user.Spec.Username = username
+		user.Status = v1.SystemUserStatus{
+			AssignedFileUID: fileUIDs[i],
+			HomeProvisioned: manifest.HasHomeData,
+		}
+		users = append(users, client.SystemUsers().Add(user))
+	}
+
+	for i := range manifest.AppComponents {
+		ac := &manifest.AppComponents[i]
+		resetObjectMeta(&ac.ObjectMeta)
+		ac.Labels["sitepod"] = sitepodKey
+		ac.GenerateName = "sitepod-appcomp-"
+		ac.Status = v1.AppComponentStatus{}
+		client.AppComps().Add(ac)
+	}
+
+	for i := range manifest.Websites {
+		website := &manifest.Websites[i]
+		resetObjectMeta(&website.ObjectMeta)
+		website.Labels["sitepod"] = sitepodKey
+		website.GenerateName = "sitepod-website-"
+		if !manifest.HasHomeData {
+			website.Status = v1.WebsiteStatus{}
+		}
+		client.Websites().Add(website)
+	}
+
+	if !manifest.HasHomeData {
+		return sitepod, nil
+	}
+
+	header, err = tr.Next()
+	if err != nil {
+		return sitepod, err
+	}
+	if header.Name != homeEntry {
+		return sitepod, fmt.Errorf("expected %s in bundle not %s", homeEntry, header.Name)
+	}
+
+	pod, err := waitForReadyPod(client, sitepod, opts.Timeout)
+	if err != nil {
+		return sitepod, err
+	}
+
+	stderr := bytes.NewBuffer([]byte{})
+	err = client.Exec(pod.Name, "sitepod-manager", []string{"/bin/tar", "-xzf", "-", "--no-same-owner", "-C", "/home"},
+		tr, ioutil.Discard, stderr)
+	if err != nil {
+		return sitepod, fmt.Errorf("importing home data failed: %v %s", err, stderr.String())
+	}
+
+	// the exported uids are meaningless here, hand each home to its newly allocated uid
+	for _, user := range users {
+		cmd := []string{"/bin/chown", "-R", fmt.Sprintf("%d:%d", user.Status.AssignedFileUID, 2000),
+			user.GetHomeDirectory()}
+		stderr.Reset()
+		if err := client.Exec(pod.Name, "sitepod-manager", cmd, nil, ioutil.Discard, stderr); err != nil {
+			return sitepod, fmt.Errorf("chown of %s failed: %v %s", user.GetHomeDirectory(), err, stderr.String())
+		}
+	}
+
+	return sitepod, nil
+}
+
+func resetObjectMeta(om *v1.ObjectMeta) {
+	om.Name = ""
+	om.GenerateName = ""
+	om.UID = ""
+	om.ResourceVersion = ""
+	om.SelfLink = ""
+	om.CreationTimestamp = unversioned.Time{}
+	if om.Labels == nil {
+		om.Labels = make(map[string]string)
+	}
+	if om.Annotations == nil {
+		om.Annotations = make(map[string]string)
+	}
+}
+
+func readyPod(client *cc.Client, sitepodKey string) (*k8s_api.Pod, error) {
+	selector := labels.SelectorFromSet(labels.Set{"sitepod": sitepodKey})
+	for _, pod := range client.Pods().FetchList(selector) {
+		if shared.IsPodReady(pod) {
+			return pod, nil
+		}
+	}
+	return nil, errors.New("no ready pod for sitepod " + sitepodKey)
+}
+
+func waitForReadyPod(client *cc.Client, sitepod *v1.Sitepod, timeout time.Duration) (*k8s_api.Pod, error) {
+
+	deadline := time.Now().Add(timeout)
+	for {
+		current, exists := findByName(client, sitepod.Name)
+		if exists && current.Status.StorageSetup {
+			if pod, err := readyPod(client, string(sitepod.UID)); err == nil {
+				return pod, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for sitepod %s to be ready", sitepod.Name)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// checkVolumeClaims refuses to import without volume claims or onto claims of an
+// existing sitepod, the exported claims may well be those of the source sitepod
+func checkVolumeClaims(client *cc.Client, volumeClaims []string) error {
+
+	if len(volumeClaims) == 0 {
+		return errors.New("volume claims for the imported sitepod are required")
+	}
+
+	for _, sitepod := range client.Sitepods().FetchList(labels.Everything()) {
+		for _, claim := range sitepod.Spec.VolumeClaims {
+			for _, wanted := range volumeClaims {
+				if claim == wanted {
+					return fmt.Errorf("volume claim %s is used by sitepod %s", claim, sitepod.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func findByName(client *cc.Client, name string) (*v1.Sitepod, bool) {
+	for _, sitepod := range client.Sitepods().FetchList(labels.Everything()) {
+		if sitepod.Name == name {
+			return sitepod, true
+		}
+	}
+	return nil, false
+}
@@ -1,18 +1,22 @@
 package client
 
 import (
+	"fmt"
 	"k8s.io/kubernetes/pkg/api/unversioned"
 	k8s_v1 "k8s.io/kubernetes/pkg/api/v1"
 	ext_v1 "k8s.io/kubernetes/pkg/apis/extensions/v1beta1"
 	"k8s.io/kubernetes/pkg/client/restclient"
 	"k8s.io/kubernetes/pkg/runtime"
 	"k8s.io/kubernetes/pkg/runtime/serializer"
+	"sitepod.io/sitepod/pkg/api/v1"
 	"sync"
 )
 
 type ClientConfig struct {
 	ApiServer string
 	Namespace string
+	// Cluster is the name of the active cluster resource
+	Cluster string
 }
 
 type Client struct {
@@ -54,7 +58,9 @@ func (c *Client) usingCache(key string, fn func() interface{}) interface{} {
 
 func (c *Client) Sitepods() *SitepodClient {
 	return c.usingCache("sitepods", func() interface{} {
-		return NewSitepodClient(c.sitepodRestClient, c.sitepodRestClientConfig, c.config.Namespace)
+		sc := NewSitepodClient(c.sitepodRestClient, c.sitepodRestClientConfig, c.config.Namespace)
+		sc.addMemberIndexer()
+		return sc
 	}).(*SitepodClient)
 }
 
@@ -94,6 +100,12 @@ func (c *Client) SystemUsers() *SystemUserClient {
 	}).(*SystemUserClient)
 }
 
+func (c *Client) SystemGroups() *SystemGroupClient {
+	return c.usingCache("systemgroups", func() interface{} {
+		return NewSystemGroupClient(c.sitepodRestClient, c.sitepodRestClientConfig, c.config.Namespace)
+	}).(*SystemGroupClient)
+}
+
 func (c *Client) ConfigMaps() *ConfigMapClient {
 	return c.usingCache("configmaps", func() interface{} {
 		return NewConfigMapClient(c.k8sCoreRestClient, c.k8sCoreRestClientConfig, c.config.Namespace)
@@ -136,6 +148,66 @@ func (c *Client) SitepodUsers() *SitepodUserClient {
 	}).(*SitepodUserClient)
 }
 
+func (c *Client) Secrets() *SecretClient {
+	return c.usingCache("secrets", func() interface{} {
+		return NewSecretClient(c.k8sCoreRestClient, c.k8sCoreRestClientConfig, c.config.Namespace)
+	}).(*SecretClient)
+}
+
+func (c *Client) Backups() *BackupClient {
+	return c.usingCache("backups", func() interface{} {
+		return NewBackupClient(c.sitepodRestClient, c.sitepodRestClientConfig, c.config.Namespace)
+	}).(*BackupClient)
+}
+
+func (c *Client) Restores() *RestoreClient {
+	return c.usingCache("restores", func() interface{} {
+		return NewRestoreClient(c.sitepodRestClient, c.sitepodRestClientConfig, c.config.Namespace)
+	}).(*RestoreClient)
+}
+
+func (c *Client) BackupSchedules() *BackupScheduleClient {
+	return c.usingCache("backupschedules", func() interface{} {
+		return NewBackupScheduleClient(c.sitepodRestClient, c.sitepodRestClientConfig, c.config.Namespace)
+	}).(*BackupScheduleClient)
+}
+
+func (c *Client) SitepodMigrations() *SitepodMigrationClient {
+	return c.usingCache("sitepodmigrations", func() interface{} {
+		return NewSitepodMigrationClient(c.sitepodRestClient, c.sitepodRestClientConfig, c.config.Namespace)
+	}).(*SitepodMigrationClient)
+}
+
+func (c *Client) WebSessions() *WebSessionClient {
+	return c.usingCache("websessions", func() interface{} {
+		return NewWebSessionClient(c.sitepodRestClient, c.sitepodRestClientConfig, c.config.Namespace)
+	}).(*WebSessionClient)
+}
+
+func (c *Client) ApiTokens() *ApiTokenClient {
+	return c.usingCache("apitokens", func() interface{} {
+		return NewApiTokenClient(c.sitepodRestClient, c.sitepodRestClientConfig, c.config.Namespace)
+	}).(*ApiTokenClient)
+}
+
+// ClusterName is the name of the active cluster resource
+func (c *Client) ClusterName() string {
+	if len(c.config.Cluster) == 0 {
+		return v1.DefaultClusterName
+	}
+	return c.config.Cluster
+}
+
+// ActiveCluster is the cluster resource this client operates on from the informer store,
+// an error is returned if it does not (yet) exist
+func (c *Client) ActiveCluster() (*v1.Cluster, error) {
+	cluster, exists := c.Clusters().MaybeGetByKey(c.ClusterName())
+	if !exists {
+		return nil, fmt.Errorf("Active cluster %s does not exist", c.ClusterName())
+	}
+	return cluster, nil
+}
+
 func (c *Client) buildRestClient(apiPath string, gv *unversioned.GroupVersion) (*restclient.RESTClient, *restclient.Config) {
 
 	rcConfig := &restclient.Config{
@@ -170,6 +242,8 @@ func (c *Client) buildRestClient(apiPath string, gv *unversioned.GroupVersion) (
 
 //go:generate gotemplate "sitepod.io/sitepod/pkg/client/clienttmpl" SystemUserClient(v1.SystemUser,v1.SystemUserList,"SystemUser","SystemUsers",true,"systemuser-")
 
+//go:generate gotemplate "sitepod.io/sitepod/pkg/client/clienttmpl" SystemGroupClient(v1.SystemGroup,v1.SystemGroupList,"SystemGroup","SystemGroups",true,"systemgroup-")
+
 //go:generate gotemplate "sitepod.io/sitepod/pkg/client/clienttmpl" ConfigMapClient(k8s_api.ConfigMap,k8s_api.ConfigMapList,"ConfigMap","ConfigMaps",true,"sitepod-cm-")
 
 //go:generate gotemplate "sitepod.io/sitepod/pkg/client/clienttmpl" ClusterClient(v1.Cluster,v1.ClusterList,"Cluster","Clusters",true,"sitepod-cluster-")
@@ -183,3 +257,17 @@ func (c *Client) buildRestClient(apiPath string, gv *unversioned.GroupVersion) (
 //go:generate gotemplate "sitepod.io/sitepod/pkg/client/clienttmpl" WebsiteClient(v1.Website,v1.WebsiteList,"Website","Websites",true,"sitepod-website-")
 
 //go:generate gotemplate "sitepod.io/sitepod/pkg/client/clienttmpl" SitepodUserClient(v1.SitepodUser,v1.SitepodUserList,"SitepodUser","SitepodUsers",true,"sitepod-user-")
+
+//go:generate gotemplate "sitepod.io/sitepod/pkg/client/clienttmpl" SecretClient(k8s_api.Secret,k8s_api.SecretList,"Secret","Secrets",true,"sitepod-secret-")
+
+//go:generate gotemplate "sitepod.io/sitepod/pkg/client/clienttmpl" BackupClient(v1.Backup,v1.BackupList,"Backup","Backups",true,"sitepod-backup-")
+
+//go:generate gotemplate "sitepod.io/sitepod/pkg/client/clienttmpl" RestoreClient(v1.Restore,v1.RestoreList,"Restore","Restores",true,"sitepod-restore-")
+
+//go:generate gotemplate "sitepod.io/sitepod/pkg/client/clienttmpl" BackupScheduleClient(v1.Backupschedule,v1.BackupscheduleList,"BackupSchedule","BackupSchedules",true,"sitepod-backupschedule-")
+
+//go:generate gotemplate "sitepod.io/sitepod/pkg/client/clienttmpl" SitepodMigrationClient(v1.SitepodMigration,v1.SitepodMigrationList,"SitepodMigration","SitepodMigrations",true,"sitepod-migration-")
+
+//go:generate gotemplate "sitepod.io/sitepod/pkg/client/clienttmpl" WebSessionClient(v1.WebSession,v1.WebSessionList,"WebSession","WebSessions",true,"sitepod-websession-")
+
+//go:generate gotemplate "sitepod.io/sitepod/pkg/client/clienttmpl" ApiTokenClient(v1.ApiToken,v1.ApiTokenList,"ApiToken","ApiTokens",true,"sitepod-apitoken-")
@@ -252,6 +252,16 @@ type BeforeAdder interface {
 
 func (c *ClientTmpl) Add(target *ResourceType) *ResourceType {
 
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *ClientTmpl) TryAdd(target *ResourceType) (*ResourceType, error) {
+
 	var itarget interface{}
 	itarget = target
 	if subject, ok := itarget.(BeforeAdder); ok {
@@ -266,17 +276,17 @@ func (c *ClientTmpl) Add(target *ResourceType) *ResourceType {
 	result := rcReq.Resource(ResourcePluralName).Body(target).Do()
 
 	if err := result.Error(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	r, err := result.Get()
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := r.(*ResourceType)
 	glog.Infof("Added %s - %s (rv: %s)", ResourceName, item.Name, item.ResourceVersion)
-	return item
+	return item, nil
 }
 
 func (c *ClientTmpl) CloneItem(orig interface{}) *ResourceType {
@@ -289,10 +299,20 @@ func (c *ClientTmpl) CloneItem(orig interface{}) *ResourceType {
 
 func (c *ClientTmpl) Update(target *ResourceType) *ResourceType {
 
-	accessor, err := meta.Accessor(target)
+	item, err := c.TryUpdate(target)
 	if err != nil {
 		panic(err)
 	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *ClientTmpl) TryUpdate(target *ResourceType) (*ResourceType, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
 	rName := accessor.GetName()
 	rcReq := c.rc.Put()
 	if Namespaced {
@@ -300,10 +320,25 @@ func (c *ClientTmpl) Update(target *ResourceType) *ResourceType {
 	}
 	replacementTarget, err := rcReq.Resource(ResourcePluralName).Name(rName).Body(target).Do().Get()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := replacementTarget.(*ResourceType)
-	return item
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *ClientTmpl) FetchByName(name string) (*ResourceType, error) {
+
+	rcReq := c.rc.Get()
+	if Namespaced {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource(ResourcePluralName).Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*ResourceType)
+	return item, nil
 }
 
 func (c *ClientTmpl) UpdateOrAdd(target *ResourceType) *ResourceType {
@@ -0,0 +1,45 @@
+package client
+
+import (
+	"io"
+
+	k8s_api "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/client/unversioned/remotecommand"
+	remotecommandserver "k8s.io/kubernetes/pkg/kubelet/server/remotecommand"
+)
+
+// Exec runs command in a container of a pod with the given streams attached, stdin may be nil
+func (c *Client) Exec(podName string, containerName string, command []string,
+	stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+
+	req := c.Pods().RestClient().Post().
+		Resource("pods").
+		Name(podName).
+		Namespace(c.config.Namespace).
+		SubResource("exec").
+		Param("container", containerName)
+
+	req.VersionedParams(&k8s_api.PodExecOptions{
+		Container: containerName,
+		Command:   command,
+		Stdin:     stdin != nil,
+		Stdout:    true,
+		Stderr:    true,
+		TTY:       false,
+	}, k8s_api.ParameterCodec)
+
+	exec, err := remotecommand.NewExecutor(c.Pods().RestClientConfig(), "POST", req.URL())
+
+	if err != nil {
+		return err
+	}
+
+	return exec.Stream(remotecommand.StreamOptions{
+		SupportedProtocols: remotecommandserver.SupportedStreamingProtocols,
+		Stdin:              stdin,
+		Stdout:             stdout,
+		Stderr:             stderr,
+		Tty:                false,
+		TerminalSizeQueue:  nil,
+	})
+}
@@ -0,0 +1,418 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang/glog"
+	k8s_api "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/meta"
+	ext_api "k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/client/cache"
+	"k8s.io/kubernetes/pkg/client/restclient"
+	"k8s.io/kubernetes/pkg/controller/framework"
+	"k8s.io/kubernetes/pkg/conversion"
+	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/runtime"
+	"reflect"
+	"sitepod.io/sitepod/pkg/api"
+	"sitepod.io/sitepod/pkg/api/v1"
+	"strings"
+	"time"
+)
+
+var (
+	resyncPeriodApiTokenClient = 5 * time.Minute
+)
+
+func HackImportIgnoredApiTokenClient(a k8s_api.Volume, b v1.Cluster, c1 ext_api.ThirdPartyResource) {
+}
+
+// template type ClientTmpl(ResourceType, ResourceListType, ResourceName, ResourcePluralName, Namespaced, DefaultGenName)
+
+type ResouceListTypeApiTokenClient []int
+
+type ApiTokenClient struct {
+	rc            *restclient.RESTClient
+	rcConfig      *restclient.Config
+	ns            string
+	supportedType reflect.Type
+	informer      framework.SharedIndexInformer
+}
+
+func NewApiTokenClient(rc *restclient.RESTClient, config *restclient.Config, ns string) *ApiTokenClient {
+	c := &ApiTokenClient{
+		rc:            rc,
+		rcConfig:      config,
+		supportedType: reflect.TypeOf(&v1.ApiToken{}),
+	}
+
+	if true {
+		c.ns = ns
+	}
+
+	pc := runtime.NewParameterCodec(k8s_api.Scheme)
+
+	indexers := make(cache.Indexers)
+	indexers["sitepod"] = func(obj interface{}) ([]string, error) {
+		accessor, _ := meta.Accessor(obj)
+		labels := accessor.GetLabels()
+		if _, ok := labels["sitepod"]; ok {
+			return []string{labels["sitepod"]}, nil
+		} else {
+			return []string{}, nil
+		}
+	}
+
+	indexers["uid"] = func(obj interface{}) ([]string, error) {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			panic(err)
+		}
+		return []string{string(accessor.GetUID())}, nil
+	}
+
+	c.informer = framework.NewSharedIndexInformer(
+		api.NewListWatchFromClient(c.rc, "ApiTokens", c.ns, nil, pc),
+		&v1.ApiToken{},
+		resyncPeriodApiTokenClient,
+		indexers,
+	)
+
+	return c
+}
+
+func (c *ApiTokenClient) StartInformer(stopCh <-chan struct{}) {
+	c.informer.Run(stopCh)
+}
+
+func (c *ApiTokenClient) AddInformerHandlers(reh framework.ResourceEventHandler) {
+	if c.informer == nil {
+		panic(fmt.Sprintf("%s informer not started", "ApiToken"))
+	}
+
+	c.informer.AddEventHandler(reh)
+}
+
+func (c *ApiTokenClient) HasSynced() bool {
+	if c.informer == nil {
+		return false
+	}
+	return c.informer.HasSynced()
+}
+
+type ItemDefaultableApiTokenClient interface {
+	SetDefaults()
+}
+
+func (c *ApiTokenClient) NewEmpty() *v1.ApiToken {
+	item := &v1.ApiToken{}
+	item.GenerateName = "sitepod-apitoken-"
+	var aitem interface{}
+	aitem = item
+	if ditem, ok := aitem.(ItemDefaultableApiTokenClient); ok {
+		ditem.SetDefaults()
+	}
+
+	return item
+}
+
+//TODO: wrong location? shared?
+func (c *ApiTokenClient) KeyOf(obj interface{}) string {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func (c *ApiTokenClient) UIDOf(obj interface{}) (string, bool) {
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", false
+	}
+	return string(accessor.GetUID()), true
+}
+
+//TODO: wrong location? shared?
+func (c *ApiTokenClient) DeepEqual(a interface{}, b interface{}) bool {
+	return k8s_api.Semantic.DeepEqual(a, b)
+}
+
+func (c *ApiTokenClient) MaybeGetByKey(key string) (*v1.ApiToken, bool) {
+
+	if !strings.Contains(key, "/") && true {
+		key = fmt.Sprintf("%s/%s", c.ns, key)
+	}
+
+	iObj, exists, err := c.informer.GetStore().GetByKey(key)
+
+	if err != nil {
+		panic(err)
+	}
+
+	if iObj == nil {
+		return nil, exists
+	} else {
+		item := c.CloneItem(iObj)
+		glog.Infof("Got %s from informer store with rv %s", "ApiToken", item.ResourceVersion)
+		return item, exists
+	}
+}
+
+func (c *ApiTokenClient) GetByKey(key string) *v1.ApiToken {
+	item, exists := c.MaybeGetByKey(key)
+
+	if !exists {
+		panic("Not found " + "ApiToken" + ": " + key)
+	}
+
+	return item
+}
+
+func (c *ApiTokenClient) ByIndexByKey(index string, key string) []*v1.ApiToken {
+
+	items, err := c.informer.GetIndexer().ByIndex(index, key)
+
+	if err != nil {
+		panic(err)
+	}
+
+	typedItems := []*v1.ApiToken{}
+	for _, item := range items {
+		typedItems = append(typedItems, c.CloneItem(item))
+	}
+	return typedItems
+}
+
+func (c *ApiTokenClient) BySitepodKey(sitepodKey string) []*v1.ApiToken {
+	return c.ByIndexByKey("sitepod", sitepodKey)
+}
+
+func (c *ApiTokenClient) BySitepodKeyFunc() func(string) []interface{} {
+	return func(sitepodKey string) []interface{} {
+		iArray := []interface{}{}
+		for _, r := range c.ByIndexByKey("sitepod", sitepodKey) {
+			iArray = append(iArray, r)
+		}
+		return iArray
+	}
+}
+
+func (c *ApiTokenClient) MaybeSingleByUID(uid string) (*v1.ApiToken, bool) {
+	items := c.ByIndexByKey("uid", uid)
+	if len(items) == 0 {
+		return nil, false
+	} else {
+		return items[0], true
+	}
+}
+
+func (c *ApiTokenClient) SingleBySitepodKey(sitepodKey string) *v1.ApiToken {
+
+	items := c.BySitepodKey(sitepodKey)
+
+	if len(items) == 0 {
+		panic(errors.New("None found"))
+	}
+
+	return items[0]
+
+}
+
+func (c *ApiTokenClient) MaybeSingleBySitepodKey(sitepodKey string) (*v1.ApiToken, bool) {
+
+	items := c.BySitepodKey(sitepodKey)
+
+	if len(items) == 0 {
+		return nil, false
+	} else {
+
+		if len(items) > 1 {
+			glog.Warningf("Unexpected number of %s for sitepod %s - %d items matched", "ApiTokens", sitepodKey, len(items))
+		}
+
+		return items[0], true
+	}
+
+}
+
+type BeforeAdderApiTokenClient interface {
+	BeforeAdd()
+}
+
+func (c *ApiTokenClient) Add(target *v1.ApiToken) *v1.ApiToken {
+
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *ApiTokenClient) TryAdd(target *v1.ApiToken) (*v1.ApiToken, error) {
+
+	var itarget interface{}
+	itarget = target
+	if subject, ok := itarget.(BeforeAdderApiTokenClient); ok {
+		subject.BeforeAdd()
+	}
+
+	rcReq := c.rc.Post()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+
+	result := rcReq.Resource("ApiTokens").Body(target).Do()
+
+	if err := result.Error(); err != nil {
+		return nil, err
+	}
+
+	r, err := result.Get()
+
+	if err != nil {
+		return nil, err
+	}
+	item := r.(*v1.ApiToken)
+	glog.Infof("Added %s - %s (rv: %s)", "ApiToken", item.Name, item.ResourceVersion)
+	return item, nil
+}
+
+func (c *ApiTokenClient) CloneItem(orig interface{}) *v1.ApiToken {
+	cloned, err := conversion.NewCloner().DeepCopy(orig)
+	if err != nil {
+		panic(err)
+	}
+	return cloned.(*v1.ApiToken)
+}
+
+func (c *ApiTokenClient) Update(target *v1.ApiToken) *v1.ApiToken {
+
+	item, err := c.TryUpdate(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *ApiTokenClient) TryUpdate(target *v1.ApiToken) (*v1.ApiToken, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
+	rName := accessor.GetName()
+	rcReq := c.rc.Put()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	replacementTarget, err := rcReq.Resource("ApiTokens").Name(rName).Body(target).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := replacementTarget.(*v1.ApiToken)
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *ApiTokenClient) FetchByName(name string) (*v1.ApiToken, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("ApiTokens").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*v1.ApiToken)
+	return item, nil
+}
+
+func (c *ApiTokenClient) UpdateOrAdd(target *v1.ApiToken) *v1.ApiToken {
+
+	if len(string(target.UID)) > 0 {
+		return c.Update(target)
+	} else {
+		return c.Add(target)
+	}
+}
+
+func (c *ApiTokenClient) FetchList(s labels.Selector) []*v1.ApiToken {
+
+	var prc *restclient.Request
+	if !true {
+		prc = c.rc.Get().Resource("ApiTokens").LabelsSelectorParam(s)
+	} else {
+		prc = c.rc.Get().Resource("ApiTokens").Namespace(c.ns).LabelsSelectorParam(s)
+	}
+
+	rObj, err := prc.Do().Get()
+
+	if err != nil {
+		panic(err)
+	}
+
+	target := []*v1.ApiToken{}
+	kList := rObj.(*v1.ApiTokenList)
+	for _, kItem := range kList.Items {
+		target = append(target, c.CloneItem(&kItem))
+	}
+
+	return target
+}
+
+func (c *ApiTokenClient) TryDelete(target *v1.ApiToken) error {
+
+	var prc *restclient.Request
+	if !true {
+		prc = c.rc.Delete().Resource("ApiTokens").Name(target.Name)
+	} else {
+		prc = c.rc.Delete().Namespace(c.ns).Resource("ApiTokens").Name(target.Name)
+	}
+
+	err := prc.Do().Error()
+	return err
+}
+
+func (c *ApiTokenClient) Delete(target *v1.ApiToken) {
+
+	err := c.TryDelete(target)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (c *ApiTokenClient) DeleteFunc() func(interface{}) {
+	return func(iTarget interface{}) {
+
+		target := iTarget.(*v1.ApiToken)
+
+		err := c.TryDelete(target)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func (c *ApiTokenClient) List() []*v1.ApiToken {
+	kItems := c.informer.GetStore().List()
+	target := []*v1.ApiToken{}
+	for _, kItem := range kItems {
+		target = append(target, kItem.(*v1.ApiToken))
+	}
+	return target
+}
+
+func (c *ApiTokenClient) RestClient() *restclient.RESTClient {
+	return c.rc
+}
+
+func (c *ApiTokenClient) RestClientConfig() *restclient.Config {
+	return c.rcConfig
+}
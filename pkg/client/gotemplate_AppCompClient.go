@@ -243,6 +243,16 @@ type BeforeAdderAppCompClient interface {
 
 func (c *AppCompClient) Add(target *v1.Appcomponent) *v1.Appcomponent {
 
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *AppCompClient) TryAdd(target *v1.Appcomponent) (*v1.Appcomponent, error) {
+
 	var itarget interface{}
 	itarget = target
 	if subject, ok := itarget.(BeforeAdderAppCompClient); ok {
@@ -257,17 +267,17 @@ func (c *AppCompClient) Add(target *v1.Appcomponent) *v1.Appcomponent {
 	result := rcReq.Resource("AppComponents").Body(target).Do()
 
 	if err := result.Error(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	r, err := result.Get()
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := r.(*v1.Appcomponent)
 	glog.Infof("Added %s - %s (rv: %s)", "AppComponent", item.Name, item.ResourceVersion)
-	return item
+	return item, nil
 }
 
 func (c *AppCompClient) CloneItem(orig interface{}) *v1.Appcomponent {
@@ -280,10 +290,20 @@ func (c *AppCompClient) CloneItem(orig interface{}) *v1.Appcomponent {
 
 func (c *AppCompClient) Update(target *v1.Appcomponent) *v1.Appcomponent {
 
-	accessor, err := meta.Accessor(target)
+	item, err := c.TryUpdate(target)
 	if err != nil {
 		panic(err)
 	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *AppCompClient) TryUpdate(target *v1.Appcomponent) (*v1.Appcomponent, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
 	rName := accessor.GetName()
 	rcReq := c.rc.Put()
 	if true {
@@ -291,10 +311,25 @@ func (c *AppCompClient) Update(target *v1.Appcomponent) *v1.Appcomponent {
 	}
 	replacementTarget, err := rcReq.Resource("AppComponents").Name(rName).Body(target).Do().Get()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := replacementTarget.(*v1.Appcomponent)
-	return item
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *AppCompClient) FetchByName(name string) (*v1.Appcomponent, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("AppComponents").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*v1.Appcomponent)
+	return item, nil
 }
 
 func (c *AppCompClient) UpdateOrAdd(target *v1.Appcomponent) *v1.Appcomponent {
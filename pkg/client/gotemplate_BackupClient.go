@@ -0,0 +1,418 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang/glog"
+	k8s_api "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/meta"
+	ext_api "k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/client/cache"
+	"k8s.io/kubernetes/pkg/client/restclient"
+	"k8s.io/kubernetes/pkg/controller/framework"
+	"k8s.io/kubernetes/pkg/conversion"
+	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/runtime"
+	"reflect"
+	"sitepod.io/sitepod/pkg/api"
+	"sitepod.io/sitepod/pkg/api/v1"
+	"strings"
+	"time"
+)
+
+var (
+	resyncPeriodBackupClient = 5 * time.Minute
+)
+
+func HackImportIgnoredBackupClient(a k8s_api.Volume, b v1.Cluster, c1 ext_api.ThirdPartyResource) {
+}
+
+// template type ClientTmpl(ResourceType, ResourceListType, ResourceName, ResourcePluralName, Namespaced, DefaultGenName)
+
+type ResouceListTypeBackupClient []int
+
+type BackupClient struct {
+	rc            *restclient.RESTClient
+	rcConfig      *restclient.Config
+	ns            string
+	supportedType reflect.Type
+	informer      framework.SharedIndexInformer
+}
+
+func NewBackupClient(rc *restclient.RESTClient, config *restclient.Config, ns string) *BackupClient {
+	c := &BackupClient{
+		rc:            rc,
+		rcConfig:      config,
+		supportedType: reflect.TypeOf(&v1.Backup{}),
+	}
+
+	if true {
+		c.ns = ns
+	}
+
+	pc := runtime.NewParameterCodec(k8s_api.Scheme)
+
+	indexers := make(cache.Indexers)
+	indexers["sitepod"] = func(obj interface{}) ([]string, error) {
+		accessor, _ := meta.Accessor(obj)
+		labels := accessor.GetLabels()
+		if _, ok := labels["sitepod"]; ok {
+			return []string{labels["sitepod"]}, nil
+		} else {
+			return []string{}, nil
+		}
+	}
+
+	indexers["uid"] = func(obj interface{}) ([]string, error) {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			panic(err)
+		}
+		return []string{string(accessor.GetUID())}, nil
+	}
+
+	c.informer = framework.NewSharedIndexInformer(
+		api.NewListWatchFromClient(c.rc, "Backups", c.ns, nil, pc),
+		&v1.Backup{},
+		resyncPeriodBackupClient,
+		indexers,
+	)
+
+	return c
+}
+
+func (c *BackupClient) StartInformer(stopCh <-chan struct{}) {
+	c.informer.Run(stopCh)
+}
+
+func (c *BackupClient) AddInformerHandlers(reh framework.ResourceEventHandler) {
+	if c.informer == nil {
+		panic(fmt.Sprintf("%s informer not started", "Backup"))
+	}
+
+	c.informer.AddEventHandler(reh)
+}
+
+func (c *BackupClient) HasSynced() bool {
+	if c.informer == nil {
+		return false
+	}
+	return c.informer.HasSynced()
+}
+
+type ItemDefaultableBackupClient interface {
+	SetDefaults()
+}
+
+func (c *BackupClient) NewEmpty() *v1.Backup {
+	item := &v1.Backup{}
+	item.GenerateName = "sitepod-backup-"
+	var aitem interface{}
+	aitem = item
+	if ditem, ok := aitem.(ItemDefaultableBackupClient); ok {
+		ditem.SetDefaults()
+	}
+
+	return item
+}
+
+//TODO: wrong location? shared?
+func (c *BackupClient) KeyOf(obj interface{}) string {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func (c *BackupClient) UIDOf(obj interface{}) (string, bool) {
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", false
+	}
+	return string(accessor.GetUID()), true
+}
+
+//TODO: wrong location? shared?
+func (c *BackupClient) DeepEqual(a interface{}, b interface{}) bool {
+	return k8s_api.Semantic.DeepEqual(a, b)
+}
+
+func (c *BackupClient) MaybeGetByKey(key string) (*v1.Backup, bool) {
+
+	if !strings.Contains(key, "/") && true {
+		key = fmt.Sprintf("%s/%s", c.ns, key)
+	}
+
+	iObj, exists, err := c.informer.GetStore().GetByKey(key)
+
+	if err != nil {
+		panic(err)
+	}
+
+	if iObj == nil {
+		return nil, exists
+	} else {
+		item := c.CloneItem(iObj)
+		glog.Infof("Got %s from informer store with rv %s", "Backup", item.ResourceVersion)
+		return item, exists
+	}
+}
+
+func (c *BackupClient) GetByKey(key string) *v1.Backup {
+	item, exists := c.MaybeGetByKey(key)
+
+	if !exists {
+		panic("Not found " + "Backup" + ": " + key)
+	}
+
+	return item
+}
+
+func (c *BackupClient) ByIndexByKey(index string, key string) []*v1.Backup {
+
+	items, err := c.informer.GetIndexer().ByIndex(index, key)
+
+	if err != nil {
+		panic(err)
+	}
+
+	typedItems := []*v1.Backup{}
+	for _, item := range items {
+		typedItems = append(typedItems, c.CloneItem(item))
+	}
+	return typedItems
+}
+
+func (c *BackupClient) BySitepodKey(sitepodKey string) []*v1.Backup {
+	return c.ByIndexByKey("sitepod", sitepodKey)
+}
+
+func (c *BackupClient) BySitepodKeyFunc() func(string) []interface{} {
+	return func(sitepodKey string) []interface{} {
+		iArray := []interface{}{}
+		for _, r := range c.ByIndexByKey("sitepod", sitepodKey) {
+			iArray = append(iArray, r)
+		}
+		return iArray
+	}
+}
+
+func (c *BackupClient) MaybeSingleByUID(uid string) (*v1.Backup, bool) {
+	items := c.ByIndexByKey("uid", uid)
+	if len(items) == 0 {
+		return nil, false
+	} else {
+		return items[0], true
+	}
+}
+
+func (c *BackupClient) SingleBySitepodKey(sitepodKey string) *v1.Backup {
+
+	items := c.BySitepodKey(sitepodKey)
+
+	if len(items) == 0 {
+		panic(errors.New("None found"))
+	}
+
+	return items[0]
+
+}
+
+func (c *BackupClient) MaybeSingleBySitepodKey(sitepodKey string) (*v1.Backup, bool) {
+
+	items := c.BySitepodKey(sitepodKey)
+
+	if len(items) == 0 {
+		return nil, false
+	} else {
+
+		if len(items) > 1 {
+			glog.Warningf("Unexpected number of %s for sitepod %s - %d items matched", "Backups", sitepodKey, len(items))
+		}
+
+		return items[0], true
+	}
+
+}
+
+type BeforeAdderBackupClient interface {
+	BeforeAdd()
+}
+
+func (c *BackupClient) Add(target *v1.Backup) *v1.Backup {
+
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *BackupClient) TryAdd(target *v1.Backup) (*v1.Backup, error) {
+
+	var itarget interface{}
+	itarget = target
+	if subject, ok := itarget.(BeforeAdderBackupClient); ok {
+		subject.BeforeAdd()
+	}
+
+	rcReq := c.rc.Post()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+
+	result := rcReq.Resource("Backups").Body(target).Do()
+
+	if err := result.Error(); err != nil {
+		return nil, err
+	}
+
+	r, err := result.Get()
+
+	if err != nil {
+		return nil, err
+	}
+	item := r.(*v1.Backup)
+	glog.Infof("Added %s - %s (rv: %s)", "Backup", item.Name, item.ResourceVersion)
+	return item, nil
+}
+
+func (c *BackupClient) CloneItem(orig interface{}) *v1.Backup {
+	cloned, err := conversion.NewCloner().DeepCopy(orig)
+	if err != nil {
+		panic(err)
+	}
+	return cloned.(*v1.Backup)
+}
+
+func (c *BackupClient) Update(target *v1.Backup) *v1.Backup {
+
+	item, err := c.TryUpdate(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *BackupClient) TryUpdate(target *v1.Backup) (*v1.Backup, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
+	rName := accessor.GetName()
+	rcReq := c.rc.Put()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	replacementTarget, err := rcReq.Resource("Backups").Name(rName).Body(target).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := replacementTarget.(*v1.Backup)
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *BackupClient) FetchByName(name string) (*v1.Backup, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("Backups").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*v1.Backup)
+	return item, nil
+}
+
+func (c *BackupClient) UpdateOrAdd(target *v1.Backup) *v1.Backup {
+
+	if len(string(target.UID)) > 0 {
+		return c.Update(target)
+	} else {
+		return c.Add(target)
+	}
+}
+
+func (c *BackupClient) FetchList(s labels.Selector) []*v1.Backup {
+
+	var prc *restclient.Request
+	if !true {
+		prc = c.rc.Get().Resource("Backups").LabelsSelectorParam(s)
+	} else {
+		prc = c.rc.Get().Resource("Backups").Namespace(c.ns).LabelsSelectorParam(s)
+	}
+
+	rObj, err := prc.Do().Get()
+
+	if err != nil {
+		panic(err)
+	}
+
+	target := []*v1.Backup{}
+	kList := rObj.(*v1.BackupList)
+	for _, kItem := range kList.Items {
+		target = append(target, c.CloneItem(&kItem))
+	}
+
+	return target
+}
+
+func (c *BackupClient) TryDelete(target *v1.Backup) error {
+
+	var prc *restclient.Request
+	if !true {
+		prc = c.rc.Delete().Resource("Backups").Name(target.Name)
+	} else {
+		prc = c.rc.Delete().Namespace(c.ns).Resource("Backups").Name(target.Name)
+	}
+
+	err := prc.Do().Error()
+	return err
+}
+
+func (c *BackupClient) Delete(target *v1.Backup) {
+
+	err := c.TryDelete(target)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (c *BackupClient) DeleteFunc() func(interface{}) {
+	return func(iTarget interface{}) {
+
+		target := iTarget.(*v1.Backup)
+
+		err := c.TryDelete(target)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func (c *BackupClient) List() []*v1.Backup {
+	kItems := c.informer.GetStore().List()
+	target := []*v1.Backup{}
+	for _, kItem := range kItems {
+		target = append(target, kItem.(*v1.Backup))
+	}
+	return target
+}
+
+func (c *BackupClient) RestClient() *restclient.RESTClient {
+	return c.rc
+}
+
+func (c *BackupClient) RestClientConfig() *restclient.Config {
+	return c.rcConfig
+}
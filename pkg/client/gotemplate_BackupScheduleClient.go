@@ -0,0 +1,418 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang/glog"
+	k8s_api "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/meta"
+	ext_api "k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/client/cache"
+	"k8s.io/kubernetes/pkg/client/restclient"
+	"k8s.io/kubernetes/pkg/controller/framework"
+	"k8s.io/kubernetes/pkg/conversion"
+	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/runtime"
+	"reflect"
+	"sitepod.io/sitepod/pkg/api"
+	"sitepod.io/sitepod/pkg/api/v1"
+	"strings"
+	"time"
+)
+
+var (
+	resyncPeriodBackupScheduleClient = 5 * time.Minute
+)
+
+func HackImportIgnoredBackupScheduleClient(a k8s_api.Volume, b v1.Cluster, c1 ext_api.ThirdPartyResource) {
+}
+
+// template type ClientTmpl(ResourceType, ResourceListType, ResourceName, ResourcePluralName, Namespaced, DefaultGenName)
+
+type ResouceListTypeBackupScheduleClient []int
+
+type BackupScheduleClient struct {
+	rc            *restclient.RESTClient
+	rcConfig      *restclient.Config
+	ns            string
+	supportedType reflect.Type
+	informer      framework.SharedIndexInformer
+}
+
+func NewBackupScheduleClient(rc *restclient.RESTClient, config *restclient.Config, ns string) *BackupScheduleClient {
+	c := &BackupScheduleClient{
+		rc:            rc,
+		rcConfig:      config,
+		supportedType: reflect.TypeOf(&v1.Backupschedule{}),
+	}
+
+	if true {
+		c.ns = ns
+	}
+
+	pc := runtime.NewParameterCodec(k8s_api.Scheme)
+
+	indexers := make(cache.Indexers)
+	indexers["sitepod"] = func(obj interface{}) ([]string, error) {
+		accessor, _ := meta.Accessor(obj)
+		labels := accessor.GetLabels()
+		if _, ok := labels["sitepod"]; ok {
+			return []string{labels["sitepod"]}, nil
+		} else {
+			return []string{}, nil
+		}
+	}
+
+	indexers["uid"] = func(obj interface{}) ([]string, error) {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			panic(err)
+		}
+		return []string{string(accessor.GetUID())}, nil
+	}
+
+	c.informer = framework.NewSharedIndexInformer(
+		api.NewListWatchFromClient(c.rc, "BackupSchedules", c.ns, nil, pc),
+		&v1.Backupschedule{},
+		resyncPeriodBackupScheduleClient,
+		indexers,
+	)
+
+	return c
+}
+
+func (c *BackupScheduleClient) StartInformer(stopCh <-chan struct{}) {
+	c.informer.Run(stopCh)
+}
+
+func (c *BackupScheduleClient) AddInformerHandlers(reh framework.ResourceEventHandler) {
+	if c.informer == nil {
+		panic(fmt.Sprintf("%s informer not started", "BackupSchedule"))
+	}
+
+	c.informer.AddEventHandler(reh)
+}
+
+func (c *BackupScheduleClient) HasSynced() bool {
+	if c.informer == nil {
+		return false
+	}
+	return c.informer.HasSynced()
+}
+
+type ItemDefaultableBackupScheduleClient interface {
+	SetDefaults()
+}
+
+func (c *BackupScheduleClient) NewEmpty() *v1.Backupschedule {
+	item := &v1.Backupschedule{}
+	item.GenerateName = "sitepod-backupschedule-"
+	var aitem interface{}
+	aitem = item
+	if ditem, ok := aitem.(ItemDefaultableBackupScheduleClient); ok {
+		ditem.SetDefaults()
+	}
+
+	return item
+}
+
+//TODO: wrong location? shared?
+func (c *BackupScheduleClient) KeyOf(obj interface{}) string {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func (c *BackupScheduleClient) UIDOf(obj interface{}) (string, bool) {
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", false
+	}
+	return string(accessor.GetUID()), true
+}
+
+//TODO: wrong location? shared?
+func (c *BackupScheduleClient) DeepEqual(a interface{}, b interface{}) bool {
+	return k8s_api.Semantic.DeepEqual(a, b)
+}
+
+func (c *BackupScheduleClient) MaybeGetByKey(key string) (*v1.Backupschedule, bool) {
+
+	if !strings.Contains(key, "/") && true {
+		key = fmt.Sprintf("%s/%s", c.ns, key)
+	}
+
+	iObj, exists, err := c.informer.GetStore().GetByKey(key)
+
+	if err != nil {
+		panic(err)
+	}
+
+	if iObj == nil {
+		return nil, exists
+	} else {
+		item := c.CloneItem(iObj)
+		glog.Infof("Got %s from informer store with rv %s", "BackupSchedule", item.ResourceVersion)
+		return item, exists
+	}
+}
+
+func (c *BackupScheduleClient) GetByKey(key string) *v1.Backupschedule {
+	item, exists := c.MaybeGetByKey(key)
+
+	if !exists {
+		panic("Not found " + "BackupSchedule" + ": " + key)
+	}
+
+	return item
+}
+
+func (c *BackupScheduleClient) ByIndexByKey(index string, key string) []*v1.Backupschedule {
+
+	items, err := c.informer.GetIndexer().ByIndex(index, key)
+
+	if err != nil {
+		panic(err)
+	}
+
+	typedItems := []*v1.Backupschedule{}
+	for _, item := range items {
+		typedItems = append(typedItems, c.CloneItem(item))
+	}
+	return typedItems
+}
+
+func (c *BackupScheduleClient) BySitepodKey(sitepodKey string) []*v1.Backupschedule {
+	return c.ByIndexByKey("sitepod", sitepodKey)
+}
+
+func (c *BackupScheduleClient) BySitepodKeyFunc() func(string) []interface{} {
+	return func(sitepodKey string) []interface{} {
+		iArray := []interface{}{}
+		for _, r := range c.ByIndexByKey("sitepod", sitepodKey) {
+			iArray = append(iArray, r)
+		}
+		return iArray
+	}
+}
+
+func (c *BackupScheduleClient) MaybeSingleByUID(uid string) (*v1.Backupschedule, bool) {
+	items := c.ByIndexByKey("uid", uid)
+	if len(items) == 0 {
+		return nil, false
+	} else {
+		return items[0], true
+	}
+}
+
+func (c *BackupScheduleClient) SingleBySitepodKey(sitepodKey string) *v1.Backupschedule {
+
+	items := c.BySitepodKey(sitepodKey)
+
+	if len(items) == 0 {
+		panic(errors.New("None found"))
+	}
+
+	return items[0]
+
+}
+
+func (c *BackupScheduleClient) MaybeSingleBySitepodKey(sitepodKey string) (*v1.Backupschedule, bool) {
+
+	items := c.BySitepodKey(sitepodKey)
+
+	if len(items) == 0 {
+		return nil, false
+	} else {
+
+		if len(items) > 1 {
+			glog.Warningf("Unexpected number of %s for sitepod %s - %d items matched", "BackupSchedules", sitepodKey, len(items))
+		}
+
+		return items[0], true
+	}
+
+}
+
+type BeforeAdderBackupScheduleClient interface {
+	BeforeAdd()
+}
+
+func (c *BackupScheduleClient) Add(target *v1.Backupschedule) *v1.Backupschedule {
+
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *BackupScheduleClient) TryAdd(target *v1.Backupschedule) (*v1.Backupschedule, error) {
+
+	var itarget interface{}
+	itarget = target
+	if subject, ok := itarget.(BeforeAdderBackupScheduleClient); ok {
+		subject.BeforeAdd()
+	}
+
+	rcReq := c.rc.Post()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+
+	result := rcReq.Resource("BackupSchedules").Body(target).Do()
+
+	if err := result.Error(); err != nil {
+		return nil, err
+	}
+
+	r, err := result.Get()
+
+	if err != nil {
+		return nil, err
+	}
+	item := r.(*v1.Backupschedule)
+	glog.Infof("Added %s - %s (rv: %s)", "BackupSchedule", item.Name, item.ResourceVersion)
+	return item, nil
+}
+
+func (c *BackupScheduleClient) CloneItem(orig interface{}) *v1.Backupschedule {
+	cloned, err := conversion.NewCloner().DeepCopy(orig)
+	if err != nil {
+		panic(err)
+	}
+	return cloned.(*v1.Backupschedule)
+}
+
+func (c *BackupScheduleClient) Update(target *v1.Backupschedule) *v1.Backupschedule {
+
+	item, err := c.TryUpdate(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *BackupScheduleClient) TryUpdate(target *v1.Backupschedule) (*v1.Backupschedule, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
+	rName := accessor.GetName()
+	rcReq := c.rc.Put()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	replacementTarget, err := rcReq.Resource("BackupSchedules").Name(rName).Body(target).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := replacementTarget.(*v1.Backupschedule)
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *BackupScheduleClient) FetchByName(name string) (*v1.Backupschedule, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("BackupSchedules").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*v1.Backupschedule)
+	return item, nil
+}
+
+func (c *BackupScheduleClient) UpdateOrAdd(target *v1.Backupschedule) *v1.Backupschedule {
+
+	if len(string(target.UID)) > 0 {
+		return c.Update(target)
+	} else {
+		return c.Add(target)
+	}
+}
+
+func (c *BackupScheduleClient) FetchList(s labels.Selector) []*v1.Backupschedule {
+
+	var prc *restclient.Request
+	if !true {
+		prc = c.rc.Get().Resource("BackupSchedules").LabelsSelectorParam(s)
+	} else {
+		prc = c.rc.Get().Resource("BackupSchedules").Namespace(c.ns).LabelsSelectorParam(s)
+	}
+
+	rObj, err := prc.Do().Get()
+
+	if err != nil {
+		panic(err)
+	}
+
+	target := []*v1.Backupschedule{}
+	kList := rObj.(*v1.BackupscheduleList)
+	for _, kItem := range kList.Items {
+		target = append(target, c.CloneItem(&kItem))
+	}
+
+	return target
+}
+
+func (c *BackupScheduleClient) TryDelete(target *v1.Backupschedule) error {
+
+	var prc *restclient.Request
+	if !true {
+		prc = c.rc.Delete().Resource("BackupSchedules").Name(target.Name)
+	} else {
+		prc = c.rc.Delete().Namespace(c.ns).Resource("BackupSchedules").Name(target.Name)
+	}
+
+	err := prc.Do().Error()
+	return err
+}
+
+func (c *BackupScheduleClient) Delete(target *v1.Backupschedule) {
+
+	err := c.TryDelete(target)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (c *BackupScheduleClient) DeleteFunc() func(interface{}) {
+	return func(iTarget interface{}) {
+
+		target := iTarget.(*v1.Backupschedule)
+
+		err := c.TryDelete(target)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func (c *BackupScheduleClient) List() []*v1.Backupschedule {
+	kItems := c.informer.GetStore().List()
+	target := []*v1.Backupschedule{}
+	for _, kItem := range kItems {
+		target = append(target, kItem.(*v1.Backupschedule))
+	}
+	return target
+}
+
+func (c *BackupScheduleClient) RestClient() *restclient.RESTClient {
+	return c.rc
+}
+
+func (c *BackupScheduleClient) RestClientConfig() *restclient.Config {
+	return c.rcConfig
+}
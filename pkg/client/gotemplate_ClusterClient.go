@@ -243,6 +243,16 @@ type BeforeAdderClusterClient interface {
 
 func (c *ClusterClient) Add(target *v1.Cluster) *v1.Cluster {
 
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *ClusterClient) TryAdd(target *v1.Cluster) (*v1.Cluster, error) {
+
 	var itarget interface{}
 	itarget = target
 	if subject, ok := itarget.(BeforeAdderClusterClient); ok {
@@ -257,17 +267,17 @@ func (c *ClusterClient) Add(target *v1.Cluster) *v1.Cluster {
 	result := rcReq.Resource("Clusters").Body(target).Do()
 
 	if err := result.Error(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	r, err := result.Get()
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := r.(*v1.Cluster)
 	glog.Infof("Added %s - %s (rv: %s)", "Cluster", item.Name, item.ResourceVersion)
-	return item
+	return item, nil
 }
 
 func (c *ClusterClient) CloneItem(orig interface{}) *v1.Cluster {
@@ -280,10 +290,20 @@ func (c *ClusterClient) CloneItem(orig interface{}) *v1.Cluster {
 
 func (c *ClusterClient) Update(target *v1.Cluster) *v1.Cluster {
 
-	accessor, err := meta.Accessor(target)
+	item, err := c.TryUpdate(target)
 	if err != nil {
 		panic(err)
 	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *ClusterClient) TryUpdate(target *v1.Cluster) (*v1.Cluster, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
 	rName := accessor.GetName()
 	rcReq := c.rc.Put()
 	if true {
@@ -291,10 +311,25 @@ func (c *ClusterClient) Update(target *v1.Cluster) *v1.Cluster {
 	}
 	replacementTarget, err := rcReq.Resource("Clusters").Name(rName).Body(target).Do().Get()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := replacementTarget.(*v1.Cluster)
-	return item
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *ClusterClient) FetchByName(name string) (*v1.Cluster, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("Clusters").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*v1.Cluster)
+	return item, nil
 }
 
 func (c *ClusterClient) UpdateOrAdd(target *v1.Cluster) *v1.Cluster {
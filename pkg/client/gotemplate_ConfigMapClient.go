@@ -243,6 +243,16 @@ type BeforeAdderConfigMapClient interface {
 
 func (c *ConfigMapClient) Add(target *k8s_api.ConfigMap) *k8s_api.ConfigMap {
 
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *ConfigMapClient) TryAdd(target *k8s_api.ConfigMap) (*k8s_api.ConfigMap, error) {
+
 	var itarget interface{}
 	itarget = target
 	if subject, ok := itarget.(BeforeAdderConfigMapClient); ok {
@@ -257,17 +267,17 @@ func (c *ConfigMapClient) Add(target *k8s_api.ConfigMap) *k8s_api.ConfigMap {
 	result := rcReq.Resource("ConfigMaps").Body(target).Do()
 
 	if err := result.Error(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	r, err := result.Get()
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := r.(*k8s_api.ConfigMap)
 	glog.Infof("Added %s - %s (rv: %s)", "ConfigMap", item.Name, item.ResourceVersion)
-	return item
+	return item, nil
 }
 
 func (c *ConfigMapClient) CloneItem(orig interface{}) *k8s_api.ConfigMap {
@@ -280,10 +290,20 @@ func (c *ConfigMapClient) CloneItem(orig interface{}) *k8s_api.ConfigMap {
 
 func (c *ConfigMapClient) Update(target *k8s_api.ConfigMap) *k8s_api.ConfigMap {
 
-	accessor, err := meta.Accessor(target)
+	item, err := c.TryUpdate(target)
 	if err != nil {
 		panic(err)
 	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *ConfigMapClient) TryUpdate(target *k8s_api.ConfigMap) (*k8s_api.ConfigMap, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
 	rName := accessor.GetName()
 	rcReq := c.rc.Put()
 	if true {
@@ -291,10 +311,25 @@ func (c *ConfigMapClient) Update(target *k8s_api.ConfigMap) *k8s_api.ConfigMap {
 	}
 	replacementTarget, err := rcReq.Resource("ConfigMaps").Name(rName).Body(target).Do().Get()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := replacementTarget.(*k8s_api.ConfigMap)
-	return item
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *ConfigMapClient) FetchByName(name string) (*k8s_api.ConfigMap, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("ConfigMaps").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*k8s_api.ConfigMap)
+	return item, nil
 }
 
 func (c *ConfigMapClient) UpdateOrAdd(target *k8s_api.ConfigMap) *k8s_api.ConfigMap {
@@ -243,6 +243,16 @@ type BeforeAdderDeploymentClient interface {
 
 func (c *DeploymentClient) Add(target *ext_api.Deployment) *ext_api.Deployment {
 
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *DeploymentClient) TryAdd(target *ext_api.Deployment) (*ext_api.Deployment, error) {
+
 	var itarget interface{}
 	itarget = target
 	if subject, ok := itarget.(BeforeAdderDeploymentClient); ok {
@@ -257,17 +267,17 @@ func (c *DeploymentClient) Add(target *ext_api.Deployment) *ext_api.Deployment {
 	result := rcReq.Resource("Deployments").Body(target).Do()
 
 	if err := result.Error(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	r, err := result.Get()
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := r.(*ext_api.Deployment)
 	glog.Infof("Added %s - %s (rv: %s)", "Deployment", item.Name, item.ResourceVersion)
-	return item
+	return item, nil
 }
 
 func (c *DeploymentClient) CloneItem(orig interface{}) *ext_api.Deployment {
@@ -280,10 +290,20 @@ func (c *DeploymentClient) CloneItem(orig interface{}) *ext_api.Deployment {
 
 func (c *DeploymentClient) Update(target *ext_api.Deployment) *ext_api.Deployment {
 
-	accessor, err := meta.Accessor(target)
+	item, err := c.TryUpdate(target)
 	if err != nil {
 		panic(err)
 	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *DeploymentClient) TryUpdate(target *ext_api.Deployment) (*ext_api.Deployment, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
 	rName := accessor.GetName()
 	rcReq := c.rc.Put()
 	if true {
@@ -291,10 +311,25 @@ func (c *DeploymentClient) Update(target *ext_api.Deployment) *ext_api.Deploymen
 	}
 	replacementTarget, err := rcReq.Resource("Deployments").Name(rName).Body(target).Do().Get()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := replacementTarget.(*ext_api.Deployment)
-	return item
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *DeploymentClient) FetchByName(name string) (*ext_api.Deployment, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("Deployments").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*ext_api.Deployment)
+	return item, nil
 }
 
 func (c *DeploymentClient) UpdateOrAdd(target *ext_api.Deployment) *ext_api.Deployment {
@@ -243,6 +243,16 @@ type BeforeAdderPVClaimClient interface {
 
 func (c *PVClaimClient) Add(target *k8s_api.PersistentVolumeClaim) *k8s_api.PersistentVolumeClaim {
 
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *PVClaimClient) TryAdd(target *k8s_api.PersistentVolumeClaim) (*k8s_api.PersistentVolumeClaim, error) {
+
 	var itarget interface{}
 	itarget = target
 	if subject, ok := itarget.(BeforeAdderPVClaimClient); ok {
@@ -257,17 +267,17 @@ func (c *PVClaimClient) Add(target *k8s_api.PersistentVolumeClaim) *k8s_api.Pers
 	result := rcReq.Resource("PersistentVolumeClaims").Body(target).Do()
 
 	if err := result.Error(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	r, err := result.Get()
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := r.(*k8s_api.PersistentVolumeClaim)
 	glog.Infof("Added %s - %s (rv: %s)", "PersistentVolumeClaim", item.Name, item.ResourceVersion)
-	return item
+	return item, nil
 }
 
 func (c *PVClaimClient) CloneItem(orig interface{}) *k8s_api.PersistentVolumeClaim {
@@ -280,10 +290,20 @@ func (c *PVClaimClient) CloneItem(orig interface{}) *k8s_api.PersistentVolumeCla
 
 func (c *PVClaimClient) Update(target *k8s_api.PersistentVolumeClaim) *k8s_api.PersistentVolumeClaim {
 
-	accessor, err := meta.Accessor(target)
+	item, err := c.TryUpdate(target)
 	if err != nil {
 		panic(err)
 	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *PVClaimClient) TryUpdate(target *k8s_api.PersistentVolumeClaim) (*k8s_api.PersistentVolumeClaim, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
 	rName := accessor.GetName()
 	rcReq := c.rc.Put()
 	if true {
@@ -291,10 +311,25 @@ func (c *PVClaimClient) Update(target *k8s_api.PersistentVolumeClaim) *k8s_api.P
 	}
 	replacementTarget, err := rcReq.Resource("PersistentVolumeClaims").Name(rName).Body(target).Do().Get()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := replacementTarget.(*k8s_api.PersistentVolumeClaim)
-	return item
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *PVClaimClient) FetchByName(name string) (*k8s_api.PersistentVolumeClaim, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("PersistentVolumeClaims").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*k8s_api.PersistentVolumeClaim)
+	return item, nil
 }
 
 func (c *PVClaimClient) UpdateOrAdd(target *k8s_api.PersistentVolumeClaim) *k8s_api.PersistentVolumeClaim {
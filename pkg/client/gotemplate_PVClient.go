@@ -243,6 +243,16 @@ type BeforeAdderPVClient interface {
 
 func (c *PVClient) Add(target *k8s_api.PersistentVolume) *k8s_api.PersistentVolume {
 
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *PVClient) TryAdd(target *k8s_api.PersistentVolume) (*k8s_api.PersistentVolume, error) {
+
 	var itarget interface{}
 	itarget = target
 	if subject, ok := itarget.(BeforeAdderPVClient); ok {
@@ -257,17 +267,17 @@ func (c *PVClient) Add(target *k8s_api.PersistentVolume) *k8s_api.PersistentVolu
 	result := rcReq.Resource("PersistentVolumes").Body(target).Do()
 
 	if err := result.Error(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	r, err := result.Get()
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := r.(*k8s_api.PersistentVolume)
 	glog.Infof("Added %s - %s (rv: %s)", "PersistentVolume", item.Name, item.ResourceVersion)
-	return item
+	return item, nil
 }
 
 func (c *PVClient) CloneItem(orig interface{}) *k8s_api.PersistentVolume {
@@ -280,10 +290,20 @@ func (c *PVClient) CloneItem(orig interface{}) *k8s_api.PersistentVolume {
 
 func (c *PVClient) Update(target *k8s_api.PersistentVolume) *k8s_api.PersistentVolume {
 
-	accessor, err := meta.Accessor(target)
+	item, err := c.TryUpdate(target)
 	if err != nil {
 		panic(err)
 	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *PVClient) TryUpdate(target *k8s_api.PersistentVolume) (*k8s_api.PersistentVolume, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
 	rName := accessor.GetName()
 	rcReq := c.rc.Put()
 	if false {
@@ -291,10 +311,25 @@ func (c *PVClient) Update(target *k8s_api.PersistentVolume) *k8s_api.PersistentV
 	}
 	replacementTarget, err := rcReq.Resource("PersistentVolumes").Name(rName).Body(target).Do().Get()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := replacementTarget.(*k8s_api.PersistentVolume)
-	return item
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *PVClient) FetchByName(name string) (*k8s_api.PersistentVolume, error) {
+
+	rcReq := c.rc.Get()
+	if false {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("PersistentVolumes").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*k8s_api.PersistentVolume)
+	return item, nil
 }
 
 func (c *PVClient) UpdateOrAdd(target *k8s_api.PersistentVolume) *k8s_api.PersistentVolume {
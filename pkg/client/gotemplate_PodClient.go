@@ -243,6 +243,16 @@ type BeforeAdderPodClient interface {
 
 func (c *PodClient) Add(target *k8s_api.Pod) *k8s_api.Pod {
 
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *PodClient) TryAdd(target *k8s_api.Pod) (*k8s_api.Pod, error) {
+
 	var itarget interface{}
 	itarget = target
 	if subject, ok := itarget.(BeforeAdderPodClient); ok {
@@ -257,17 +267,17 @@ func (c *PodClient) Add(target *k8s_api.Pod) *k8s_api.Pod {
 	result := rcReq.Resource("Pods").Body(target).Do()
 
 	if err := result.Error(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	r, err := result.Get()
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := r.(*k8s_api.Pod)
 	glog.Infof("Added %s - %s (rv: %s)", "Pod", item.Name, item.ResourceVersion)
-	return item
+	return item, nil
 }
 
 func (c *PodClient) CloneItem(orig interface{}) *k8s_api.Pod {
@@ -280,10 +290,20 @@ func (c *PodClient) CloneItem(orig interface{}) *k8s_api.Pod {
 
 func (c *PodClient) Update(target *k8s_api.Pod) *k8s_api.Pod {
 
-	accessor, err := meta.Accessor(target)
+	item, err := c.TryUpdate(target)
 	if err != nil {
 		panic(err)
 	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *PodClient) TryUpdate(target *k8s_api.Pod) (*k8s_api.Pod, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
 	rName := accessor.GetName()
 	rcReq := c.rc.Put()
 	if true {
@@ -291,10 +311,25 @@ func (c *PodClient) Update(target *k8s_api.Pod) *k8s_api.Pod {
 	}
 	replacementTarget, err := rcReq.Resource("Pods").Name(rName).Body(target).Do().Get()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := replacementTarget.(*k8s_api.Pod)
-	return item
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *PodClient) FetchByName(name string) (*k8s_api.Pod, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("Pods").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*k8s_api.Pod)
+	return item, nil
 }
 
 func (c *PodClient) UpdateOrAdd(target *k8s_api.Pod) *k8s_api.Pod {
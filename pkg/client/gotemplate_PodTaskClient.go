@@ -243,6 +243,16 @@ type BeforeAdderPodTaskClient interface {
 
 func (c *PodTaskClient) Add(target *v1.Podtask) *v1.Podtask {
 
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *PodTaskClient) TryAdd(target *v1.Podtask) (*v1.Podtask, error) {
+
 	var itarget interface{}
 	itarget = target
 	if subject, ok := itarget.(BeforeAdderPodTaskClient); ok {
@@ -257,17 +267,17 @@ func (c *PodTaskClient) Add(target *v1.Podtask) *v1.Podtask {
 	result := rcReq.Resource("PodTasks").Body(target).Do()
 
 	if err := result.Error(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	r, err := result.Get()
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := r.(*v1.Podtask)
 	glog.Infof("Added %s - %s (rv: %s)", "PodTask", item.Name, item.ResourceVersion)
-	return item
+	return item, nil
 }
 
 func (c *PodTaskClient) CloneItem(orig interface{}) *v1.Podtask {
@@ -280,10 +290,20 @@ func (c *PodTaskClient) CloneItem(orig interface{}) *v1.Podtask {
 
 func (c *PodTaskClient) Update(target *v1.Podtask) *v1.Podtask {
 
-	accessor, err := meta.Accessor(target)
+	item, err := c.TryUpdate(target)
 	if err != nil {
 		panic(err)
 	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *PodTaskClient) TryUpdate(target *v1.Podtask) (*v1.Podtask, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
 	rName := accessor.GetName()
 	rcReq := c.rc.Put()
 	if true {
@@ -291,10 +311,25 @@ func (c *PodTaskClient) Update(target *v1.Podtask) *v1.Podtask {
 	}
 	replacementTarget, err := rcReq.Resource("PodTasks").Name(rName).Body(target).Do().Get()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := replacementTarget.(*v1.Podtask)
-	return item
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *PodTaskClient) FetchByName(name string) (*v1.Podtask, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("PodTasks").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*v1.Podtask)
+	return item, nil
 }
 
 func (c *PodTaskClient) UpdateOrAdd(target *v1.Podtask) *v1.Podtask {
@@ -243,6 +243,16 @@ type BeforeAdderReplicaSetClient interface {
 
 func (c *ReplicaSetClient) Add(target *ext_api.ReplicaSet) *ext_api.ReplicaSet {
 
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *ReplicaSetClient) TryAdd(target *ext_api.ReplicaSet) (*ext_api.ReplicaSet, error) {
+
 	var itarget interface{}
 	itarget = target
 	if subject, ok := itarget.(BeforeAdderReplicaSetClient); ok {
@@ -257,17 +267,17 @@ func (c *ReplicaSetClient) Add(target *ext_api.ReplicaSet) *ext_api.ReplicaSet {
 	result := rcReq.Resource("ReplicaSets").Body(target).Do()
 
 	if err := result.Error(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	r, err := result.Get()
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := r.(*ext_api.ReplicaSet)
 	glog.Infof("Added %s - %s (rv: %s)", "ReplicaSet", item.Name, item.ResourceVersion)
-	return item
+	return item, nil
 }
 
 func (c *ReplicaSetClient) CloneItem(orig interface{}) *ext_api.ReplicaSet {
@@ -280,10 +290,20 @@ func (c *ReplicaSetClient) CloneItem(orig interface{}) *ext_api.ReplicaSet {
 
 func (c *ReplicaSetClient) Update(target *ext_api.ReplicaSet) *ext_api.ReplicaSet {
 
-	accessor, err := meta.Accessor(target)
+	item, err := c.TryUpdate(target)
 	if err != nil {
 		panic(err)
 	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *ReplicaSetClient) TryUpdate(target *ext_api.ReplicaSet) (*ext_api.ReplicaSet, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
 	rName := accessor.GetName()
 	rcReq := c.rc.Put()
 	if true {
@@ -291,10 +311,25 @@ func (c *ReplicaSetClient) Update(target *ext_api.ReplicaSet) *ext_api.ReplicaSe
 	}
 	replacementTarget, err := rcReq.Resource("ReplicaSets").Name(rName).Body(target).Do().Get()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := replacementTarget.(*ext_api.ReplicaSet)
-	return item
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *ReplicaSetClient) FetchByName(name string) (*ext_api.ReplicaSet, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("ReplicaSets").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*ext_api.ReplicaSet)
+	return item, nil
 }
 
 func (c *ReplicaSetClient) UpdateOrAdd(target *ext_api.ReplicaSet) *ext_api.ReplicaSet {
@@ -0,0 +1,418 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang/glog"
+	k8s_api "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/meta"
+	ext_api "k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/client/cache"
+	"k8s.io/kubernetes/pkg/client/restclient"
+	"k8s.io/kubernetes/pkg/controller/framework"
+	"k8s.io/kubernetes/pkg/conversion"
+	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/runtime"
+	"reflect"
+	"sitepod.io/sitepod/pkg/api"
+	"sitepod.io/sitepod/pkg/api/v1"
+	"strings"
+	"time"
+)
+
+var (
+	resyncPeriodRestoreClient = 5 * time.Minute
+)
+
+func HackImportIgnoredRestoreClient(a k8s_api.Volume, b v1.Cluster, c1 ext_api.ThirdPartyResource) {
+}
+
+// template type ClientTmpl(ResourceType, ResourceListType, ResourceName, ResourcePluralName, Namespaced, DefaultGenName)
+
+type ResouceListTypeRestoreClient []int
+
+type RestoreClient struct {
+	rc            *restclient.RESTClient
+	rcConfig      *restclient.Config
+	ns            string
+	supportedType reflect.Type
+	informer      framework.SharedIndexInformer
+}
+
+func NewRestoreClient(rc *restclient.RESTClient, config *restclient.Config, ns string) *RestoreClient {
+	c := &RestoreClient{
+		rc:            rc,
+		rcConfig:      config,
+		supportedType: reflect.TypeOf(&v1.Restore{}),
+	}
+
+	if true {
+		c.ns = ns
+	}
+
+	pc := runtime.NewParameterCodec(k8s_api.Scheme)
+
+	indexers := make(cache.Indexers)
+	indexers["sitepod"] = func(obj interface{}) ([]string, error) {
+		accessor, _ := meta.Accessor(obj)
+		labels := accessor.GetLabels()
+		if _, ok := labels["sitepod"]; ok {
+			return []string{labels["sitepod"]}, nil
+		} else {
+			return []string{}, nil
+		}
+	}
+
+	indexers["uid"] = func(obj interface{}) ([]string, error) {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			panic(err)
+		}
+		return []string{string(accessor.GetUID())}, nil
+	}
+
+	c.informer = framework.NewSharedIndexInformer(
+		api.NewListWatchFromClient(c.rc, "Restores", c.ns, nil, pc),
+		&v1.Restore{},
+		resyncPeriodRestoreClient,
+		indexers,
+	)
+
+	return c
+}
+
+func (c *RestoreClient) StartInformer(stopCh <-chan struct{}) {
+	c.informer.Run(stopCh)
+}
+
+func (c *RestoreClient) AddInformerHandlers(reh framework.ResourceEventHandler) {
+	if c.informer == nil {
+		panic(fmt.Sprintf("%s informer not started", "Restore"))
+	}
+
+	c.informer.AddEventHandler(reh)
+}
+
+func (c *RestoreClient) HasSynced() bool {
+	if c.informer == nil {
+		return false
+	}
+	return c.informer.HasSynced()
+}
+
+type ItemDefaultableRestoreClient interface {
+	SetDefaults()
+}
+
+func (c *RestoreClient) NewEmpty() *v1.Restore {
+	item := &v1.Restore{}
+	item.GenerateName = "sitepod-restore-"
+	var aitem interface{}
+	aitem = item
+	if ditem, ok := aitem.(ItemDefaultableRestoreClient); ok {
+		ditem.SetDefaults()
+	}
+
+	return item
+}
+
+//TODO: wrong location? shared?
+func (c *RestoreClient) KeyOf(obj interface{}) string {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func (c *RestoreClient) UIDOf(obj interface{}) (string, bool) {
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", false
+	}
+	return string(accessor.GetUID()), true
+}
+
+//TODO: wrong location? shared?
+func (c *RestoreClient) DeepEqual(a interface{}, b interface{}) bool {
+	return k8s_api.Semantic.DeepEqual(a, b)
+}
+
+func (c *RestoreClient) MaybeGetByKey(key string) (*v1.Restore, bool) {
+
+	if !strings.Contains(key, "/") && true {
+		key = fmt.Sprintf("%s/%s", c.ns, key)
+	}
+
+	iObj, exists, err := c.informer.GetStore().GetByKey(key)
+
+	if err != nil {
+		panic(err)
+	}
+
+	if iObj == nil {
+		return nil, exists
+	} else {
+		item := c.CloneItem(iObj)
+		glog.Infof("Got %s from informer store with rv %s", "Restore", item.ResourceVersion)
+		return item, exists
+	}
+}
+
+func (c *RestoreClient) GetByKey(key string) *v1.Restore {
+	item, exists := c.MaybeGetByKey(key)
+
+	if !exists {
+		panic("Not found " + "Restore" + ": " + key)
+	}
+
+	return item
+}
+
+func (c *RestoreClient) ByIndexByKey(index string, key string) []*v1.Restore {
+
+	items, err := c.informer.GetIndexer().ByIndex(index, key)
+
+	if err != nil {
+		panic(err)
+	}
+
+	typedItems := []*v1.Restore{}
+	for _, item := range items {
+		typedItems = append(typedItems, c.CloneItem(item))
+	}
+	return typedItems
+}
+
+func (c *RestoreClient) BySitepodKey(sitepodKey string) []*v1.Restore {
+	return c.ByIndexByKey("sitepod", sitepodKey)
+}
+
+func (c *RestoreClient) BySitepodKeyFunc() func(string) []interface{} {
+	return func(sitepodKey string) []interface{} {
+		iArray := []interface{}{}
+		for _, r := range c.ByIndexByKey("sitepod", sitepodKey) {
+			iArray = append(iArray, r)
+		}
+		return iArray
+	}
+}
+
+func (c *RestoreClient) MaybeSingleByUID(uid string) (*v1.Restore, bool) {
+	items := c.ByIndexByKey("uid", uid)
+	if len(items) == 0 {
+		return nil, false
+	} else {
+		return items[0], true
+	}
+}
+
+func (c *RestoreClient) SingleBySitepodKey(sitepodKey string) *v1.Restore {
+
+	items := c.BySitepodKey(sitepodKey)
+
+	if len(items) == 0 {
+		panic(errors.New("None found"))
+	}
+
+	return items[0]
+
+}
+
+func (c *RestoreClient) MaybeSingleBySitepodKey(sitepodKey string) (*v1.Restore, bool) {
+
+	items := c.BySitepodKey(sitepodKey)
+
+	if len(items) == 0 {
+		return nil, false
+	} else {
+
+		if len(items) > 1 {
+			glog.Warningf("Unexpected number of %s for sitepod %s - %d items matched", "Restores", sitepodKey, len(items))
+		}
+
+		return items[0], true
+	}
+
+}
+
+type BeforeAdderRestoreClient interface {
+	BeforeAdd()
+}
+
+func (c *RestoreClient) Add(target *v1.Restore) *v1.Restore {
+
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *RestoreClient) TryAdd(target *v1.Restore) (*v1.Restore, error) {
+
+	var itarget interface{}
+	itarget = target
+	if subject, ok := itarget.(BeforeAdderRestoreClient); ok {
+		subject.BeforeAdd()
+	}
+
+	rcReq := c.rc.Post()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+
+	result := rcReq.Resource("Restores").Body(target).Do()
+
+	if err := result.Error(); err != nil {
+		return nil, err
+	}
+
+	r, err := result.Get()
+
+	if err != nil {
+		return nil, err
+	}
+	item := r.(*v1.Restore)
+	glog.Infof("Added %s - %s (rv: %s)", "Restore", item.Name, item.ResourceVersion)
+	return item, nil
+}
+
+func (c *RestoreClient) CloneItem(orig interface{}) *v1.Restore {
+	cloned, err := conversion.NewCloner().DeepCopy(orig)
+	if err != nil {
+		panic(err)
+	}
+	return cloned.(*v1.Restore)
+}
+
+func (c *RestoreClient) Update(target *v1.Restore) *v1.Restore {
+
+	item, err := c.TryUpdate(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *RestoreClient) TryUpdate(target *v1.Restore) (*v1.Restore, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
+	rName := accessor.GetName()
+	rcReq := c.rc.Put()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	replacementTarget, err := rcReq.Resource("Restores").Name(rName).Body(target).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := replacementTarget.(*v1.Restore)
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *RestoreClient) FetchByName(name string) (*v1.Restore, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("Restores").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*v1.Restore)
+	return item, nil
+}
+
+func (c *RestoreClient) UpdateOrAdd(target *v1.Restore) *v1.Restore {
+
+	if len(string(target.UID)) > 0 {
+		return c.Update(target)
+	} else {
+		return c.Add(target)
+	}
+}
+
+func (c *RestoreClient) FetchList(s labels.Selector) []*v1.Restore {
+
+	var prc *restclient.Request
+	if !true {
+		prc = c.rc.Get().Resource("Restores").LabelsSelectorParam(s)
+	} else {
+		prc = c.rc.Get().Resource("Restores").Namespace(c.ns).LabelsSelectorParam(s)
+	}
+
+	rObj, err := prc.Do().Get()
+
+	if err != nil {
+		panic(err)
+	}
+
+	target := []*v1.Restore{}
+	kList := rObj.(*v1.RestoreList)
+	for _, kItem := range kList.Items {
+		target = append(target, c.CloneItem(&kItem))
+	}
+
+	return target
+}
+
+func (c *RestoreClient) TryDelete(target *v1.Restore) error {
+
+	var prc *restclient.Request
+	if !true {
+		prc = c.rc.Delete().Resource("Restores").Name(target.Name)
+	} else {
+		prc = c.rc.Delete().Namespace(c.ns).Resource("Restores").Name(target.Name)
+	}
+
+	err := prc.Do().Error()
+	return err
+}
+
+func (c *RestoreClient) Delete(target *v1.Restore) {
+
+	err := c.TryDelete(target)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (c *RestoreClient) DeleteFunc() func(interface{}) {
+	return func(iTarget interface{}) {
+
+		target := iTarget.(*v1.Restore)
+
+		err := c.TryDelete(target)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func (c *RestoreClient) List() []*v1.Restore {
+	kItems := c.informer.GetStore().List()
+	target := []*v1.Restore{}
+	for _, kItem := range kItems {
+		target = append(target, kItem.(*v1.Restore))
+	}
+	return target
+}
+
+func (c *RestoreClient) RestClient() *restclient.RESTClient {
+	return c.rc
+}
+
+func (c *RestoreClient) RestClientConfig() *restclient.Config {
+	return c.rcConfig
+}
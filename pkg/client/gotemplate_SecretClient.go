@@ -0,0 +1,418 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang/glog"
+	k8s_api "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/meta"
+	ext_api "k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/client/cache"
+	"k8s.io/kubernetes/pkg/client/restclient"
+	"k8s.io/kubernetes/pkg/controller/framework"
+	"k8s.io/kubernetes/pkg/conversion"
+	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/runtime"
+	"reflect"
+	"sitepod.io/sitepod/pkg/api"
+	"sitepod.io/sitepod/pkg/api/v1"
+	"strings"
+	"time"
+)
+
+var (
+	resyncPeriodSecretClient = 5 * time.Minute
+)
+
+func HackImportIgnoredSecretClient(a k8s_api.Volume, b v1.Cluster, c1 ext_api.ThirdPartyResource) {
+}
+
+// template type ClientTmpl(ResourceType, ResourceListType, ResourceName, ResourcePluralName, Namespaced, DefaultGenName)
+
+type ResouceListTypeSecretClient []int
+
+type SecretClient struct {
+	rc            *restclient.RESTClient
+	rcConfig      *restclient.Config
+	ns            string
+	supportedType reflect.Type
+	informer      framework.SharedIndexInformer
+}
+
+func NewSecretClient(rc *restclient.RESTClient, config *restclient.Config, ns string) *SecretClient {
+	c := &SecretClient{
+		rc:            rc,
+		rcConfig:      config,
+		supportedType: reflect.TypeOf(&k8s_api.Secret{}),
+	}
+
+	if true {
+		c.ns = ns
+	}
+
+	pc := runtime.NewParameterCodec(k8s_api.Scheme)
+
+	indexers := make(cache.Indexers)
+	indexers["sitepod"] = func(obj interface{}) ([]string, error) {
+		accessor, _ := meta.Accessor(obj)
+		labels := accessor.GetLabels()
+		if _, ok := labels["sitepod"]; ok {
+			return []string{labels["sitepod"]}, nil
+		} else {
+			return []string{}, nil
+		}
+	}
+
+	indexers["uid"] = func(obj interface{}) ([]string, error) {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			panic(err)
+		}
+		return []string{string(accessor.GetUID())}, nil
+	}
+
+	c.informer = framework.NewSharedIndexInformer(
+		api.NewListWatchFromClient(c.rc, "Secrets", c.ns, nil, pc),
+		&k8s_api.Secret{},
+		resyncPeriodSecretClient,
+		indexers,
+	)
+
+	return c
+}
+
+func (c *SecretClient) StartInformer(stopCh <-chan struct{}) {
+	c.informer.Run(stopCh)
+}
+
+func (c *SecretClient) AddInformerHandlers(reh framework.ResourceEventHandler) {
+	if c.informer == nil {
+		panic(fmt.Sprintf("%s informer not started", "Secret"))
+	}
+
+	c.informer.AddEventHandler(reh)
+}
+
+func (c *SecretClient) HasSynced() bool {
+	if c.informer == nil {
+		return false
+	}
+	return c.informer.HasSynced()
+}
+
+type ItemDefaultableSecretClient interface {
+	SetDefaults()
+}
+
+func (c *SecretClient) NewEmpty() *k8s_api.Secret {
+	item := &k8s_api.Secret{}
+	item.GenerateName = "sitepod-secret-"
+	var aitem interface{}
+	aitem = item
+	if ditem, ok := aitem.(ItemDefaultableSecretClient); ok {
+		ditem.SetDefaults()
+	}
+
+	return item
+}
+
+//TODO: wrong location? shared?
+func (c *SecretClient) KeyOf(obj interface{}) string {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func (c *SecretClient) UIDOf(obj interface{}) (string, bool) {
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", false
+	}
+	return string(accessor.GetUID()), true
+}
+
+//TODO: wrong location? shared?
+func (c *SecretClient) DeepEqual(a interface{}, b interface{}) bool {
+	return k8s_api.Semantic.DeepEqual(a, b)
+}
+
+func (c *SecretClient) MaybeGetByKey(key string) (*k8s_api.Secret, bool) {
+
+	if !strings.Contains(key, "/") && true {
+		key = fmt.Sprintf("%s/%s", c.ns, key)
+	}
+
+	iObj, exists, err := c.informer.GetStore().GetByKey(key)
+
+	if err != nil {
+		panic(err)
+	}
+
+	if iObj == nil {
+		return nil, exists
+	} else {
+		item := c.CloneItem(iObj)
+		glog.Infof("Got %s from informer store with rv %s", "Secret", item.ResourceVersion)
+		return item, exists
+	}
+}
+
+func (c *SecretClient) GetByKey(key string) *k8s_api.Secret {
+	item, exists := c.MaybeGetByKey(key)
+
+	if !exists {
+		panic("Not found " + "Secret" + ": " + key)
+	}
+
+	return item
+}
+
+func (c *SecretClient) ByIndexByKey(index string, key string) []*k8s_api.Secret {
+
+	items, err := c.informer.GetIndexer().ByIndex(index, key)
+
+	if err != nil {
+		panic(err)
+	}
+
+	typedItems := []*k8s_api.Secret{}
+	for _, item := range items {
+		typedItems = append(typedItems, c.CloneItem(item))
+	}
+	return typedItems
+}
+
+func (c *SecretClient) BySitepodKey(sitepodKey string) []*k8s_api.Secret {
+	return c.ByIndexByKey("sitepod", sitepodKey)
+}
+
+func (c *SecretClient) BySitepodKeyFunc() func(string) []interface{} {
+	return func(sitepodKey string) []interface{} {
+		iArray := []interface{}{}
+		for _, r := range c.ByIndexByKey("sitepod", sitepodKey) {
+			iArray = append(iArray, r)
+		}
+		return iArray
+	}
+}
+
+func (c *SecretClient) MaybeSingleByUID(uid string) (*k8s_api.Secret, bool) {
+	items := c.ByIndexByKey("uid", uid)
+	if len(items) == 0 {
+		return nil, false
+	} else {
+		return items[0], true
+	}
+}
+
+func (c *SecretClient) SingleBySitepodKey(sitepodKey string) *k8s_api.Secret {
+
+	items := c.BySitepodKey(sitepodKey)
+
+	if len(items) == 0 {
+		panic(errors.New("None found"))
+	}
+
+	return items[0]
+
+}
+
+func (c *SecretClient) MaybeSingleBySitepodKey(sitepodKey string) (*k8s_api.Secret, bool) {
+
+	items := c.BySitepodKey(sitepodKey)
+
+	if len(items) == 0 {
+		return nil, false
+	} else {
+
+		if len(items) > 1 {
+			glog.Warningf("Unexpected number of %s for sitepod %s - %d items matched", "Secrets", sitepodKey, len(items))
+		}
+
+		return items[0], true
+	}
+
+}
+
+type BeforeAdderSecretClient interface {
+	BeforeAdd()
+}
+
+func (c *SecretClient) Add(target *k8s_api.Secret) *k8s_api.Secret {
+
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *SecretClient) TryAdd(target *k8s_api.Secret) (*k8s_api.Secret, error) {
+
+	var itarget interface{}
+	itarget = target
+	if subject, ok := itarget.(BeforeAdderSecretClient); ok {
+		subject.BeforeAdd()
+	}
+
+	rcReq := c.rc.Post()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+
+	result := rcReq.Resource("Secrets").Body(target).Do()
+
+	if err := result.Error(); err != nil {
+		return nil, err
+	}
+
+	r, err := result.Get()
+
+	if err != nil {
+		return nil, err
+	}
+	item := r.(*k8s_api.Secret)
+	glog.Infof("Added %s - %s (rv: %s)", "Secret", item.Name, item.ResourceVersion)
+	return item, nil
+}
+
+func (c *SecretClient) CloneItem(orig interface{}) *k8s_api.Secret {
+	cloned, err := conversion.NewCloner().DeepCopy(orig)
+	if err != nil {
+		panic(err)
+	}
+	return cloned.(*k8s_api.Secret)
+}
+
+func (c *SecretClient) Update(target *k8s_api.Secret) *k8s_api.Secret {
+
+	item, err := c.TryUpdate(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *SecretClient) TryUpdate(target *k8s_api.Secret) (*k8s_api.Secret, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
+	rName := accessor.GetName()
+	rcReq := c.rc.Put()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	replacementTarget, err := rcReq.Resource("Secrets").Name(rName).Body(target).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := replacementTarget.(*k8s_api.Secret)
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *SecretClient) FetchByName(name string) (*k8s_api.Secret, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("Secrets").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*k8s_api.Secret)
+	return item, nil
+}
+
+func (c *SecretClient) UpdateOrAdd(target *k8s_api.Secret) *k8s_api.Secret {
+
+	if len(string(target.UID)) > 0 {
+		return c.Update(target)
+	} else {
+		return c.Add(target)
+	}
+}
+
+func (c *SecretClient) FetchList(s labels.Selector) []*k8s_api.Secret {
+
+	var prc *restclient.Request
+	if !true {
+		prc = c.rc.Get().Resource("Secrets").LabelsSelectorParam(s)
+	} else {
+		prc = c.rc.Get().Resource("Secrets").Namespace(c.ns).LabelsSelectorParam(s)
+	}
+
+	rObj, err := prc.Do().Get()
+
+	if err != nil {
+		panic(err)
+	}
+
+	target := []*k8s_api.Secret{}
+	kList := rObj.(*k8s_api.SecretList)
+	for _, kItem := range kList.Items {
+		target = append(target, c.CloneItem(&kItem))
+	}
+
+	return target
+}
+
+func (c *SecretClient) TryDelete(target *k8s_api.Secret) error {
+
+	var prc *restclient.Request
+	if !true {
+		prc = c.rc.Delete().Resource("Secrets").Name(target.Name)
+	} else {
+		prc = c.rc.Delete().Namespace(c.ns).Resource("Secrets").Name(target.Name)
+	}
+
+	err := prc.Do().Error()
+	return err
+}
+
+func (c *SecretClient) Delete(target *k8s_api.Secret) {
+
+	err := c.TryDelete(target)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (c *SecretClient) DeleteFunc() func(interface{}) {
+	return func(iTarget interface{}) {
+
+		target := iTarget.(*k8s_api.Secret)
+
+		err := c.TryDelete(target)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func (c *SecretClient) List() []*k8s_api.Secret {
+	kItems := c.informer.GetStore().List()
+	target := []*k8s_api.Secret{}
+	for _, kItem := range kItems {
+		target = append(target, kItem.(*k8s_api.Secret))
+	}
+	return target
+}
+
+func (c *SecretClient) RestClient() *restclient.RESTClient {
+	return c.rc
+}
+
+func (c *SecretClient) RestClientConfig() *restclient.Config {
+	return c.rcConfig
+}
@@ -243,6 +243,16 @@ type BeforeAdderServiceClient interface {
 
 func (c *ServiceClient) Add(target *k8s_api.Service) *k8s_api.Service {
 
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *ServiceClient) TryAdd(target *k8s_api.Service) (*k8s_api.Service, error) {
+
 	var itarget interface{}
 	itarget = target
 	if subject, ok := itarget.(BeforeAdderServiceClient); ok {
@@ -257,17 +267,17 @@ func (c *ServiceClient) Add(target *k8s_api.Service) *k8s_api.Service {
 	result := rcReq.Resource("Services").Body(target).Do()
 
 	if err := result.Error(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	r, err := result.Get()
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := r.(*k8s_api.Service)
 	glog.Infof("Added %s - %s (rv: %s)", "Service", item.Name, item.ResourceVersion)
-	return item
+	return item, nil
 }
 
 func (c *ServiceClient) CloneItem(orig interface{}) *k8s_api.Service {
@@ -280,10 +290,20 @@ func (c *ServiceClient) CloneItem(orig interface{}) *k8s_api.Service {
 
 func (c *ServiceClient) Update(target *k8s_api.Service) *k8s_api.Service {
 
-	accessor, err := meta.Accessor(target)
+	item, err := c.TryUpdate(target)
 	if err != nil {
 		panic(err)
 	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *ServiceClient) TryUpdate(target *k8s_api.Service) (*k8s_api.Service, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
 	rName := accessor.GetName()
 	rcReq := c.rc.Put()
 	if true {
@@ -291,10 +311,25 @@ func (c *ServiceClient) Update(target *k8s_api.Service) *k8s_api.Service {
 	}
 	replacementTarget, err := rcReq.Resource("Services").Name(rName).Body(target).Do().Get()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := replacementTarget.(*k8s_api.Service)
-	return item
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *ServiceClient) FetchByName(name string) (*k8s_api.Service, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("Services").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*k8s_api.Service)
+	return item, nil
 }
 
 func (c *ServiceClient) UpdateOrAdd(target *k8s_api.Service) *k8s_api.Service {
@@ -243,6 +243,16 @@ type BeforeAdderSitepodClient interface {
 
 func (c *SitepodClient) Add(target *v1.Sitepod) *v1.Sitepod {
 
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *SitepodClient) TryAdd(target *v1.Sitepod) (*v1.Sitepod, error) {
+
 	var itarget interface{}
 	itarget = target
 	if subject, ok := itarget.(BeforeAdderSitepodClient); ok {
@@ -257,17 +267,17 @@ func (c *SitepodClient) Add(target *v1.Sitepod) *v1.Sitepod {
 	result := rcReq.Resource("Sitepods").Body(target).Do()
 
 	if err := result.Error(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	r, err := result.Get()
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := r.(*v1.Sitepod)
 	glog.Infof("Added %s - %s (rv: %s)", "Sitepod", item.Name, item.ResourceVersion)
-	return item
+	return item, nil
 }
 
 func (c *SitepodClient) CloneItem(orig interface{}) *v1.Sitepod {
@@ -280,10 +290,20 @@ func (c *SitepodClient) CloneItem(orig interface{}) *v1.Sitepod {
 
 func (c *SitepodClient) Update(target *v1.Sitepod) *v1.Sitepod {
 
-	accessor, err := meta.Accessor(target)
+	item, err := c.TryUpdate(target)
 	if err != nil {
 		panic(err)
 	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *SitepodClient) TryUpdate(target *v1.Sitepod) (*v1.Sitepod, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
 	rName := accessor.GetName()
 	rcReq := c.rc.Put()
 	if true {
@@ -291,10 +311,25 @@ func (c *SitepodClient) Update(target *v1.Sitepod) *v1.Sitepod {
 	}
 	replacementTarget, err := rcReq.Resource("Sitepods").Name(rName).Body(target).Do().Get()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := replacementTarget.(*v1.Sitepod)
-	return item
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *SitepodClient) FetchByName(name string) (*v1.Sitepod, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("Sitepods").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*v1.Sitepod)
+	return item, nil
 }
 
 func (c *SitepodClient) UpdateOrAdd(target *v1.Sitepod) *v1.Sitepod {
@@ -0,0 +1,418 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang/glog"
+	k8s_api "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/meta"
+	ext_api "k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/client/cache"
+	"k8s.io/kubernetes/pkg/client/restclient"
+	"k8s.io/kubernetes/pkg/controller/framework"
+	"k8s.io/kubernetes/pkg/conversion"
+	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/runtime"
+	"reflect"
+	"sitepod.io/sitepod/pkg/api"
+	"sitepod.io/sitepod/pkg/api/v1"
+	"strings"
+	"time"
+)
+
+var (
+	resyncPeriodSitepodMigrationClient = 5 * time.Minute
+)
+
+func HackImportIgnoredSitepodMigrationClient(a k8s_api.Volume, b v1.Cluster, c1 ext_api.ThirdPartyResource) {
+}
+
+// template type ClientTmpl(ResourceType, ResourceListType, ResourceName, ResourcePluralName, Namespaced, DefaultGenName)
+
+type ResouceListTypeSitepodMigrationClient []int
+
+type SitepodMigrationClient struct {
+	rc            *restclient.RESTClient
+	rcConfig      *restclient.Config
+	ns            string
+	supportedType reflect.Type
+	informer      framework.SharedIndexInformer
+}
+
+func NewSitepodMigrationClient(rc *restclient.RESTClient, config *restclient.Config, ns string) *SitepodMigrationClient {
+	c := &SitepodMigrationClient{
+		rc:            rc,
+		rcConfig:      config,
+		supportedType: reflect.TypeOf(&v1.SitepodMigration{}),
+	}
+
+	if true {
+		c.ns = ns
+	}
+
+	pc := runtime.NewParameterCodec(k8s_api.Scheme)
+
+	indexers := make(cache.Indexers)
+	indexers["sitepod"] = func(obj interface{}) ([]string, error) {
+		accessor, _ := meta.Accessor(obj)
+		labels := accessor.GetLabels()
+		if _, ok := labels["sitepod"]; ok {
+			return []string{labels["sitepod"]}, nil
+		} else {
+			return []string{}, nil
+		}
+	}
+
+	indexers["uid"] = func(obj interface{}) ([]string, error) {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			panic(err)
+		}
+		return []string{string(accessor.GetUID())}, nil
+	}
+
+	c.informer = framework.NewSharedIndexInformer(
+		api.NewListWatchFromClient(c.rc, "SitepodMigrations", c.ns, nil, pc),
+		&v1.SitepodMigration{},
+		resyncPeriodSitepodMigrationClient,
+		indexers,
+	)
+
+	return c
+}
+
+func (c *SitepodMigrationClient) StartInformer(stopCh <-chan struct{}) {
+	c.informer.Run(stopCh)
+}
+
+func (c *SitepodMigrationClient) AddInformerHandlers(reh framework.ResourceEventHandler) {
+	if c.informer == nil {
+		panic(fmt.Sprintf("%s informer not started", "SitepodMigration"))
+	}
+
+	c.informer.AddEventHandler(reh)
+}
+
+func (c *SitepodMigrationClient) HasSynced() bool {
+	if c.informer == nil {
+		return false
+	}
+	return c.informer.HasSynced()
+}
+
+type ItemDefaultableSitepodMigrationClient interface {
+	SetDefaults()
+}
+
+func (c *SitepodMigrationClient) NewEmpty() *v1.SitepodMigration {
+	item := &v1.SitepodMigration{}
+	item.GenerateName = "sitepod-migration-"
+	var aitem interface{}
+	aitem = item
+	if ditem, ok := aitem.(ItemDefaultableSitepodMigrationClient); ok {
+		ditem.SetDefaults()
+	}
+
+	return item
+}
+
+//TODO: wrong location? shared?
+func (c *SitepodMigrationClient) KeyOf(obj interface{}) string {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func (c *SitepodMigrationClient) UIDOf(obj interface{}) (string, bool) {
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", false
+	}
+	return string(accessor.GetUID()), true
+}
+
+//TODO: wrong location? shared?
+func (c *SitepodMigrationClient) DeepEqual(a interface{}, b interface{}) bool {
+	return k8s_api.Semantic.DeepEqual(a, b)
+}
+
+func (c *SitepodMigrationClient) MaybeGetByKey(key string) (*v1.SitepodMigration, bool) {
+
+	if !strings.Contains(key, "/") && true {
+		key = fmt.Sprintf("%s/%s", c.ns, key)
+	}
+
+	iObj, exists, err := c.informer.GetStore().GetByKey(key)
+
+	if err != nil {
+		panic(err)
+	}
+
+	if iObj == nil {
+		return nil, exists
+	} else {
+		item := c.CloneItem(iObj)
+		glog.Infof("Got %s from informer store with rv %s", "SitepodMigration", item.ResourceVersion)
+		return item, exists
+	}
+}
+
+func (c *SitepodMigrationClient) GetByKey(key string) *v1.SitepodMigration {
+	item, exists := c.MaybeGetByKey(key)
+
+	if !exists {
+		panic("Not found " + "SitepodMigration" + ": " + key)
+	}
+
+	return item
+}
+
+func (c *SitepodMigrationClient) ByIndexByKey(index string, key string) []*v1.SitepodMigration {
+
+	items, err := c.informer.GetIndexer().ByIndex(index, key)
+
+	if err != nil {
+		panic(err)
+	}
+
+	typedItems := []*v1.SitepodMigration{}
+	for _, item := range items {
+		typedItems = append(typedItems, c.CloneItem(item))
+	}
+	return typedItems
+}
+
+func (c *SitepodMigrationClient) BySitepodKey(sitepodKey string) []*v1.SitepodMigration {
+	return c.ByIndexByKey("sitepod", sitepodKey)
+}
+
+func (c *SitepodMigrationClient) BySitepodKeyFunc() func(string) []interface{} {
+	return func(sitepodKey string) []interface{} {
+		iArray := []interface{}{}
+		for _, r := range c.ByIndexByKey("sitepod", sitepodKey) {
+			iArray = append(iArray, r)
+		}
+		return iArray
+	}
+}
+
+func (c *SitepodMigrationClient) MaybeSingleByUID(uid string) (*v1.SitepodMigration, bool) {
+	items := c.ByIndexByKey("uid", uid)
+	if len(items) == 0 {
+		return nil, false
+	} else {
+		return items[0], true
+	}
+}
+
+func (c *SitepodMigrationClient) SingleBySitepodKey(sitepodKey string) *v1.SitepodMigration {
+
+	items := c.BySitepodKey(sitepodKey)
+
+	if len(items) == 0 {
+		panic(errors.New("None found"))
+	}
+
+	return items[0]
+
+}
+
+func (c *SitepodMigrationClient) MaybeSingleBySitepodKey(sitepodKey string) (*v1.SitepodMigration, bool) {
+
+	items := c.BySitepodKey(sitepodKey)
+
+	if len(items) == 0 {
+		return nil, false
+	} else {
+
+		if len(items) > 1 {
+			glog.Warningf("Unexpected number of %s for sitepod %s - %d items matched", "SitepodMigrations", sitepodKey, len(items))
+		}
+
+		return items[0], true
+	}
+
+}
+
+type BeforeAdderSitepodMigrationClient interface {
+	BeforeAdd()
+}
+
+func (c *SitepodMigrationClient) Add(target *v1.SitepodMigration) *v1.SitepodMigration {
+
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *SitepodMigrationClient) TryAdd(target *v1.SitepodMigration) (*v1.SitepodMigration, error) {
+
+	var itarget interface{}
+	itarget = target
+	if subject, ok := itarget.(BeforeAdderSitepodMigrationClient); ok {
+		subject.BeforeAdd()
+	}
+
+	rcReq := c.rc.Post()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+
+	result := rcReq.Resource("SitepodMigrations").Body(target).Do()
+
+	if err := result.Error(); err != nil {
+		return nil, err
+	}
+
+	r, err := result.Get()
+
+	if err != nil {
+		return nil, err
+	}
+	item := r.(*v1.SitepodMigration)
+	glog.Infof("Added %s - %s (rv: %s)", "SitepodMigration", item.Name, item.ResourceVersion)
+	return item, nil
+}
+
+func (c *SitepodMigrationClient) CloneItem(orig interface{}) *v1.SitepodMigration {
+	cloned, err := conversion.NewCloner().DeepCopy(orig)
+	if err != nil {
+		panic(err)
+	}
+	return cloned.(*v1.SitepodMigration)
+}
+
+func (c *SitepodMigrationClient) Update(target *v1.SitepodMigration) *v1.SitepodMigration {
+
+	item, err := c.TryUpdate(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *SitepodMigrationClient) TryUpdate(target *v1.SitepodMigration) (*v1.SitepodMigration, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
+	rName := accessor.GetName()
+	rcReq := c.rc.Put()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	replacementTarget, err := rcReq.Resource("SitepodMigrations").Name(rName).Body(target).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := replacementTarget.(*v1.SitepodMigration)
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *SitepodMigrationClient) FetchByName(name string) (*v1.SitepodMigration, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("SitepodMigrations").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*v1.SitepodMigration)
+	return item, nil
+}
+
+func (c *SitepodMigrationClient) UpdateOrAdd(target *v1.SitepodMigration) *v1.SitepodMigration {
+
+	if len(string(target.UID)) > 0 {
+		return c.Update(target)
+	} else {
+		return c.Add(target)
+	}
+}
+
+func (c *SitepodMigrationClient) FetchList(s labels.Selector) []*v1.SitepodMigration {
+
+	var prc *restclient.Request
+	if !true {
+		prc = c.rc.Get().Resource("SitepodMigrations").LabelsSelectorParam(s)
+	} else {
+		prc = c.rc.Get().Resource("SitepodMigrations").Namespace(c.ns).LabelsSelectorParam(s)
+	}
+
+	rObj, err := prc.Do().Get()
+
+	if err != nil {
+		panic(err)
+	}
+
+	target := []*v1.SitepodMigration{}
+	kList := rObj.(*v1.SitepodMigrationList)
+	for _, kItem := range kList.Items {
+		target = append(target, c.CloneItem(&kItem))
+	}
+
+	return target
+}
+
+func (c *SitepodMigrationClient) TryDelete(target *v1.SitepodMigration) error {
+
+	var prc *restclient.Request
+	if !true {
+		prc = c.rc.Delete().Resource("SitepodMigrations").Name(target.Name)
+	} else {
+		prc = c.rc.Delete().Namespace(c.ns).Resource("SitepodMigrations").Name(target.Name)
+	}
+
+	err := prc.Do().Error()
+	return err
+}
+
+func (c *SitepodMigrationClient) Delete(target *v1.SitepodMigration) {
+
+	err := c.TryDelete(target)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (c *SitepodMigrationClient) DeleteFunc() func(interface{}) {
+	return func(iTarget interface{}) {
+
+		target := iTarget.(*v1.SitepodMigration)
+
+		err := c.TryDelete(target)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func (c *SitepodMigrationClient) List() []*v1.SitepodMigration {
+	kItems := c.informer.GetStore().List()
+	target := []*v1.SitepodMigration{}
+	for _, kItem := range kItems {
+		target = append(target, kItem.(*v1.SitepodMigration))
+	}
+	return target
+}
+
+func (c *SitepodMigrationClient) RestClient() *restclient.RESTClient {
+	return c.rc
+}
+
+func (c *SitepodMigrationClient) RestClientConfig() *restclient.Config {
+	return c.rcConfig
+}
@@ -243,6 +243,16 @@ type BeforeAdderSitepodUserClient interface {
 
 func (c *SitepodUserClient) Add(target *v1.SitepodUser) *v1.SitepodUser {
 
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *SitepodUserClient) TryAdd(target *v1.SitepodUser) (*v1.SitepodUser, error) {
+
 	var itarget interface{}
 	itarget = target
 	if subject, ok := itarget.(BeforeAdderSitepodUserClient); ok {
@@ -257,17 +267,17 @@ func (c *SitepodUserClient) Add(target *v1.SitepodUser) *v1.SitepodUser {
 	result := rcReq.Resource("SitepodUsers").Body(target).Do()
 
 	if err := result.Error(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	r, err := result.Get()
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := r.(*v1.SitepodUser)
 	glog.Infof("Added %s - %s (rv: %s)", "SitepodUser", item.Name, item.ResourceVersion)
-	return item
+	return item, nil
 }
 
 func (c *SitepodUserClient) CloneItem(orig interface{}) *v1.SitepodUser {
@@ -280,10 +290,20 @@ func (c *SitepodUserClient) CloneItem(orig interface{}) *v1.SitepodUser {
 
 func (c *SitepodUserClient) Update(target *v1.SitepodUser) *v1.SitepodUser {
 
-	accessor, err := meta.Accessor(target)
+	item, err := c.TryUpdate(target)
 	if err != nil {
 		panic(err)
 	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *SitepodUserClient) TryUpdate(target *v1.SitepodUser) (*v1.SitepodUser, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
 	rName := accessor.GetName()
 	rcReq := c.rc.Put()
 	if true {
@@ -291,10 +311,25 @@ func (c *SitepodUserClient) Update(target *v1.SitepodUser) *v1.SitepodUser {
 	}
 	replacementTarget, err := rcReq.Resource("SitepodUsers").Name(rName).Body(target).Do().Get()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := replacementTarget.(*v1.SitepodUser)
-	return item
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *SitepodUserClient) FetchByName(name string) (*v1.SitepodUser, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("SitepodUsers").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*v1.SitepodUser)
+	return item, nil
 }
 
 func (c *SitepodUserClient) UpdateOrAdd(target *v1.SitepodUser) *v1.SitepodUser {
@@ -0,0 +1,418 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang/glog"
+	k8s_api "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/meta"
+	ext_api "k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/client/cache"
+	"k8s.io/kubernetes/pkg/client/restclient"
+	"k8s.io/kubernetes/pkg/controller/framework"
+	"k8s.io/kubernetes/pkg/conversion"
+	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/runtime"
+	"reflect"
+	"sitepod.io/sitepod/pkg/api"
+	"sitepod.io/sitepod/pkg/api/v1"
+	"strings"
+	"time"
+)
+
+var (
+	resyncPeriodSystemGroupClient = 5 * time.Minute
+)
+
+func HackImportIgnoredSystemGroupClient(a k8s_api.Volume, b v1.Cluster, c1 ext_api.ThirdPartyResource) {
+}
+
+// template type ClientTmpl(ResourceType, ResourceListType, ResourceName, ResourcePluralName, Namespaced, DefaultGenName)
+
+type ResouceListTypeSystemGroupClient []int
+
+type SystemGroupClient struct {
+	rc            *restclient.RESTClient
+	rcConfig      *restclient.Config
+	ns            string
+	supportedType reflect.Type
+	informer      framework.SharedIndexInformer
+}
+
+func NewSystemGroupClient(rc *restclient.RESTClient, config *restclient.Config, ns string) *SystemGroupClient {
+	c := &SystemGroupClient{
+		rc:            rc,
+		rcConfig:      config,
+		supportedType: reflect.TypeOf(&v1.SystemGroup{}),
+	}
+
+	if true {
+		c.ns = ns
+	}
+
+	pc := runtime.NewParameterCodec(k8s_api.Scheme)
+
+	indexers := make(cache.Indexers)
+	indexers["sitepod"] = func(obj interface{}) ([]string, error) {
+		accessor, _ := meta.Accessor(obj)
+		labels := accessor.GetLabels()
+		if _, ok := labels["sitepod"]; ok {
+			return []string{labels["sitepod"]}, nil
+		} else {
+			return []string{}, nil
+		}
+	}
+
+	indexers["uid"] = func(obj interface{}) ([]string, error) {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			panic(err)
+		}
+		return []string{string(accessor.GetUID())}, nil
+	}
+
+	c.informer = framework.NewSharedIndexInformer(
+		api.NewListWatchFromClient(c.rc, "SystemGroups", c.ns, nil, pc),
+		&v1.SystemGroup{},
+		resyncPeriodSystemGroupClient,
+		indexers,
+	)
+
+	return c
+}
+
+func (c *SystemGroupClient) StartInformer(stopCh <-chan struct{}) {
+	c.informer.Run(stopCh)
+}
+
+func (c *SystemGroupClient) AddInformerHandlers(reh framework.ResourceEventHandler) {
+	if c.informer == nil {
+		panic(fmt.Sprintf("%s informer not started", "SystemGroup"))
+	}
+
+	c.informer.AddEventHandler(reh)
+}
+
+func (c *SystemGroupClient) HasSynced() bool {
+	if c.informer == nil {
+		return false
+	}
+	return c.informer.HasSynced()
+}
+
+type ItemDefaultableSystemGroupClient interface {
+	SetDefaults()
+}
+
+func (c *SystemGroupClient) NewEmpty() *v1.SystemGroup {
+	item := &v1.SystemGroup{}
+	item.GenerateName = "systemgroup-"
+	var aitem interface{}
+	aitem = item
+	if ditem, ok := aitem.(ItemDefaultableSystemGroupClient); ok {
+		ditem.SetDefaults()
+	}
+
+	return item
+}
+
+//TODO: wrong location? shared?
+func (c *SystemGroupClient) KeyOf(obj interface{}) string {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func (c *SystemGroupClient) UIDOf(obj interface{}) (string, bool) {
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", false
+	}
+	return string(accessor.GetUID()), true
+}
+
+//TODO: wrong location? shared?
+func (c *SystemGroupClient) DeepEqual(a interface{}, b interface{}) bool {
+	return k8s_api.Semantic.DeepEqual(a, b)
+}
+
+func (c *SystemGroupClient) MaybeGetByKey(key string) (*v1.SystemGroup, bool) {
+
+	if !strings.Contains(key, "/") && true {
+		key = fmt.Sprintf("%s/%s", c.ns, key)
+	}
+
+	iObj, exists, err := c.informer.GetStore().GetByKey(key)
+
+	if err != nil {
+		panic(err)
+	}
+
+	if iObj == nil {
+		return nil, exists
+	} else {
+		item := c.CloneItem(iObj)
+		glog.Infof("Got %s from informer store with rv %s", "SystemGroup", item.ResourceVersion)
+		return item, exists
+	}
+}
+
+func (c *SystemGroupClient) GetByKey(key string) *v1.SystemGroup {
+	item, exists := c.MaybeGetByKey(key)
+
+	if !exists {
+		panic("Not found " + "SystemGroup" + ": " + key)
+	}
+
+	return item
+}
+
+func (c *SystemGroupClient) ByIndexByKey(index string, key string) []*v1.SystemGroup {
+
+	items, err := c.informer.GetIndexer().ByIndex(index, key)
+
+	if err != nil {
+		panic(err)
+	}
+
+	typedItems := []*v1.SystemGroup{}
+	for _, item := range items {
+		typedItems = append(typedItems, c.CloneItem(item))
+	}
+	return typedItems
+}
+
+func (c *SystemGroupClient) BySitepodKey(sitepodKey string) []*v1.SystemGroup {
+	return c.ByIndexByKey("sitepod", sitepodKey)
+}
+
+func (c *SystemGroupClient) BySitepodKeyFunc() func(string) []interface{} {
+	return func(sitepodKey string) []interface{} {
+		iArray := []interface{}{}
+		for _, r := range c.ByIndexByKey("sitepod", sitepodKey) {
+			iArray = append(iArray, r)
+		}
+		return iArray
+	}
+}
+
+func (c *SystemGroupClient) MaybeSingleByUID(uid string) (*v1.SystemGroup, bool) {
+	items := c.ByIndexByKey("uid", uid)
+	if len(items) == 0 {
+		return nil, false
+	} else {
+		return items[0], true
+	}
+}
+
+func (c *SystemGroupClient) SingleBySitepodKey(sitepodKey string) *v1.SystemGroup {
+
+	items := c.BySitepodKey(sitepodKey)
+
+	if len(items) == 0 {
+		panic(errors.New("None found"))
+	}
+
+	return items[0]
+
+}
+
+func (c *SystemGroupClient) MaybeSingleBySitepodKey(sitepodKey string) (*v1.SystemGroup, bool) {
+
+	items := c.BySitepodKey(sitepodKey)
+
+	if len(items) == 0 {
+		return nil, false
+	} else {
+
+		if len(items) > 1 {
+			glog.Warningf("Unexpected number of %s for sitepod %s - %d items matched", "SystemGroups", sitepodKey, len(items))
+		}
+
+		return items[0], true
+	}
+
+}
+
+type BeforeAdderSystemGroupClient interface {
+	BeforeAdd()
+}
+
+func (c *SystemGroupClient) Add(target *v1.SystemGroup) *v1.SystemGroup {
+
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *SystemGroupClient) TryAdd(target *v1.SystemGroup) (*v1.SystemGroup, error) {
+
+	var itarget interface{}
+	itarget = target
+	if subject, ok := itarget.(BeforeAdderSystemGroupClient); ok {
+		subject.BeforeAdd()
+	}
+
+	rcReq := c.rc.Post()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+
+	result := rcReq.Resource("SystemGroups").Body(target).Do()
+
+	if err := result.Error(); err != nil {
+		return nil, err
+	}
+
+	r, err := result.Get()
+
+	if err != nil {
+		return nil, err
+	}
+	item := r.(*v1.SystemGroup)
+	glog.Infof("Added %s - %s (rv: %s)", "SystemGroup", item.Name, item.ResourceVersion)
+	return item, nil
+}
+
+func (c *SystemGroupClient) CloneItem(orig interface{}) *v1.SystemGroup {
+	cloned, err := conversion.NewCloner().DeepCopy(orig)
+	if err != nil {
+		panic(err)
+	}
+	return cloned.(*v1.SystemGroup)
+}
+
+func (c *SystemGroupClient) Update(target *v1.SystemGroup) *v1.SystemGroup {
+
+	item, err := c.TryUpdate(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *SystemGroupClient) TryUpdate(target *v1.SystemGroup) (*v1.SystemGroup, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
+	rName := accessor.GetName()
+	rcReq := c.rc.Put()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	replacementTarget, err := rcReq.Resource("SystemGroups").Name(rName).Body(target).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := replacementTarget.(*v1.SystemGroup)
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *SystemGroupClient) FetchByName(name string) (*v1.SystemGroup, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("SystemGroups").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*v1.SystemGroup)
+	return item, nil
+}
+
+func (c *SystemGroupClient) UpdateOrAdd(target *v1.SystemGroup) *v1.SystemGroup {
+
+	if len(string(target.UID)) > 0 {
+		return c.Update(target)
+	} else {
+		return c.Add(target)
+	}
+}
+
+func (c *SystemGroupClient) FetchList(s labels.Selector) []*v1.SystemGroup {
+
+	var prc *restclient.Request
+	if !true {
+		prc = c.rc.Get().Resource("SystemGroups").LabelsSelectorParam(s)
+	} else {
+		prc = c.rc.Get().Resource("SystemGroups").Namespace(c.ns).LabelsSelectorParam(s)
+	}
+
+	rObj, err := prc.Do().Get()
+
+	if err != nil {
+		panic(err)
+	}
+
+	target := []*v1.SystemGroup{}
+	kList := rObj.(*v1.SystemGroupList)
+	for _, kItem := range kList.Items {
+		target = append(target, c.CloneItem(&kItem))
+	}
+
+	return target
+}
+
+func (c *SystemGroupClient) TryDelete(target *v1.SystemGroup) error {
+
+	var prc *restclient.Request
+	if !true {
+		prc = c.rc.Delete().Resource("SystemGroups").Name(target.Name)
+	} else {
+		prc = c.rc.Delete().Namespace(c.ns).Resource("SystemGroups").Name(target.Name)
+	}
+
+	err := prc.Do().Error()
+	return err
+}
+
+func (c *SystemGroupClient) Delete(target *v1.SystemGroup) {
+
+	err := c.TryDelete(target)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (c *SystemGroupClient) DeleteFunc() func(interface{}) {
+	return func(iTarget interface{}) {
+
+		target := iTarget.(*v1.SystemGroup)
+
+		err := c.TryDelete(target)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func (c *SystemGroupClient) List() []*v1.SystemGroup {
+	kItems := c.informer.GetStore().List()
+	target := []*v1.SystemGroup{}
+	for _, kItem := range kItems {
+		target = append(target, kItem.(*v1.SystemGroup))
+	}
+	return target
+}
+
+func (c *SystemGroupClient) RestClient() *restclient.RESTClient {
+	return c.rc
+}
+
+func (c *SystemGroupClient) RestClientConfig() *restclient.Config {
+	return c.rcConfig
+}
@@ -243,6 +243,16 @@ type BeforeAdderSystemUserClient interface {
 
 func (c *SystemUserClient) Add(target *v1.SystemUser) *v1.SystemUser {
 
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *SystemUserClient) TryAdd(target *v1.SystemUser) (*v1.SystemUser, error) {
+
 	var itarget interface{}
 	itarget = target
 	if subject, ok := itarget.(BeforeAdderSystemUserClient); ok {
@@ -257,17 +267,17 @@ func (c *SystemUserClient) Add(target *v1.SystemUser) *v1.SystemUser {
 	result := rcReq.Resource("SystemUsers").Body(target).Do()
 
 	if err := result.Error(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	r, err := result.Get()
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := r.(*v1.SystemUser)
 	glog.Infof("Added %s - %s (rv: %s)", "SystemUser", item.Name, item.ResourceVersion)
-	return item
+	return item, nil
 }
 
 func (c *SystemUserClient) CloneItem(orig interface{}) *v1.SystemUser {
@@ -280,10 +290,20 @@ func (c *SystemUserClient) CloneItem(orig interface{}) *v1.SystemUser {
 
 func (c *SystemUserClient) Update(target *v1.SystemUser) *v1.SystemUser {
 
-	accessor, err := meta.Accessor(target)
+	item, err := c.TryUpdate(target)
 	if err != nil {
 		panic(err)
 	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *SystemUserClient) TryUpdate(target *v1.SystemUser) (*v1.SystemUser, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
 	rName := accessor.GetName()
 	rcReq := c.rc.Put()
 	if true {
@@ -291,10 +311,25 @@ func (c *SystemUserClient) Update(target *v1.SystemUser) *v1.SystemUser {
 	}
 	replacementTarget, err := rcReq.Resource("SystemUsers").Name(rName).Body(target).Do().Get()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := replacementTarget.(*v1.SystemUser)
-	return item
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *SystemUserClient) FetchByName(name string) (*v1.SystemUser, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("SystemUsers").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*v1.SystemUser)
+	return item, nil
 }
 
 func (c *SystemUserClient) UpdateOrAdd(target *v1.SystemUser) *v1.SystemUser {
@@ -0,0 +1,418 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang/glog"
+	k8s_api "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/meta"
+	ext_api "k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/client/cache"
+	"k8s.io/kubernetes/pkg/client/restclient"
+	"k8s.io/kubernetes/pkg/controller/framework"
+	"k8s.io/kubernetes/pkg/conversion"
+	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/runtime"
+	"reflect"
+	"sitepod.io/sitepod/pkg/api"
+	"sitepod.io/sitepod/pkg/api/v1"
+	"strings"
+	"time"
+)
+
+var (
+	resyncPeriodWebSessionClient = 5 * time.Minute
+)
+
+func HackImportIgnoredWebSessionClient(a k8s_api.Volume, b v1.Cluster, c1 ext_api.ThirdPartyResource) {
+}
+
+// template type ClientTmpl(ResourceType, ResourceListType, ResourceName, ResourcePluralName, Namespaced, DefaultGenName)
+
+type ResouceListTypeWebSessionClient []int
+
+type WebSessionClient struct {
+	rc            *restclient.RESTClient
+	rcConfig      *restclient.Config
+	ns            string
+	supportedType reflect.Type
+	informer      framework.SharedIndexInformer
+}
+
+func NewWebSessionClient(rc *restclient.RESTClient, config *restclient.Config, ns string) *WebSessionClient {
+	c := &WebSessionClient{
+		rc:            rc,
+		rcConfig:      config,
+		supportedType: reflect.TypeOf(&v1.WebSession{}),
+	}
+
+	if true {
+		c.ns = ns
+	}
+
+	pc := runtime.NewParameterCodec(k8s_api.Scheme)
+
+	indexers := make(cache.Indexers)
+	indexers["sitepod"] = func(obj interface{}) ([]string, error) {
+		accessor, _ := meta.Accessor(obj)
+		labels := accessor.GetLabels()
+		if _, ok := labels["sitepod"]; ok {
+			return []string{labels["sitepod"]}, nil
+		} else {
+			return []string{}, nil
+		}
+	}
+
+	indexers["uid"] = func(obj interface{}) ([]string, error) {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			panic(err)
+		}
+		return []string{string(accessor.GetUID())}, nil
+	}
+
+	c.informer = framework.NewSharedIndexInformer(
+		api.NewListWatchFromClient(c.rc, "WebSessions", c.ns, nil, pc),
+		&v1.WebSession{},
+		resyncPeriodWebSessionClient,
+		indexers,
+	)
+
+	return c
+}
+
+func (c *WebSessionClient) StartInformer(stopCh <-chan struct{}) {
+	c.informer.Run(stopCh)
+}
+
+func (c *WebSessionClient) AddInformerHandlers(reh framework.ResourceEventHandler) {
+	if c.informer == nil {
+		panic(fmt.Sprintf("%s informer not started", "WebSession"))
+	}
+
+	c.informer.AddEventHandler(reh)
+}
+
+func (c *WebSessionClient) HasSynced() bool {
+	if c.informer == nil {
+		return false
+	}
+	return c.informer.HasSynced()
+}
+
+type ItemDefaultableWebSessionClient interface {
+	SetDefaults()
+}
+
+func (c *WebSessionClient) NewEmpty() *v1.WebSession {
+	item := &v1.WebSession{}
+	item.GenerateName = "sitepod-websession-"
+	var aitem interface{}
+	aitem = item
+	if ditem, ok := aitem.(ItemDefaultableWebSessionClient); ok {
+		ditem.SetDefaults()
+	}
+
+	return item
+}
+
+//TODO: wrong location? shared?
+func (c *WebSessionClient) KeyOf(obj interface{}) string {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func (c *WebSessionClient) UIDOf(obj interface{}) (string, bool) {
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", false
+	}
+	return string(accessor.GetUID()), true
+}
+
+//TODO: wrong location? shared?
+func (c *WebSessionClient) DeepEqual(a interface{}, b interface{}) bool {
+	return k8s_api.Semantic.DeepEqual(a, b)
+}
+
+func (c *WebSessionClient) MaybeGetByKey(key string) (*v1.WebSession, bool) {
+
+	if !strings.Contains(key, "/") && true {
+		key = fmt.Sprintf("%s/%s", c.ns, key)
+	}
+
+	iObj, exists, err := c.informer.GetStore().GetByKey(key)
+
+	if err != nil {
+		panic(err)
+	}
+
+	if iObj == nil {
+		return nil, exists
+	} else {
+		item := c.CloneItem(iObj)
+		glog.Infof("Got %s from informer store with rv %s", "WebSession", item.ResourceVersion)
+		return item, exists
+	}
+}
+
+func (c *WebSessionClient) GetByKey(key string) *v1.WebSession {
+	item, exists := c.MaybeGetByKey(key)
+
+	if !exists {
+		panic("Not found " + "WebSession" + ": " + key)
+	}
+
+	return item
+}
+
+func (c *WebSessionClient) ByIndexByKey(index string, key string) []*v1.WebSession {
+
+	items, err := c.informer.GetIndexer().ByIndex(index, key)
+
+	if err != nil {
+		panic(err)
+	}
+
+	typedItems := []*v1.WebSession{}
+	for _, item := range items {
+		typedItems = append(typedItems, c.CloneItem(item))
+	}
+	return typedItems
+}
+
+func (c *WebSessionClient) BySitepodKey(sitepodKey string) []*v1.WebSession {
+	return c.ByIndexByKey("sitepod", sitepodKey)
+}
+
+func (c *WebSessionClient) BySitepodKeyFunc() func(string) []interface{} {
+	return func(sitepodKey string) []interface{} {
+		iArray := []interface{}{}
+		for _, r := range c.ByIndexByKey("sitepod", sitepodKey) {
+			iArray = append(iArray, r)
+		}
+		return iArray
+	}
+}
+
+func (c *WebSessionClient) MaybeSingleByUID(uid string) (*v1.WebSession, bool) {
+	items := c.ByIndexByKey("uid", uid)
+	if len(items) == 0 {
+		return nil, false
+	} else {
+		return items[0], true
+	}
+}
+
+func (c *WebSessionClient) SingleBySitepodKey(sitepodKey string) *v1.WebSession {
+
+	items := c.BySitepodKey(sitepodKey)
+
+	if len(items) == 0 {
+		panic(errors.New("None found"))
+	}
+
+	return items[0]
+
+}
+
+func (c *WebSessionClient) MaybeSingleBySitepodKey(sitepodKey string) (*v1.WebSession, bool) {
+
+	items := c.BySitepodKey(sitepodKey)
+
+	if len(items) == 0 {
+		return nil, false
+	} else {
+
+		if len(items) > 1 {
+			glog.Warningf("Unexpected number of %s for sitepod %s - %d items matched", "WebSessions", sitepodKey, len(items))
+		}
+
+		return items[0], true
+	}
+
+}
+
+type BeforeAdderWebSessionClient interface {
+	BeforeAdd()
+}
+
+func (c *WebSessionClient) Add(target *v1.WebSession) *v1.WebSession {
+
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *WebSessionClient) TryAdd(target *v1.WebSession) (*v1.WebSession, error) {
+
+	var itarget interface{}
+	itarget = target
+	if subject, ok := itarget.(BeforeAdderWebSessionClient); ok {
+		subject.BeforeAdd()
+	}
+
+	rcReq := c.rc.Post()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+
+	result := rcReq.Resource("WebSessions").Body(target).Do()
+
+	if err := result.Error(); err != nil {
+		return nil, err
+	}
+
+	r, err := result.Get()
+
+	if err != nil {
+		return nil, err
+	}
+	item := r.(*v1.WebSession)
+	glog.Infof("Added %s - %s (rv: %s)", "WebSession", item.Name, item.ResourceVersion)
+	return item, nil
+}
+
+func (c *WebSessionClient) CloneItem(orig interface{}) *v1.WebSession {
+	cloned, err := conversion.NewCloner().DeepCopy(orig)
+	if err != nil {
+		panic(err)
+	}
+	return cloned.(*v1.WebSession)
+}
+
+func (c *WebSessionClient) Update(target *v1.WebSession) *v1.WebSession {
+
+	item, err := c.TryUpdate(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *WebSessionClient) TryUpdate(target *v1.WebSession) (*v1.WebSession, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
+	rName := accessor.GetName()
+	rcReq := c.rc.Put()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	replacementTarget, err := rcReq.Resource("WebSessions").Name(rName).Body(target).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := replacementTarget.(*v1.WebSession)
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *WebSessionClient) FetchByName(name string) (*v1.WebSession, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("WebSessions").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*v1.WebSession)
+	return item, nil
+}
+
+func (c *WebSessionClient) UpdateOrAdd(target *v1.WebSession) *v1.WebSession {
+
+	if len(string(target.UID)) > 0 {
+		return c.Update(target)
+	} else {
+		return c.Add(target)
+	}
+}
+
+func (c *WebSessionClient) FetchList(s labels.Selector) []*v1.WebSession {
+
+	var prc *restclient.Request
+	if !true {
+		prc = c.rc.Get().Resource("WebSessions").LabelsSelectorParam(s)
+	} else {
+		prc = c.rc.Get().Resource("WebSessions").Namespace(c.ns).LabelsSelectorParam(s)
+	}
+
+	rObj, err := prc.Do().Get()
+
+	if err != nil {
+		panic(err)
+	}
+
+	target := []*v1.WebSession{}
+	kList := rObj.(*v1.WebSessionList)
+	for _, kItem := range kList.Items {
+		target = append(target, c.CloneItem(&kItem))
+	}
+
+	return target
+}
+
+func (c *WebSessionClient) TryDelete(target *v1.WebSession) error {
+
+	var prc *restclient.Request
+	if !true {
+		prc = c.rc.Delete().Resource("WebSessions").Name(target.Name)
+	} else {
+		prc = c.rc.Delete().Namespace(c.ns).Resource("WebSessions").Name(target.Name)
+	}
+
+	err := prc.Do().Error()
+	return err
+}
+
+func (c *WebSessionClient) Delete(target *v1.WebSession) {
+
+	err := c.TryDelete(target)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (c *WebSessionClient) DeleteFunc() func(interface{}) {
+	return func(iTarget interface{}) {
+
+		target := iTarget.(*v1.WebSession)
+
+		err := c.TryDelete(target)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func (c *WebSessionClient) List() []*v1.WebSession {
+	kItems := c.informer.GetStore().List()
+	target := []*v1.WebSession{}
+	for _, kItem := range kItems {
+		target = append(target, kItem.(*v1.WebSession))
+	}
+	return target
+}
+
+func (c *WebSessionClient) RestClient() *restclient.RESTClient {
+	return c.rc
+}
+
+func (c *WebSessionClient) RestClientConfig() *restclient.Config {
+	return c.rcConfig
+}
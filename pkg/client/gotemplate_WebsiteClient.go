@@ -243,6 +243,16 @@ type BeforeAdderWebsiteClient interface {
 
 func (c *WebsiteClient) Add(target *v1.Website) *v1.Website {
 
+	item, err := c.TryAdd(target)
+	if err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// TryAdd is Add returning the error, e.g. already exists, rather than panicking
+func (c *WebsiteClient) TryAdd(target *v1.Website) (*v1.Website, error) {
+
 	var itarget interface{}
 	itarget = target
 	if subject, ok := itarget.(BeforeAdderWebsiteClient); ok {
@@ -257,17 +267,17 @@ func (c *WebsiteClient) Add(target *v1.Website) *v1.Website {
 	result := rcReq.Resource("Websites").Body(target).Do()
 
 	if err := result.Error(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	r, err := result.Get()
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := r.(*v1.Website)
 	glog.Infof("Added %s - %s (rv: %s)", "Website", item.Name, item.ResourceVersion)
-	return item
+	return item, nil
 }
 
 func (c *WebsiteClient) CloneItem(orig interface{}) *v1.Website {
@@ -280,10 +290,20 @@ func (c *WebsiteClient) CloneItem(orig interface{}) *v1.Website {
 
 func (c *WebsiteClient) Update(target *v1.Website) *v1.Website {
 
-	accessor, err := meta.Accessor(target)
+	item, err := c.TryUpdate(target)
 	if err != nil {
 		panic(err)
 	}
+	return item
+}
+
+// TryUpdate is Update returning the error, e.g. a resourceVersion conflict, rather than panicking
+func (c *WebsiteClient) TryUpdate(target *v1.Website) (*v1.Website, error) {
+
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return nil, err
+	}
 	rName := accessor.GetName()
 	rcReq := c.rc.Put()
 	if true {
@@ -291,10 +311,25 @@ func (c *WebsiteClient) Update(target *v1.Website) *v1.Website {
 	}
 	replacementTarget, err := rcReq.Resource("Websites").Name(rName).Body(target).Do().Get()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	item := replacementTarget.(*v1.Website)
-	return item
+	return item, nil
+}
+
+// FetchByName gets the latest item direct from the api server bypassing the informer store
+func (c *WebsiteClient) FetchByName(name string) (*v1.Website, error) {
+
+	rcReq := c.rc.Get()
+	if true {
+		rcReq = rcReq.Namespace(c.ns)
+	}
+	obj, err := rcReq.Resource("Websites").Name(name).Do().Get()
+	if err != nil {
+		return nil, err
+	}
+	item := obj.(*v1.Website)
+	return item, nil
 }
 
 func (c *WebsiteClient) UpdateOrAdd(target *v1.Website) *v1.Website {
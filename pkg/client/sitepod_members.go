@@ -0,0 +1,28 @@
+package client
+
+import (
+	"k8s.io/kubernetes/pkg/client/cache"
+	"sitepod.io/sitepod/pkg/api/v1"
+)
+
+// SitepodMemberIndex indexes sitepods by the names of the sitepod users with a role on them
+const SitepodMemberIndex = "member"
+
+func sitepodMemberIndexFunc(obj interface{}) ([]string, error) {
+	sitepod, ok := obj.(*v1.Sitepod)
+	if !ok {
+		return []string{}, nil
+	}
+	return sitepod.MemberUsers(), nil
+}
+
+func (c *SitepodClient) addMemberIndexer() {
+	if err := c.informer.AddIndexers(cache.Indexers{SitepodMemberIndex: sitepodMemberIndexFunc}); err != nil {
+		panic(err)
+	}
+}
+
+// ByMember are the sitepods a sitepod user has any role on, from the informer store
+func (c *SitepodClient) ByMember(sitepodUserName string) []*v1.Sitepod {
+	return c.ByIndexByKey(SitepodMemberIndex, sitepodUserName)
+}
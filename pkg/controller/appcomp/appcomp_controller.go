@@ -4,10 +4,13 @@ package appcomp
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
 
 	"github.com/golang/glog"
 	k8s_api "k8s.io/kubernetes/pkg/api"
 	k8s_ext "k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/client/cache"
 	"k8s.io/kubernetes/pkg/controller/framework"
 	"k8s.io/kubernetes/pkg/util/intstr"
 	"sitepod.io/sitepod/pkg/api/v1"
@@ -22,13 +25,21 @@ type AppCompController struct {
 
 const (
 	SpecGenAnnontationKey = "sitepod.io/specgen-onetime"
+
+	// hostKeysAnnotationPrefix on the pod template restarts sshd when its host keys change
+	hostKeysAnnotationPrefix = "sitepod.io/ssh-hostkeys-"
+
+	// legacyEtcConfigMapName is the single namespace wide etc config map which exposed
+	// every sitepod's users to every other sitepod, replaced by a config map per sitepod
+	legacyEtcConfigMapName = "user-etcs"
 )
 
 func NewAppCompController(client *cc.Client) framework.ControllerInterface {
 
 	glog.Infof("Creating app component (appcomp) controller")
 	c := &AppCompController{*NewSimpleController("AppCompController",
-		client, []Syncer{client.Sitepods(), client.ConfigMaps(), client.PVClaims(), client.PVs(), client.Deployments()}, nil, nil)}
+		client, []Syncer{client.Sitepods(), client.ConfigMaps(), client.PVClaims(), client.PVs(), client.Deployments(),
+			client.Clusters(), client.SystemUsers(), client.Secrets()}, nil, nil)}
 	c.SyncFunc = c.ProcessUpdate
 	//sc.DeleteFunc = sc.ProcessDelete
 	client.AppComps().AddInformerHandlers(framework.ResourceEventHandlerFuncs{
@@ -36,9 +47,46 @@ func NewAppCompController(client *cc.Client) framework.ControllerInterface {
 		UpdateFunc: c.QueueUpdate,
 		DeleteFunc: c.QueueDelete,
 	})
+	// sshd_config lists the users allowed to log in
+	client.SystemUsers().AddInformerHandlers(framework.ResourceEventHandlerFuncs{
+		AddFunc:    c.QueueSSHComps,
+		UpdateFunc: func(old interface{}, cur interface{}) { c.QueueSSHComps(cur) },
+		DeleteFunc: c.QueueSSHComps,
+	})
+	// host key rotation replaces the secret data
+	client.Secrets().AddInformerHandlers(framework.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old interface{}, cur interface{}) { c.QueueHostKeysComp(cur) },
+	})
 	return c
 }
 
+func (c *AppCompController) QueueHostKeysComp(item interface{}) {
+	secret, ok := item.(*k8s_api.Secret)
+	if !ok || secret.Labels["secret-type"] != specgen.HostKeysSecretType {
+		return
+	}
+	for _, ac := range c.Client.AppComps().BySitepodKey(secret.Labels["sitepod"]) {
+		if ac.Name == secret.Labels["appcomponent"] {
+			c.QueueAdd(ac)
+		}
+	}
+}
+
+func (c *AppCompController) QueueSSHComps(item interface{}) {
+	if tombstone, ok := item.(cache.DeletedFinalStateUnknown); ok {
+		item = tombstone.Obj
+	}
+	user, ok := item.(*v1.SystemUser)
+	if !ok {
+		return
+	}
+	for _, ac := range c.Client.AppComps().BySitepodKey(user.Labels["sitepod"]) {
+		if ac.Spec.SSH != nil {
+			c.QueueAdd(ac)
+		}
+	}
+}
+
 func (c *AppCompController) QueueAdd(item interface{}) {
 	c.EnqueueUpdate(c.Client.AppComps().KeyOf(item))
 }
@@ -92,6 +140,12 @@ func (c *AppCompController) ProcessUpdate(key string) error {
 		}
 	}
 
+	if ac.Spec.Type == "ssh" && migrateSSHAppComp(ac) {
+		glog.Infof("Moving host keys of app comp %s out of its spec", ac.Name)
+		c.Client.AppComps().Update(ac)
+		return nil
+	}
+
 	var destContainer *k8s_api.Container
 	destIdx := -1
 
@@ -113,9 +167,14 @@ func (c *AppCompController) ProcessUpdate(key string) error {
 	destContainer.Image = fmt.Sprintf("%s:%s", ac.Spec.Image, ac.Spec.ImageVersion)
 	destContainer.ImagePullPolicy = k8s_api.PullAlways
 
+	configFiles := ac.Spec.ConfigFiles
+	if ac.Spec.SSH != nil {
+		configFiles = c.withSSHDConfig(ac, sitepodKey)
+	}
+
 	groupedConfigFiles := make(map[string][]v1.AppComponentConfigFile)
 
-	for _, acConfigFile := range ac.Spec.ConfigFiles {
+	for _, acConfigFile := range configFiles {
 		groupedConfigFiles[acConfigFile.Directory] = append(groupedConfigFiles[acConfigFile.Directory], acConfigFile)
 	}
 
@@ -159,19 +218,35 @@ func (c *AppCompController) ProcessUpdate(key string) error {
 		c.attachConfigMap(deployment, destContainer, matchedConfigMap, keyMap)
 	}
 
-	if ac.Spec.MountEtcs {
+	if ac.Spec.SSH != nil {
+		updated, err := c.attachHostKeys(ac, sitepodKey, deployment, destContainer)
+		if err != nil {
+			return err
+		}
+		if updated {
+			// requeued by the status update
+			return nil
+		}
+	}
 
-		globalConfigMapList := c.Client.ConfigMaps().List()
-		for _, configMap := range globalConfigMapList {
+	if ac.Spec.MountEtcs {
 
-			if configMap.Labels["config-type"] != "etc" {
-				continue
+		// only this sitepod's users, never the etc config maps of other sitepods
+		var etcConfigMap *k8s_api.ConfigMap
+		for _, configMap := range configMapList {
+			if configMap.Labels["config-type"] == "etc" {
+				etcConfigMap = configMap
+				break
 			}
+		}
 
-			c.attachConfigMap(deployment, destContainer, configMap, nil)
-
+		if etcConfigMap == nil {
+			return DependentResourcesNotReady{fmt.Sprintf("Etc config map for sitepod %s does not yet exist.", sitepodKey)}
 		}
+
+		c.attachConfigMap(deployment, destContainer, etcConfigMap, nil)
 	}
+	c.detachConfigMap(deployment, destContainer, legacyEtcConfigMapName)
 
 	if ac.Spec.MountTemp {
 
@@ -263,8 +338,10 @@ func (c *AppCompController) ProcessUpdate(key string) error {
 			},
 		}
 
-		//Presume cluster exists
-		cluster := c.Client.Clusters().GetByKey("sitepod-alpha")
+		cluster, err := c.Client.ActiveCluster()
+		if err != nil {
+			return DependentConfigNotValid{err.Error()}
+		}
 
 		if cluster.Spec.UseLoadBalancer {
 			service.Spec.Type = k8s_api.ServiceTypeLoadBalancer
@@ -281,10 +358,200 @@ func (c *AppCompController) ProcessUpdate(key string) error {
 	}
 
 	c.Client.Deployments().Update(deployment)
+	c.removeLegacyEtcs(deployment)
 
 	return nil
 }
 
+// removeLegacyEtcs deletes the legacy etc config map once no deployment mounts it any
+// more, pods of a deployment still mounting it would fail to start without it. The
+// deployment just updated is taken as is, the cache may not have caught up with it.
+func (c *AppCompController) removeLegacyEtcs(updated *k8s_ext.Deployment) {
+
+	legacy, exists := c.Client.ConfigMaps().MaybeGetByKey(legacyEtcConfigMapName)
+	if !exists {
+		return
+	}
+
+	for _, deployment := range c.Client.Deployments().List() {
+		if deployment.Name == updated.Name {
+			deployment = updated
+		}
+		for _, volume := range deployment.Spec.Template.Spec.Volumes {
+			if volume.ConfigMap != nil && volume.ConfigMap.Name == legacyEtcConfigMapName {
+				return
+			}
+		}
+	}
+
+	glog.Infof("Removing legacy config map %s, no deployment mounts it", legacyEtcConfigMapName)
+	if err := c.Client.ConfigMaps().TryDelete(legacy); err != nil {
+		glog.Errorf("Unable to remove legacy config map %s: %+v", legacyEtcConfigMapName, err)
+	}
+}
+
+// withSSHDConfig regenerates sshd_config with the sitepod's current system users, the app
+// component itself is left as is
+func (c *AppCompController) withSSHDConfig(ac *v1.Appcomponent, sitepodKey string) []v1.AppComponentConfigFile {
+
+	users := []*v1.SystemUser{}
+	for _, user := range c.Client.SystemUsers().BySitepodKey(sitepodKey) {
+		if user.Status.AssignedFileUID != 0 {
+			users = append(users, user)
+		}
+	}
+	// stable output so the config map only changes with the users
+	sort.Sort(byUsername(users))
+
+	configFiles := []v1.AppComponentConfigFile{}
+	for _, configFile := range ac.Spec.ConfigFiles {
+		if configFile.Name == specgen.SSHDConfigName {
+			configFile.Content = specgen.GenerateSSHDConfig(*ac.Spec.SSH, users)
+		}
+		configFiles = append(configFiles, configFile)
+	}
+	return configFiles
+}
+
+type byUsername []*v1.SystemUser
+
+func (u byUsername) Len() int           { return len(u) }
+func (u byUsername) Swap(i, j int)      { u[i], u[j] = u[j], u[i] }
+func (u byUsername) Less(i, j int) bool { return u[i].GetUsername() < u[j].GetUsername() }
+
+// migrateSSHAppComp drops host keys kept as config files by earlier versions and sets
+// default ssh options, true if the app component was changed
+func migrateSSHAppComp(ac *v1.Appcomponent) bool {
+
+	changed := false
+	if ac.Spec.SSH == nil {
+		ac.Spec.SSH = &v1.SSHOptions{PasswordAuthentication: true, PublicKeyAuthentication: true}
+		changed = true
+	}
+
+	configFiles := []v1.AppComponentConfigFile{}
+	for _, configFile := range ac.Spec.ConfigFiles {
+		if configFile.Name == "sshprivate" || configFile.Name == "sshpublic" {
+			changed = true
+			continue
+		}
+		configFiles = append(configFiles, configFile)
+	}
+	ac.Spec.ConfigFiles = configFiles
+
+	return changed
+}
+
+// attachHostKeys mounts the host key secret of an ssh app component, generating it on
+// first use, and publishes the fingerprints in the status. True is returned when the
+// status was updated.
+func (c *AppCompController) attachHostKeys(ac *v1.Appcomponent, sitepodKey string,
+	deployment *k8s_ext.Deployment, container *k8s_api.Container) (bool, error) {
+
+	secretName := specgen.HostKeysSecretName(ac)
+	secret, exists := c.Client.Secrets().MaybeGetByKey(secretName)
+	if !exists {
+
+		data, err := specgen.GenerateHostKeys()
+		if err != nil {
+			return false, err
+		}
+
+		secret = c.Client.Secrets().NewEmpty()
+		secret.GenerateName = ""
+		secret.Name = secretName
+		if secret.Labels == nil {
+			secret.Labels = make(map[string]string)
+		}
+		secret.Labels["sitepod"] = sitepodKey
+		secret.Labels["appcomponent"] = ac.Name
+		secret.Labels["secret-type"] = specgen.HostKeysSecretType
+		secret.Data = data
+
+		glog.Infof("Generating host keys for app comp %s", ac.Name)
+		secret, err = c.Client.Secrets().TryAdd(secret)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	fingerprints, err := specgen.HostKeyFingerprints(secret.Data)
+	if err != nil {
+		return false, DependentConfigNotValid{err.Error()}
+	}
+
+	if !reflect.DeepEqual(fingerprints, ac.Status.HostKeyFingerprints) {
+		ac.Status.HostKeyFingerprints = fingerprints
+		c.Client.AppComps().Update(ac)
+		return true, nil
+	}
+
+	vmExists := false
+	for _, vm := range container.VolumeMounts {
+		if vm.Name == secretName {
+			vmExists = true
+			break
+		}
+	}
+
+	if !vmExists {
+		container.VolumeMounts = append(container.VolumeMounts, k8s_api.VolumeMount{
+			Name:      secretName,
+			MountPath: specgen.SSHHostKeyDirectory,
+			ReadOnly:  true,
+		})
+	}
+
+	dvExists := false
+	for _, dv := range deployment.Spec.Template.Spec.Volumes {
+		if dv.Name == secretName {
+			dvExists = true
+			break
+		}
+	}
+
+	if !dvExists {
+		mode := int32(0400)
+		deployment.Spec.Template.Spec.Volumes = append(deployment.Spec.Template.Spec.Volumes, k8s_api.Volume{
+			Name: secretName,
+			VolumeSource: k8s_api.VolumeSource{
+				Secret: &k8s_api.SecretVolumeSource{
+					SecretName:  secretName,
+					DefaultMode: &mode,
+				},
+			},
+		})
+	}
+
+	if deployment.Spec.Template.Annotations == nil {
+		deployment.Spec.Template.Annotations = make(map[string]string)
+	}
+	deployment.Spec.Template.Annotations[hostKeysAnnotationPrefix+ac.Name] = secret.ResourceVersion
+
+	return false, nil
+}
+
+// detachConfigMap removes the volume and volume mount of a config map which should no
+// longer be mounted, e.g. the old namespace wide user-etcs
+func (c *AppCompController) detachConfigMap(deployment *k8s_ext.Deployment, container *k8s_api.Container, name string) {
+
+	volumeMounts := []k8s_api.VolumeMount{}
+	for _, vm := range container.VolumeMounts {
+		if vm.Name != name {
+			volumeMounts = append(volumeMounts, vm)
+		}
+	}
+	container.VolumeMounts = volumeMounts
+
+	volumes := []k8s_api.Volume{}
+	for _, dv := range deployment.Spec.Template.Spec.Volumes {
+		if dv.Name != name {
+			volumes = append(volumes, dv)
+		}
+	}
+	deployment.Spec.Template.Spec.Volumes = volumes
+}
+
 func (c *AppCompController) attachConfigMap(deployment *k8s_ext.Deployment, container *k8s_api.Container, cm *k8s_api.ConfigMap, km map[string]string) {
 
 	vmExists := false
@@ -0,0 +1,111 @@
+package backup
+
+// BackupController listens for new backups and streams a tarball of the sitepod home
+// volume (or just the selected websites) out of sitepod-manager into the backup target
+// by way of a podtask.
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	"k8s.io/kubernetes/pkg/controller/framework"
+	"sitepod.io/sitepod/pkg/api/v1"
+	"sitepod.io/sitepod/pkg/backup"
+	cc "sitepod.io/sitepod/pkg/client"
+	. "sitepod.io/sitepod/pkg/controller/shared"
+)
+
+type BackupController struct {
+	SimpleController
+}
+
+func NewBackupController(client *cc.Client) framework.ControllerInterface {
+
+	glog.Infof("Creating backup controller")
+	c := &BackupController{*NewSimpleController("BackupController", client, []Syncer{client.Backups(),
+		client.Sitepods(), client.Websites(), client.Pods(), client.PodTasks()}, nil, nil)}
+	c.SyncFunc = c.ProcessUpdate
+	client.Backups().AddInformerHandlers(framework.ResourceEventHandlerFuncs{
+		AddFunc:    c.QueueAdd,
+		UpdateFunc: c.QueueUpdate,
+	})
+	return c
+}
+
+func (c *BackupController) QueueAdd(item interface{}) {
+	c.EnqueueUpdate(c.Client.Backups().KeyOf(item))
+}
+
+func (c *BackupController) QueueUpdate(old interface{}, cur interface{}) {
+	if !c.Client.Backups().DeepEqual(old, cur) {
+		c.EnqueueUpdate(c.Client.Backups().KeyOf(cur))
+	}
+}
+
+func (c *BackupController) ProcessUpdate(key string) error {
+
+	b, exists := c.Client.Backups().MaybeGetByKey(key)
+
+	if !exists {
+		glog.Infof("Backup %s no longer exists", key)
+		return nil
+	}
+
+	if b.Status.Completed {
+		glog.Infof("Backup %s already completed", key)
+		return nil
+	}
+
+	sitepodKey := b.Labels["sitepod"]
+	_, exists = c.Client.Sitepods().MaybeSingleByUID(sitepodKey)
+	if !exists {
+		glog.Infof("Sitepod %s no longer exists, skipping backup %s", sitepodKey, b.Name)
+		return nil
+	}
+
+	if len(b.Status.Archive) == 0 {
+		// record the archive name first, this requeues us
+		b.Status.Archive = backup.ArchiveName(sitepodKey, b.Name)
+		c.Client.Backups().Update(b)
+		return nil
+	}
+
+	paths, err := c.backupPaths(b)
+	if err != nil {
+		return err
+	}
+
+	cmd := append([]string{"/bin/tar", "-czf", "-", "-C", "/home"}, paths...)
+	stream := &v1.PodTaskStream{Target: b.Spec.Target, Object: b.Status.Archive}
+
+	return SubmitManagerPodTask(c.Client, sitepodKey, "Backup", b.Name, "Completed", cmd, func(podTask *v1.Podtask) {
+		podTask.Spec.StdoutTo = stream
+	})
+}
+
+// backupPaths are relative to /home in the sitepod-manager container, only websites of
+// the backup's own sitepod are included
+func (c *BackupController) backupPaths(b *v1.Backup) ([]string, error) {
+
+	if len(b.Spec.Websites) == 0 {
+		return []string{"."}, nil
+	}
+
+	paths := []string{}
+	for _, websiteName := range b.Spec.Websites {
+		website, exists := c.Client.Websites().MaybeGetByKey(websiteName)
+		if !exists {
+			return nil, DependentConfigNotValid{fmt.Sprintf("Website %s of backup %s does not exist", websiteName, b.Name)}
+		}
+		if website.Labels["sitepod"] != b.Labels["sitepod"] {
+			return nil, DependentConfigNotValid{fmt.Sprintf("Website %s of backup %s belongs to another sitepod", websiteName, b.Name)}
+		}
+		documentRoot, err := website.GetDocumentRoot()
+		if err != nil {
+			return nil, DependentConfigNotValid{err.Error()}
+		}
+		paths = append(paths, strings.TrimPrefix(documentRoot, "/home/"))
+	}
+	return paths, nil
+}
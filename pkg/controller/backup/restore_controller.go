@@ -0,0 +1,89 @@
+package backup
+
+// RestoreController listens for new restores and streams the archive of the referenced
+// backup back into the home volume of the backup's sitepod by way of a podtask.
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"k8s.io/kubernetes/pkg/controller/framework"
+	"sitepod.io/sitepod/pkg/api/v1"
+	cc "sitepod.io/sitepod/pkg/client"
+	. "sitepod.io/sitepod/pkg/controller/shared"
+)
+
+type RestoreController struct {
+	SimpleController
+}
+
+func NewRestoreController(client *cc.Client) framework.ControllerInterface {
+
+	glog.Infof("Creating restore controller")
+	c := &RestoreController{*NewSimpleController("RestoreController", client, []Syncer{client.Restores(),
+		client.Backups(), client.Sitepods(), client.Pods(), client.PodTasks()}, nil, nil)}
+	c.SyncFunc = c.ProcessUpdate
+	client.Restores().AddInformerHandlers(framework.ResourceEventHandlerFuncs{
+		AddFunc:    c.QueueAdd,
+		UpdateFunc: c.QueueUpdate,
+	})
+	return c
+}
+
+func (c *RestoreController) QueueAdd(item interface{}) {
+	c.EnqueueUpdate(c.Client.Restores().KeyOf(item))
+}
+
+func (c *RestoreController) QueueUpdate(old interface{}, cur interface{}) {
+	if !c.Client.Restores().DeepEqual(old, cur) {
+		c.EnqueueUpdate(c.Client.Restores().KeyOf(cur))
+	}
+}
+
+func (c *RestoreController) ProcessUpdate(key string) error {
+
+	restore, exists := c.Client.Restores().MaybeGetByKey(key)
+
+	if !exists {
+		glog.Infof("Restore %s no longer exists", key)
+		return nil
+	}
+
+	if restore.Status.Completed {
+		glog.Infof("Restore %s already completed", key)
+		return nil
+	}
+
+	b, exists := c.Client.Backups().MaybeGetByKey(restore.Spec.Backup)
+	if !exists {
+		return DependentConfigNotValid{fmt.Sprintf("Backup %s of restore %s does not exist", restore.Spec.Backup, key)}
+	}
+
+	if !b.Status.Completed {
+		return ConditionsNotReady{fmt.Sprintf("Backup %s not yet completed", b.Name)}
+	}
+
+	if b.Status.Pruning {
+		return DependentConfigNotValid{fmt.Sprintf("Backup %s of restore %s is being pruned", b.Name, key)}
+	}
+
+	// an archive is only ever restored into the sitepod it was taken of
+	sitepodKey := b.Labels["sitepod"]
+	if restoreKey := restore.Labels["sitepod"]; len(restoreKey) > 0 && restoreKey != sitepodKey {
+		return DependentConfigNotValid{fmt.Sprintf("Restore %s is for sitepod %s but backup %s is of sitepod %s",
+			key, restoreKey, b.Name, sitepodKey)}
+	}
+
+	_, exists = c.Client.Sitepods().MaybeSingleByUID(sitepodKey)
+	if !exists {
+		glog.Infof("Sitepod %s no longer exists, skipping restore %s", sitepodKey, restore.Name)
+		return nil
+	}
+
+	cmd := []string{"/bin/tar", "-xzf", "-", "-C", "/home"}
+	stream := &v1.PodTaskStream{Target: b.Spec.Target, Object: b.Status.Archive}
+
+	return SubmitManagerPodTask(c.Client, sitepodKey, "Restore", restore.Name, "Completed", cmd, func(podTask *v1.Podtask) {
+		podTask.Spec.StdinFrom = stream
+	})
+}
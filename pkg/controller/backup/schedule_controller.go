@@ -0,0 +1,153 @@
+package backup
+
+// BackupScheduleController creates backups for a sitepod at a fixed interval and prunes
+// completed backups (and their archives) beyond the retention count.
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	kerrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/controller/framework"
+	"sitepod.io/sitepod/pkg/api/v1"
+	"sitepod.io/sitepod/pkg/backup"
+	cc "sitepod.io/sitepod/pkg/client"
+	. "sitepod.io/sitepod/pkg/controller/shared"
+)
+
+type BackupScheduleController struct {
+	SimpleController
+}
+
+func NewBackupScheduleController(client *cc.Client) framework.ControllerInterface {
+
+	glog.Infof("Creating backup schedule controller")
+	c := &BackupScheduleController{*NewSimpleController("BackupScheduleController", client,
+		[]Syncer{client.BackupSchedules(), client.Backups(), client.Sitepods()}, nil, nil)}
+	c.SyncFunc = c.ProcessUpdate
+	client.BackupSchedules().AddInformerHandlers(framework.ResourceEventHandlerFuncs{
+		AddFunc:    c.QueueAdd,
+		UpdateFunc: c.QueueUpdate,
+	})
+	return c
+}
+
+func (c *BackupScheduleController) QueueAdd(item interface{}) {
+	c.EnqueueUpdate(c.Client.BackupSchedules().KeyOf(item))
+}
+
+func (c *BackupScheduleController) QueueUpdate(old interface{}, cur interface{}) {
+	if !c.Client.BackupSchedules().DeepEqual(old, cur) {
+		c.EnqueueUpdate(c.Client.BackupSchedules().KeyOf(cur))
+	}
+}
+
+func (c *BackupScheduleController) ProcessUpdate(key string) error {
+
+	schedule, exists := c.Client.BackupSchedules().MaybeGetByKey(key)
+
+	if !exists {
+		glog.Infof("Backup schedule %s no longer exists", key)
+		return nil
+	}
+
+	sitepodKey := schedule.Labels["sitepod"]
+	_, exists = c.Client.Sitepods().MaybeSingleByUID(sitepodKey)
+	if !exists {
+		glog.Infof("Sitepod %s no longer exists, skipping backup schedule %s", sitepodKey, schedule.Name)
+		return nil
+	}
+
+	// a failed prune must not hold up the schedule, it is retried on the next pass
+	pruneErr := c.prune(schedule)
+	if pruneErr != nil {
+		glog.Errorf("Error pruning backups of schedule %s: %+v", key, pruneErr)
+	}
+
+	interval := time.Duration(schedule.Spec.IntervalMinutes) * time.Minute
+	if interval <= 0 {
+		return DependentConfigNotValid{"Backup schedule " + key + " requires a positive interval"}
+	}
+
+	due := schedule.Status.LastRunAt.Add(interval)
+	if now := time.Now(); now.Before(due) {
+		c.EnqueueUpdateAfter(key, int(due.Sub(now).Seconds())+1)
+		return pruneErr
+	}
+
+	b := c.Client.Backups().NewEmpty()
+	b.GenerateName = schedule.Name + "-"
+	b.Labels["sitepod"] = sitepodKey
+	b.Labels["backupschedule"] = schedule.Name
+	b.Spec.Websites = schedule.Spec.Websites
+	b.Spec.Target = schedule.Spec.Target
+	b = c.Client.Backups().Add(b)
+	glog.Infof("Created scheduled backup %s for %s", b.Name, key)
+
+	schedule.Status.LastBackup = b.Name
+	schedule.Status.LastRunAt = unversioned.Now()
+	// the status update requeues us to wait out the next interval and retry a failed
+	// prune, retrying here as well could run before the cache has the new LastRunAt
+	c.Client.BackupSchedules().Update(schedule)
+	return nil
+}
+
+// prune removes completed backups of the schedule beyond its retention, newest are kept.
+// A backup is marked pruning before its archive is deleted and only deleted itself after,
+// so a failure at any step, returned as ConditionsNotReady, is retried without leaving a
+// backup of a missing archive.
+func (c *BackupScheduleController) prune(schedule *v1.Backupschedule) error {
+
+	completed := byCreation{}
+	pruning := []*v1.Backup{}
+	for _, b := range c.Client.Backups().BySitepodKey(schedule.Labels["sitepod"]) {
+		if b.Labels["backupschedule"] != schedule.Name || !b.Status.Completed {
+			continue
+		}
+		if b.Status.Pruning {
+			pruning = append(pruning, b)
+		} else {
+			completed = append(completed, b)
+		}
+	}
+
+	if len(completed) > schedule.Spec.Retention {
+		sort.Sort(completed)
+		for _, b := range completed[schedule.Spec.Retention:] {
+			b.Status.Pruning = true
+			updated, err := c.Client.Backups().TryUpdate(b)
+			if err != nil {
+				return ConditionsNotReady{fmt.Sprintf("Unable to mark backup %s for pruning: %s", b.Name, err)}
+			}
+			pruning = append(pruning, updated)
+		}
+	}
+
+	for _, b := range pruning {
+		target, err := backup.Open(c.Client, b.Spec.Target)
+		if err != nil {
+			return DependentConfigNotValid{fmt.Sprintf("Backup target of %s: %s", b.Name, err)}
+		}
+		if err := target.Delete(b.Status.Archive); err != nil {
+			return ConditionsNotReady{fmt.Sprintf("Unable to delete archive of backup %s: %s", b.Name, err)}
+		}
+		if err := c.Client.Backups().TryDelete(b); err != nil && !kerrors.IsNotFound(err) {
+			return ConditionsNotReady{fmt.Sprintf("Unable to delete backup %s: %s", b.Name, err)}
+		}
+		glog.Infof("Pruned backup %s of schedule %s", b.Name, schedule.Name)
+	}
+
+	return nil
+}
+
+// byCreation sorts newest first
+type byCreation []*v1.Backup
+
+func (s byCreation) Len() int      { return len(s) }
+func (s byCreation) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byCreation) Less(i, j int) bool {
+	return s[j].CreationTimestamp.Time.Before(s[i].CreationTimestamp.Time)
+}
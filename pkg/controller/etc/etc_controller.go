@@ -5,16 +5,21 @@ package etc
 import (
 	"bytes"
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 	"text/template"
 
 	//. "github.com/ahmetalpbalkan/go-linq"
 	"github.com/golang/glog"
 	//k8s_api "k8s.io/kubernetes/pkg/api"
 	//kerrors "k8s.io/kubernetes/pkg/api/errors"
-	//"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/api/meta"
+	"k8s.io/kubernetes/pkg/api/unversioned"
 	//ext_api "k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/client/cache"
 	"k8s.io/kubernetes/pkg/controller/framework"
-	//"sitepod.io/sitepod/pkg/api/v1"
+	"sitepod.io/sitepod/pkg/api/v1"
 	cc "sitepod.io/sitepod/pkg/client"
 	. "sitepod.io/sitepod/pkg/controller/shared"
 )
@@ -27,57 +32,108 @@ func NewEtcController(client *cc.Client) framework.ControllerInterface {
 
 	glog.Infof("Creating etc controller")
 	sc := &EtcController{*NewSimpleController("EtcController", client, []Syncer{client.SystemUsers(),
-		client.ConfigMaps(), client.Sitepods()}, nil, nil)}
+		client.SystemGroups(), client.ConfigMaps(), client.Sitepods()}, nil, nil)}
 	sc.SyncFunc = sc.ProcessUpdate
 	client.SystemUsers().AddInformerHandlers(framework.ResourceEventHandlerFuncs{
 		AddFunc:    sc.QueueAdd,
 		UpdateFunc: sc.QueueUpdate,
 		DeleteFunc: sc.QueueDelete,
 	})
+	client.SystemGroups().AddInformerHandlers(framework.ResourceEventHandlerFuncs{
+		AddFunc:    sc.QueueAdd,
+		UpdateFunc: sc.QueueUpdate,
+		DeleteFunc: sc.QueueDelete,
+	})
+	client.Sitepods().AddInformerHandlers(framework.ResourceEventHandlerFuncs{
+		AddFunc:    sc.QueueSitepod,
+		DeleteFunc: sc.QueueSitepod,
+	})
 	return sc
 }
 
-var (
-	SystemUserEtcs = []string{"users"}
+const (
+	// AuthorizedKeysPrefix prefixes the config map key of each user's authorized keys, sshd
+	// reads them with AuthorizedKeysCommand as they can't be owned by the user
+	AuthorizedKeysPrefix = "authorized_keys."
 )
 
+// EtcConfigMapName is the name of the config map holding passwd, shadow and group of a sitepod
+func EtcConfigMapName(sitepodKey string) string {
+	return "etc-" + sitepodKey
+}
+
 func (c *EtcController) Run(stopCh <-chan struct{}) {
-	// We want to fire a run even if there are no system users
-	c.QueueAdd(struct{}{})
+	c.WaitReady()
+
+	// We want to fire a run for every sitepod even if there are no system users
+	for _, sitepod := range c.Client.Sitepods().List() {
+		c.EnqueueUpdate(string(sitepod.UID))
+	}
 	c.SimpleController.Run(stopCh)
 }
 
+// QueueAdd queues the sitepod of a system user or system group
 func (c *EtcController) QueueAdd(item interface{}) {
-	for _, etcKey := range SystemUserEtcs {
-		c.EnqueueUpdate(etcKey)
+	if accessor, err := meta.Accessor(item); err == nil {
+		c.EnqueueUpdate(accessor.GetLabels()["sitepod"])
+	}
+}
+
+func (c *EtcController) QueueSitepod(item interface{}) {
+	if tombstone, ok := item.(cache.DeletedFinalStateUnknown); ok {
+		item = tombstone.Obj
+	}
+	if sitepod, ok := item.(*v1.Sitepod); ok {
+		c.EnqueueUpdate(string(sitepod.UID))
 	}
 }
 
 func (c *EtcController) QueueUpdate(old interface{}, cur interface{}) {
-	if !c.Client.SystemUsers().DeepEqual(old, cur) {
+	if !reflect.DeepEqual(old, cur) {
+		// a user moved between sitepods has to leave the old one
+		c.QueueAdd(old)
 		c.QueueAdd(cur)
 	}
 }
 
 func (c *EtcController) QueueDelete(deleted interface{}) {
+	if tombstone, ok := deleted.(cache.DeletedFinalStateUnknown); ok {
+		deleted = tombstone.Obj
+	}
 	// we rewrite etc configmaps when a user is removed so this similar as add/update
 	c.QueueAdd(deleted)
 }
 
-func (c *EtcController) ProcessUpdate(key string) error {
+// ProcessUpdate rebuilds the etc config map of a single sitepod, key is the sitepod uid
+func (c *EtcController) ProcessUpdate(sitepodKey string) error {
+
+	if len(sitepodKey) == 0 {
+		return nil
+	}
+
+	configName := EtcConfigMapName(sitepodKey)
+	config, exists := c.Client.ConfigMaps().MaybeGetByKey(configName)
 
-	glog.Info("Rebuilding passwd and shadow content")
+	_, sitepodExists := c.Client.Sitepods().MaybeSingleByUID(sitepodKey)
+	if !sitepodExists {
+		if exists {
+			glog.Infof("Sitepod %s no longer exists, removing config map %s", sitepodKey, configName)
+			c.Client.ConfigMaps().Delete(config)
+		}
+		return nil
+	}
+
+	glog.Infof("Rebuilding passwd, shadow and group content for sitepod %s", sitepodKey)
 	passwdContent := []string{}
 	shadowContent := []string{}
 
-	systemUsers := c.Client.SystemUsers().List()
+	systemUsers := c.Client.SystemUsers().BySitepodKey(sitepodKey)
 
-	config, exists := c.Client.ConfigMaps().MaybeGetByKey("user-etcs")
 	if !exists {
-		//TODO NewEmpty to accept minimum name or generateName?
 		config = c.Client.ConfigMaps().NewEmpty()
-		config.Name = "user-etcs"
-		glog.Info("Creating new config map user-etcs")
+		config.GenerateName = ""
+		config.Name = configName
+		glog.Infof("Creating new config map %s", configName)
 	} else {
 		glog.Infof("Using existing  config map %s : %s", string(config.UID), config.GetName())
 	}
@@ -85,9 +141,20 @@ func (c *EtcController) ProcessUpdate(key string) error {
 	glog.Infof("Building config with %d system users", len(systemUsers))
 	for _, user := range systemUsers {
 
+		if user.Status.AssignedFileUID == 0 {
+			// no uid yet, the update assigning one will bring us back
+			continue
+		}
+
+		// never format fields that could break out of their passwd or shadow line
+		if !user.HasValidUsername() || !user.HasValidShell() {
+			glog.Warningf("Leaving system user %s out of etc of sitepod %s, invalid username or shell", user.Name, sitepodKey)
+			continue
+		}
+
 		passwdContent = append(passwdContent, fmt.Sprintf("%s:%s:%d:%d:%s:%s:%s\n",
 			user.GetUsername(),
-			"x", //auth method
+			"x",                         //auth method
 			user.Status.AssignedFileUID, //uid
 			2000,
 			"", //gecos field
@@ -95,34 +162,67 @@ func (c *EtcController) ProcessUpdate(key string) error {
 			user.GetShell()))
 
 		if user.Spec.Password.IsValid() {
-			shadowPassword := fmt.Sprintf("$6$%s$%s",
-				user.Spec.Password.Salt,
-				user.Spec.Password.CombinedHash)
-
+			aging := user.Spec.Aging
 			shadowContent = append(shadowContent, fmt.Sprintf("%s:%s:%s:%s:%s:%s:%s:%s:%s\n",
-				user.GetUsername(), //login name
-				shadowPassword,     //encrypted password
-				"",                 //date last changed
-				"",                 //minimum password age,
-				"",                 //maximum password age,
-				"",                 //password warning period
-				"",                 //password inactivity period
-				"",                 //account expiration date
-				"",                 //reserved for future use
+				user.GetUsername(),                       //login name
+				user.Spec.Password.CryptString(),         //encrypted password
+				shadowDate(user.Spec.Password.ChangedAt), //date last changed
+				shadowDays(aging.MinAgeDays),             //minimum password age,
+				shadowDays(aging.MaxAgeDays),             //maximum password age,
+				shadowDays(aging.WarnDays),               //password warning period
+				shadowDays(aging.InactiveDays),           //password inactivity period
+				shadowDate(aging.AccountExpires),         //account expiration date
+				"",                                       //reserved for future use
 			))
 		}
 	}
 
 	passwdOutput := processTemplate("etc_passwd", passwdContent)
 	shadowOutput := processTemplate("etc_shadow", shadowContent)
-	groupOutput := processTemplate("etc_group", []string{})
+	groupContent := []string{}
+	for _, group := range c.Client.SystemGroups().BySitepodKey(sitepodKey) {
+
+		if group.Status.AssignedGID == 0 || !group.HasValidGroupname() {
+			continue
+		}
+
+		members := []string{}
+		for _, user := range systemUsers {
+			if user.Status.AssignedFileUID != 0 && user.HasValidUsername() && group.HasMember(user.GetUsername()) {
+				members = append(members, user.GetUsername())
+			}
+		}
+
+		groupContent = append(groupContent, fmt.Sprintf("%s:%s:%d:%s\n",
+			group.GetGroupname(),
+			"x",                      //password
+			group.Status.AssignedGID, //gid
+			strings.Join(members, ","),
+		))
+	}
+
+	groupOutput := processTemplate("etc_group", groupContent)
 
 	// Move to defaulters
 	if config.Labels == nil {
 		config.Labels = make(map[string]string)
 	}
-	if config.Data == nil {
-		config.Data = make(map[string]string)
+	// rebuilt from scratch so keys of removed users do not linger
+	config.Data = make(map[string]string)
+
+	for _, user := range systemUsers {
+		if user.Status.AssignedFileUID == 0 || !user.HasValidUsername() || len(user.Spec.AuthorizedKeys) == 0 {
+			continue
+		}
+		if err := user.ValidateAuthorizedKeys(); err != nil {
+			glog.Warningf("Not publishing authorized keys of %s: %s", user.Name, err)
+			continue
+		}
+		authorizedKeys := ""
+		for _, authorizedKey := range user.Spec.AuthorizedKeys {
+			authorizedKeys = authorizedKeys + strings.TrimSpace(authorizedKey) + "\n"
+		}
+		config.Data[AuthorizedKeysPrefix+user.GetUsername()] = authorizedKeys
 	}
 
 	if config.Annotations == nil {
@@ -130,6 +230,7 @@ func (c *EtcController) ProcessUpdate(key string) error {
 	}
 	config.Annotations["sitepod.io/mount-path"] = "/etc/sitepod/etc"
 	config.Labels["config-type"] = "etc"
+	config.Labels["sitepod"] = sitepodKey
 	config.Data["passwd"] = passwdOutput
 	config.Data["shadow"] = shadowOutput
 	config.Data["group"] = groupOutput
@@ -141,6 +242,21 @@ func (c *EtcController) ProcessUpdate(key string) error {
 	return nil
 }
 
+// shadowDate is days since the epoch as the shadow file expects, empty if unset
+func shadowDate(t *unversioned.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return strconv.FormatInt(t.Unix()/(24*60*60), 10)
+}
+
+func shadowDays(days *int) string {
+	if days == nil {
+		return ""
+	}
+	return strconv.Itoa(*days)
+}
+
 func processTemplate(path string, data []string) string {
 	template, err := template.ParseFiles("../../templates/" + path)
 	if err != nil {
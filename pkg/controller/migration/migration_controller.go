@@ -0,0 +1,270 @@
+package migration
+
+// SitepodMigrationController clones a sitepod into a new sitepod. Each stage is
+// recorded in status so it is resumed after a requeue:
+//  1. create the destination sitepod, and wait for its storage to be setup
+//  2. stream /home from the source pod into the destination pod via a podtask
+//  3. copy system users, app components and websites with the sitepod label
+//     rewritten to the destination sitepod UID and their status cleared, the copied
+//     users are assigned file UIDs of their own by the system user controller
+//  4. hand the transferred homes to the file UIDs of the copied users
+//  5. optionally delete the source sitepod (the sitepod controller cascades)
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/controller/framework"
+	"sitepod.io/sitepod/pkg/api/v1"
+	cc "sitepod.io/sitepod/pkg/client"
+	. "sitepod.io/sitepod/pkg/controller/shared"
+)
+
+type SitepodMigrationController struct {
+	SimpleController
+}
+
+func NewSitepodMigrationController(client *cc.Client) framework.ControllerInterface {
+
+	glog.Infof("Creating sitepod migration controller")
+	c := &SitepodMigrationController{*NewSimpleController("SitepodMigrationController", client,
+		[]Syncer{client.SitepodMigrations(), client.Sitepods(), client.SystemUsers(), client.AppComps(),
+			client.Websites(), client.Pods(), client.PodTasks()}, nil, nil)}
+	c.SyncFunc = c.ProcessUpdate
+	client.SitepodMigrations().AddInformerHandlers(framework.ResourceEventHandlerFuncs{
+		AddFunc:    c.QueueAdd,
+		UpdateFunc: c.QueueUpdate,
+	})
+	return c
+}
+
+func (c *SitepodMigrationController) QueueAdd(item interface{}) {
+	c.EnqueueUpdate(c.Client.SitepodMigrations().KeyOf(item))
+}
+
+func (c *SitepodMigrationController) QueueUpdate(old interface{}, cur interface{}) {
+	if !c.Client.SitepodMigrations().DeepEqual(old, cur) {
+		c.EnqueueUpdate(c.Client.SitepodMigrations().KeyOf(cur))
+	}
+}
+
+func (c *SitepodMigrationController) ProcessUpdate(key string) error {
+
+	migration, exists := c.Client.SitepodMigrations().MaybeGetByKey(key)
+
+	if !exists {
+		glog.Infof("Sitepod migration %s no longer exists", key)
+		return nil
+	}
+
+	if migration.Status.Completed {
+		glog.Infof("Sitepod migration %s already completed", key)
+		return nil
+	}
+
+	if len(migration.Status.SourceUID) == 0 {
+		source, exists := c.Client.Sitepods().MaybeGetByKey(migration.Spec.Source)
+		if !exists {
+			return DependentConfigNotValid{fmt.Sprintf("Source sitepod %s of migration %s does not exist",
+				migration.Spec.Source, key)}
+		}
+		// pin the source by UID from here on, names may be reused
+		migration.Status.SourceUID = string(source.UID)
+		c.Client.SitepodMigrations().Update(migration)
+		return nil
+	}
+
+	source, exists := c.Client.Sitepods().MaybeSingleByUID(migration.Status.SourceUID)
+	if !exists {
+		return DependentConfigNotValid{fmt.Sprintf("Source sitepod %s of migration %s has been deleted",
+			migration.Spec.Source, key)}
+	}
+
+	if len(migration.Status.DestinationUID) == 0 {
+		return c.createDestination(migration, source)
+	}
+
+	destination, exists := c.Client.Sitepods().MaybeSingleByUID(migration.Status.DestinationUID)
+	if !exists {
+		return ConditionsNotReady{fmt.Sprintf("Destination sitepod %s not yet available", migration.Spec.Destination)}
+	}
+
+	if migration.Spec.CopyData && !migration.Status.DataTransferred {
+		return c.transferData(migration, source, destination)
+	}
+
+	if !migration.Status.ResourcesCopied {
+		c.copyResources(migration.Status.SourceUID, migration.Status.DestinationUID, migration.Spec.CopyData)
+		migration.Status.ResourcesCopied = true
+		c.Client.SitepodMigrations().Update(migration)
+		return nil
+	}
+
+	if migration.Spec.CopyData && !migration.Status.OwnershipTransferred {
+		return c.transferOwnership(migration)
+	}
+
+	if migration.Spec.DeleteSource {
+		glog.Infof("Deleting source sitepod %s of migration %s", source.Name, key)
+		c.Client.Sitepods().Delete(source)
+	}
+
+	migration.Status.Completed = true
+	c.Client.SitepodMigrations().Update(migration)
+	glog.Infof("Completed sitepod migration %s", key)
+	return nil
+}
+
+func (c *SitepodMigrationController) createDestination(migration *v1.SitepodMigration, source *v1.Sitepod) error {
+
+	destination, exists := c.Client.Sitepods().MaybeGetByKey(migration.Spec.Destination)
+	if !exists {
+		destination = &v1.Sitepod{}
+		destination.Name = migration.Spec.Destination
+		destination.Labels = make(map[string]string)
+		destination.Annotations = make(map[string]string)
+		destination.Spec = source.Spec
+		if len(migration.Spec.VolumeClaims) > 0 {
+			destination.Spec.VolumeClaims = migration.Spec.VolumeClaims
+		}
+		destination.Annotations["sitepod.io/cloned-from"] = migration.Status.SourceUID
+		destination = c.Client.Sitepods().Add(destination)
+		glog.Infof("Created destination sitepod %s for migration %s", destination.Name, migration.Name)
+	} else if destination.Annotations["sitepod.io/cloned-from"] != migration.Status.SourceUID {
+		return DependentConfigNotValid{fmt.Sprintf("Destination sitepod %s already exists", destination.Name)}
+	}
+
+	migration.Status.DestinationUID = string(destination.UID)
+	c.Client.SitepodMigrations().Update(migration)
+	return nil
+}
+
+func (c *SitepodMigrationController) transferData(migration *v1.SitepodMigration, source *v1.Sitepod,
+	destination *v1.Sitepod) error {
+
+	if !destination.Status.StorageSetup {
+		return ConditionsNotReady{fmt.Sprintf("Destination sitepod %s storage not yet setup", destination.Name)}
+	}
+
+	sourcePod, exists := c.Client.Pods().MaybeSingleBySitepodKey(migration.Status.SourceUID)
+	if !exists || !IsPodReady(sourcePod) {
+		return ConditionsNotReady{fmt.Sprintf("Source sitepod %s pod not ready", source.Name)}
+	}
+
+	cmd := []string{"/bin/tar", "-xzpf", "-", "-C", "/home"}
+	return SubmitManagerPodTask(c.Client, migration.Status.DestinationUID, "SitepodMigration", migration.Name,
+		"DataTransferred", cmd, func(podTask *v1.Podtask) {
+			podTask.Spec.StdinFromPod = &v1.PodTaskSource{
+				PodName:       sourcePod.Name,
+				ContainerName: "sitepod-manager",
+				Command:       []string{"/bin/tar", "-czpf", "-", "-C", "/home", "."},
+			}
+		})
+}
+
+// transferOwnership chowns the home of each copied user to its own file UID once the
+// system user controller has assigned them, the copies never share a UID with the source
+func (c *SitepodMigrationController) transferOwnership(migration *v1.SitepodMigration) error {
+
+	// positional args so nothing from the users is interpreted by the shell
+	cmd := []string{"/bin/sh", "-c",
+		`while [ $# -gt 1 ]; do if [ -d "$2" ]; then chown -R -h "$1" "$2" || exit 1; fi; shift 2; done`, "sh"}
+
+	copies := c.Client.SystemUsers().BySitepodKey(migration.Status.DestinationUID)
+	cloned := make(map[string]bool)
+	for _, user := range copies {
+		cloned[user.Annotations["sitepod.io/cloned-from"]] = true
+	}
+	for _, user := range c.Client.SystemUsers().BySitepodKey(migration.Status.SourceUID) {
+		if !cloned[string(user.UID)] {
+			return ConditionsNotReady{fmt.Sprintf("Copy of system user %s not yet available", user.Name)}
+		}
+	}
+
+	for _, user := range copies {
+		if user.Status.AssignedFileUID == 0 {
+			return ConditionsNotReady{fmt.Sprintf("Copied system user %s has no file uid assigned yet", user.Name)}
+		}
+		if !user.HasValidUsername() {
+			glog.Warningf("Not transferring home of copied system user %s with invalid username", user.Name)
+			continue
+		}
+		cmd = append(cmd, fmt.Sprintf("%d:%d", user.Status.AssignedFileUID, 2000), user.GetHomeDirectory())
+	}
+
+	return SubmitManagerPodTask(c.Client, migration.Status.DestinationUID, "SitepodMigration", migration.Name,
+		"OwnershipTransferred", cmd, nil)
+}
+
+// copyResources clones the children of the source sitepod, they are keyed by the sitepod
+// label so it is rewritten to the destination. Copies already made (by a previous attempt)
+// are recognised by the sitepod.io/cloned-from annotation. Status is cleared so the
+// controllers provision the copies in the destination, only the document roots already
+// transferred with the data are not created again.
+func (c *SitepodMigrationController) copyResources(sourceKey string, destinationKey string, copyData bool) {
+
+	cloned := make(map[string]bool)
+	for _, user := range c.Client.SystemUsers().BySitepodKey(destinationKey) {
+		cloned[user.Annotations["sitepod.io/cloned-from"]] = true
+	}
+	for _, ac := range c.Client.AppComps().BySitepodKey(destinationKey) {
+		cloned[ac.Annotations["sitepod.io/cloned-from"]] = true
+	}
+	for _, website := range c.Client.Websites().BySitepodKey(destinationKey) {
+		cloned[website.Annotations["sitepod.io/cloned-from"]] = true
+	}
+
+	for _, user := range c.Client.SystemUsers().BySitepodKey(sourceKey) {
+		if cloned[string(user.UID)] {
+			continue
+		}
+		username := user.GetUsername()
+		sourceUID := string(user.UID)
+		resetObjectMeta(&user.ObjectMeta, sourceUID, destinationKey)
+		// the name is unique per namespace so keep the login via the spec
+		user.GenerateName = "systemuser-" + username + "-"
+		user.Spec.Username = username
+		// a file UID of its own is assigned, see transferOwnership
+		user.Status = v1.SystemUserStatus{}
+		c.Client.SystemUsers().Add(user)
+	}
+
+	for _, ac := range c.Client.AppComps().BySitepodKey(sourceKey) {
+		if cloned[string(ac.UID)] {
+			continue
+		}
+		sourceUID := string(ac.UID)
+		resetObjectMeta(&ac.ObjectMeta, sourceUID, destinationKey)
+		ac.GenerateName = "sitepod-appcomp-"
+		ac.Status = v1.AppComponentStatus{}
+		c.Client.AppComps().Add(ac)
+	}
+
+	for _, website := range c.Client.Websites().BySitepodKey(sourceKey) {
+		if cloned[string(website.UID)] {
+			continue
+		}
+		sourceUID := string(website.UID)
+		resetObjectMeta(&website.ObjectMeta, sourceUID, destinationKey)
+		website.GenerateName = "sitepod-website-"
+		website.Status = v1.WebsiteStatus{DirectoryCreated: copyData && website.Status.DirectoryCreated}
+		c.Client.Websites().Add(website)
+	}
+}
+
+func resetObjectMeta(om *v1.ObjectMeta, sourceUID string, destinationKey string) {
+	om.Name = ""
+	om.UID = ""
+	om.ResourceVersion = ""
+	om.SelfLink = ""
+	om.CreationTimestamp = unversioned.Time{}
+	if om.Labels == nil {
+		om.Labels = make(map[string]string)
+	}
+	if om.Annotations == nil {
+		om.Annotations = make(map[string]string)
+	}
+	om.Labels["sitepod"] = destinationKey
+	om.Annotations["sitepod.io/cloned-from"] = sourceUID
+}
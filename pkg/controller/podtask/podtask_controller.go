@@ -3,15 +3,14 @@ package podtask
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"time"
 
-	"k8s.io/kubernetes/pkg/client/unversioned/remotecommand"
-	remotecommandserver "k8s.io/kubernetes/pkg/kubelet/server/remotecommand"
-
 	"github.com/golang/glog"
-	k8s_api "k8s.io/kubernetes/pkg/api"
+	kerrors "k8s.io/kubernetes/pkg/api/errors"
 	"k8s.io/kubernetes/pkg/controller/framework"
-	//"sitepod.io/sitepod/pkg/api/v1"
+	"sitepod.io/sitepod/pkg/api/v1"
+	"sitepod.io/sitepod/pkg/backup"
 	cc "sitepod.io/sitepod/pkg/client"
 	. "sitepod.io/sitepod/pkg/controller/shared"
 )
@@ -24,7 +23,7 @@ func NewPodTaskController(client *cc.Client) framework.ControllerInterface {
 
 	glog.Infof("Creating podtask controller")
 	c := &PodTaskController{*NewSimpleController("PodTaskController", client, []Syncer{client.PodTasks(),
-		client.Pods()}, nil, nil)}
+		client.Pods(), client.Secrets()}, nil, nil)}
 
 	c.SyncFunc = c.ProcessUpdate
 
@@ -77,7 +76,13 @@ func (c *PodTaskController) ProcessUpdate(key string) error {
 		return nil
 	}
 
-	stdOut, stdErr, err := c.Execute(podTask.Spec.PodName, podTask.Spec.ContainerName, podTask.Spec.Command)
+	var stdOut, stdErr string
+	var err error
+	if podTask.Spec.StdinFrom != nil || podTask.Spec.StdoutTo != nil || podTask.Spec.StdinFromPod != nil {
+		stdOut, stdErr, err = c.executeStreamed(podTask)
+	} else {
+		stdOut, stdErr, err = c.Execute(podTask.Spec.PodName, podTask.Spec.ContainerName, podTask.Spec.Command)
+	}
 
 	podTask.Status.Attempts = podTask.Status.Attempts + 1
 	if err != nil {
@@ -104,6 +109,13 @@ func (c *PodTaskController) ProcessUpdate(key string) error {
 			behalfItem, err := c.Client.Sitepods().RestClient().Get().Resource(podTask.Spec.BehalfType + "s").
 				Namespace(podTask.Namespace).Name(podTask.Spec.BehalfOf).Do().Get()
 
+			if err != nil && kerrors.IsNotFound(err) {
+				// e.g. clean up on behalf of a deleted resource
+				glog.Infof("Behalf of %s resource %s:%s no longer exists", podTask.Spec.BehalfType,
+					podTask.Namespace, podTask.Spec.BehalfOf)
+				return nil
+			}
+
 			if err != nil || behalfItem == nil {
 				glog.Infof("Behalf of %s resource %s:%s unable to get (err: %+v)", podTask.Spec.BehalfType,
 					podTask.Namespace, podTask.Spec.BehalfOf, err)
@@ -133,42 +145,83 @@ func (c *PodTaskController) ProcessUpdate(key string) error {
 
 }
 
-func (c *PodTaskController) Execute(podName string, containerName string, command []string) (string, string, error) {
+// executeStreamed runs a podtask with stdin and/or stdout attached to backup
+// target objects, or stdin piped from a command on another pod, rather than
+// in memory buffers
+func (c *PodTaskController) executeStreamed(podTask *v1.Podtask) (string, string, error) {
+
+	var stdin io.Reader
+	if source := podTask.Spec.StdinFromPod; source != nil {
+		pr, pw := io.Pipe()
+		sourceStderr := bytes.NewBuffer([]byte{})
+		go func() {
+			err := c.ExecuteStream(source.PodName, source.ContainerName, source.Command, nil, pw, sourceStderr)
+			if err != nil {
+				glog.Errorf("PodTask %s source %s failed: %+v %s", podTask.Name, source.PodName, err, sourceStderr.String())
+			}
+			pw.CloseWithError(err)
+		}()
+		// stop the source if we give up reading early
+		defer pr.Close()
+		stdin = pr
+	} else if from := podTask.Spec.StdinFrom; from != nil {
+		target, err := backup.Open(c.Client, from.Target)
+		if err != nil {
+			return "", "", err
+		}
+		reader, err := target.Get(from.Object)
+		if err != nil {
+			return "", "", err
+		}
+		defer reader.Close()
+		stdin = reader
+	}
 
-	glog.Infof("Exsecuting")
-	req := c.Client.Pods().RestClient().Post().
-		Resource("pods").
-		Name(podName).
-		Namespace("default"). //TODO inject
-		SubResource("exec").
-		Param("container", containerName)
-
-	req.VersionedParams(&k8s_api.PodExecOptions{
-		Container: containerName,
-		Command:   command,
-		Stdin:     false,
-		Stdout:    true,
-		Stderr:    true,
-		TTY:       false,
-	}, k8s_api.ParameterCodec)
-
-	exec, err := remotecommand.NewExecutor(c.Client.Pods().RestClientConfig(), "POST", req.URL())
+	stderr := bytes.NewBuffer([]byte{})
+
+	to := podTask.Spec.StdoutTo
+	if to == nil {
+		stdout := bytes.NewBuffer([]byte{})
+		err := c.ExecuteStream(podTask.Spec.PodName, podTask.Spec.ContainerName, podTask.Spec.Command,
+			stdin, stdout, stderr)
+		return stdout.String(), stderr.String(), err
+	}
 
+	target, err := backup.Open(c.Client, to.Target)
 	if err != nil {
 		return "", "", err
 	}
 
+	pr, pw := io.Pipe()
+	putErr := make(chan error, 1)
+	go func() {
+		err := target.Put(to.Object, pr)
+		// unblock the exec stream if the target gave up early
+		pr.CloseWithError(err)
+		putErr <- err
+	}()
+
+	err = c.ExecuteStream(podTask.Spec.PodName, podTask.Spec.ContainerName, podTask.Spec.Command,
+		stdin, pw, stderr)
+	if err != nil {
+		pw.CloseWithError(err)
+	} else {
+		pw.Close()
+	}
+
+	if perr := <-putErr; err == nil {
+		err = perr
+	}
+
+	return fmt.Sprintf("streamed to %s", to.Object), stderr.String(), err
+}
+
+func (c *PodTaskController) Execute(podName string, containerName string, command []string) (string, string, error) {
+
 	stdout := bytes.NewBuffer([]byte{})
 	stderr := bytes.NewBuffer([]byte{})
 
-	err = exec.Stream(remotecommand.StreamOptions{
-		SupportedProtocols: remotecommandserver.SupportedStreamingProtocols,
-		Stdin:              nil,
-		Stdout:             stdout,
-		Stderr:             stderr,
-		Tty:                false,
-		TerminalSizeQueue:  nil,
-	})
+	err := c.ExecuteStream(podName, containerName, command, nil, stdout, stderr)
 
 	if err != nil {
 		return "", "", err
@@ -176,3 +229,10 @@ func (c *PodTaskController) Execute(podName string, containerName string, comman
 
 	return stdout.String(), stderr.String(), err
 }
+
+func (c *PodTaskController) ExecuteStream(podName string, containerName string, command []string,
+	stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+
+	glog.Infof("Exsecuting")
+	return c.Client.Exec(podName, containerName, command, stdin, stdout, stderr)
+}
@@ -0,0 +1,48 @@
+package shared
+
+import (
+	"github.com/golang/glog"
+	"sitepod.io/sitepod/pkg/api/v1"
+	cc "sitepod.io/sitepod/pkg/client"
+)
+
+// SubmitManagerPodTask creates a podtask on the sitepod-manager container of the sitepod on
+// behalf of the named resource, unless one is already outstanding for it on the current pod.
+// The podtask may be customized (e.g. attaching streams) before it is added.
+func SubmitManagerPodTask(client *cc.Client, sitepodKey string, behalfType string, behalfOf string,
+	behalfCondition string, cmd []string, customize func(*v1.Podtask)) error {
+
+	pod, exists := client.Pods().MaybeSingleBySitepodKey(sitepodKey)
+	if !exists {
+		return ConditionsNotReady{"Still provisioning pod"}
+	}
+
+	for _, podTask := range client.PodTasks().BySitepodKey(sitepodKey) {
+		if podTask.Spec.BehalfType == behalfType && podTask.Spec.BehalfOf == behalfOf &&
+			podTask.Spec.BehalfCondition == behalfCondition && podTask.Spec.PodName == pod.Name {
+			glog.Infof("Existing podtask for %s %s found", behalfType, behalfOf)
+			return nil
+		}
+	}
+
+	if !IsPodReady(pod) {
+		return ConditionsNotReady{"Pod not in ready state"}
+	}
+
+	podTask := client.PodTasks().NewEmpty()
+	podTask.Labels["sitepod"] = sitepodKey
+	podTask.Spec.Command = cmd
+	podTask.Spec.PodName = pod.GetName()
+	podTask.Spec.ContainerName = "sitepod-manager"
+	podTask.Spec.Namespace = pod.GetNamespace()
+	podTask.Spec.BehalfType = behalfType
+	podTask.Spec.BehalfOf = behalfOf
+	podTask.Spec.BehalfCondition = behalfCondition
+	if customize != nil {
+		customize(podTask)
+	}
+	client.PodTasks().Add(podTask)
+	glog.Infof("Created podtask for %s %s", behalfType, behalfOf)
+
+	return nil
+}
@@ -1 +1,15 @@
 package shared
+
+import (
+	k8s_api "k8s.io/kubernetes/pkg/api"
+)
+
+// IsPodReady reports whether the pod has a true Ready condition
+func IsPodReady(pod *k8s_api.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == k8s_api.PodReady && condition.Status == k8s_api.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package systemgroup
+
+// SystemGroupController assigns GIDs to system groups and releases them on deletion,
+// the etc controller renders the groups into each sitepod's etc group file
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/golang/glog"
+	kerrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/client/cache"
+	"k8s.io/kubernetes/pkg/controller/framework"
+	"sitepod.io/sitepod/pkg/allocator"
+	"sitepod.io/sitepod/pkg/api/v1"
+	cc "sitepod.io/sitepod/pkg/client"
+	. "sitepod.io/sitepod/pkg/controller/shared"
+)
+
+type SystemGroupController struct {
+	SimpleController
+	fileUIDs *allocator.FileUIDAllocator
+	// deleted groups are gone from the store by the time the delete is processed
+	deleted      map[string]*v1.SystemGroup
+	deletedMutex sync.Mutex
+}
+
+func NewSystemGroupController(client *cc.Client) framework.ControllerInterface {
+
+	glog.Info("Creating system group controller")
+	sc := &SystemGroupController{*NewSimpleController("SystemGroupController", client,
+		[]Syncer{client.Sitepods(), client.SystemGroups(), client.Clusters()}, nil, nil),
+		allocator.NewFileUIDAllocator(client, client.ClusterName()), make(map[string]*v1.SystemGroup), sync.Mutex{}}
+	sc.SyncFunc = sc.ProcessUpdate
+	sc.DeleteFunc = sc.ProcessDelete
+	client.SystemGroups().AddInformerHandlers(framework.ResourceEventHandlerFuncs{
+		AddFunc:    sc.QueueAdd,
+		UpdateFunc: sc.QueueUpdate,
+		DeleteFunc: sc.QueueDelete,
+	})
+	return sc
+}
+
+func (c *SystemGroupController) QueueAdd(item interface{}) {
+	c.EnqueueUpdate(c.Client.SystemGroups().KeyOf(item))
+}
+
+func (c *SystemGroupController) QueueUpdate(old interface{}, cur interface{}) {
+	if !c.Client.SystemGroups().DeepEqual(old, cur) {
+		c.QueueAdd(cur)
+	}
+}
+
+func (c *SystemGroupController) QueueDelete(deleted interface{}) {
+	if tombstone, ok := deleted.(cache.DeletedFinalStateUnknown); ok {
+		deleted = tombstone.Obj
+	}
+
+	group, ok := deleted.(*v1.SystemGroup)
+	if !ok {
+		glog.Warningf("Unexpected deleted object %+v", deleted)
+		return
+	}
+
+	key := c.Client.SystemGroups().KeyOf(group)
+	c.deletedMutex.Lock()
+	c.deleted[key] = group
+	c.deletedMutex.Unlock()
+	c.EnqueueDelete(key)
+}
+
+func (c *SystemGroupController) ProcessUpdate(key string) error {
+
+	group, exists := c.Client.SystemGroups().MaybeGetByKey(key)
+
+	if !exists {
+		glog.Infof("Group %s no longer exists", key)
+		return nil
+	}
+
+	sitepodKey := group.Labels["sitepod"]
+	_, exists = c.Client.Sitepods().MaybeSingleByUID(sitepodKey)
+	if !exists {
+		glog.Infof("Sitepod %s no longer exists, skipping group %s", sitepodKey, group.Name)
+		return nil
+	}
+
+	if !group.HasValidGroupname() {
+		return DependentConfigNotValid{fmt.Sprintf("Group %s has invalid groupname %s", key, group.GetGroupname())}
+	}
+
+	for _, other := range c.Client.SystemGroups().BySitepodKey(sitepodKey) {
+		if other.Name != group.Name && other.GetGroupname() == group.GetGroupname() {
+			return DependentConfigNotValid{fmt.Sprintf("Group %s duplicates groupname %s of %s",
+				key, group.GetGroupname(), other.Name)}
+		}
+	}
+
+	if group.Status.AssignedGID != 0 {
+		return nil
+	}
+
+	gids, err := c.fileUIDs.Allocate(1)
+	if err != nil {
+		return ConditionsNotReady{fmt.Sprintf("Unable to allocate gid for %s: %s", key, err)}
+	}
+
+	group.Status.AssignedGID = gids[0]
+	_, err = c.Client.SystemGroups().TryUpdate(group)
+	if err != nil {
+		// never assigned so can be reused straight away
+		if releaseErr := c.fileUIDs.Release(gids[0], false); releaseErr != nil {
+			glog.Errorf("Leaked gid %d: %+v", gids[0], releaseErr)
+		}
+		if kerrors.IsConflict(err) {
+			return ConditionsNotReady{fmt.Sprintf("Conflict assigning gid to %s", key)}
+		}
+		return err
+	}
+
+	glog.Infof("Assigned gid %d to group %s", gids[0], key)
+	return nil
+}
+
+// ProcessDelete releases the GID of a deleted group. Files on disk may still carry the
+// GID so it is quarantined like a file UID.
+func (c *SystemGroupController) ProcessDelete(key string) error {
+
+	c.deletedMutex.Lock()
+	group := c.deleted[key]
+	c.deletedMutex.Unlock()
+
+	if group == nil {
+		glog.Warningf("No record of deleted group %s, skipping clean up", key)
+		return nil
+	}
+
+	if gid := group.Status.AssignedGID; gid != 0 {
+		if err := c.fileUIDs.Release(gid, true); err != nil {
+			return ConditionsNotReady{fmt.Sprintf("Unable to release gid %d of %s: %s", gid, key, err)}
+		}
+	}
+
+	c.deletedMutex.Lock()
+	delete(c.deleted, key)
+	c.deletedMutex.Unlock()
+
+	glog.Infof("Cleaned up deleted group %s", key)
+	return nil
+}
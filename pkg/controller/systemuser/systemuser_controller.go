@@ -1,25 +1,37 @@
 package systemuser
 
 import (
+	"fmt"
 	. "github.com/ahmetalpbalkan/go-linq"
 	"github.com/golang/glog"
 	k8s_api "k8s.io/kubernetes/pkg/api"
+	kerrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/client/cache"
 	"k8s.io/kubernetes/pkg/controller/framework"
 	"reflect"
+	"sitepod.io/sitepod/pkg/allocator"
+	"sitepod.io/sitepod/pkg/api/v1"
 	cc "sitepod.io/sitepod/pkg/client"
 	. "sitepod.io/sitepod/pkg/controller/shared"
+	"sync"
 )
 
 type SystemUserController struct {
 	SimpleController
+	fileUIDs *allocator.FileUIDAllocator
+	// deleted users are gone from the store by the time the delete is processed
+	deleted      map[string]*v1.SystemUser
+	deletedMutex sync.Mutex
 }
 
 func NewSystemUserController(client *cc.Client) framework.ControllerInterface {
 
 	glog.Info("Creating system user controller")
 	sc := &SystemUserController{*NewSimpleController("SystemUserController", client, []Syncer{client.PVClaims(),
-		client.PVs(), client.Sitepods(), client.SystemUsers()}, nil, nil)}
+		client.PVs(), client.Sitepods(), client.SystemUsers(), client.Clusters()}, nil, nil),
+		allocator.NewFileUIDAllocator(client, client.ClusterName()), make(map[string]*v1.SystemUser), sync.Mutex{}}
 	sc.SyncFunc = sc.ProcessUpdate
+	sc.DeleteFunc = sc.ProcessDelete
 	client.SystemUsers().AddInformerHandlers(framework.ResourceEventHandlerFuncs{
 		AddFunc:    sc.QueueAdd,
 		UpdateFunc: sc.QueueUpdate,
@@ -28,6 +40,26 @@ func NewSystemUserController(client *cc.Client) framework.ControllerInterface {
 	return sc
 }
 
+func (c *SystemUserController) Run(stopCh <-chan struct{}) {
+	c.WaitReady()
+	c.checkFileUIDs()
+	c.SimpleController.Run(stopCh)
+}
+
+// checkFileUIDs reports file UIDs shared by more than one user and repairs the cluster
+// allocation state from the users
+func (c *SystemUserController) checkFileUIDs() {
+
+	duplicates, err := c.fileUIDs.Reconcile(c.Client.SystemUsers().List())
+	if err != nil {
+		glog.Errorf("Unable to reconcile file uids: %+v", err)
+	}
+
+	for uid, users := range duplicates {
+		glog.Errorf("File uid %d is assigned to more than one system user: %v", uid, users)
+	}
+}
+
 func (c *SystemUserController) QueueAdd(item interface{}) {
 	c.EnqueueUpdate(c.Client.SystemUsers().KeyOf(item))
 }
@@ -39,7 +71,21 @@ func (c *SystemUserController) QueueUpdate(old interface{}, cur interface{}) {
 }
 
 func (c *SystemUserController) QueueDelete(deleted interface{}) {
-	c.EnqueueDelete(c.Client.SystemUsers().KeyOf(deleted))
+	if tombstone, ok := deleted.(cache.DeletedFinalStateUnknown); ok {
+		deleted = tombstone.Obj
+	}
+
+	user, ok := deleted.(*v1.SystemUser)
+	if !ok {
+		glog.Warningf("Unexpected deleted object %+v", deleted)
+		return
+	}
+
+	key := c.Client.SystemUsers().KeyOf(user)
+	c.deletedMutex.Lock()
+	c.deleted[key] = user
+	c.deletedMutex.Unlock()
+	c.EnqueueDelete(key)
 }
 
 func (c *SystemUserController) ProcessUpdate(key string) error {
@@ -61,21 +107,39 @@ func (c *SystemUserController) ProcessUpdate(key string) error {
 		return nil
 	}
 
-	if user.Status.AssignedFileUID == 0 {
+	if !user.HasValidUsername() {
+		return DependentConfigNotValid{fmt.Sprintf("User %s has an invalid or reserved username %s", key, user.GetUsername())}
+	}
+
+	if err := user.ValidateAuthorizedKeys(); err != nil {
+		// keys are only published by the etc controller once valid
+		return DependentConfigNotValid{err.Error()}
+	}
 
-		cluster, exists := c.Client.Clusters().MaybeGetByKey("sitepod-alpha")
+	if err := user.ValidateAccess(); err != nil {
+		return DependentConfigNotValid{err.Error()}
+	}
 
-		if !exists {
-			//TODO pass name
-			cluster = c.Client.Clusters().NewEmpty()
-			cluster.Name = "sitepod-alpha"
-		}
+	if user.Status.AssignedFileUID == 0 {
 
-		assignedFileUID := cluster.NextFileUID()
-		c.Client.Clusters().UpdateOrAdd(cluster)
+		uids, err := c.fileUIDs.Allocate(1)
+		if err != nil {
+			return ConditionsNotReady{fmt.Sprintf("Unable to allocate file uid for %s: %s", key, err)}
+		}
 
-		user.Status.AssignedFileUID = assignedFileUID
-		c.Client.SystemUsers().Update(user)
+		user.Status.AssignedFileUID = uids[0]
+		updated, err := c.Client.SystemUsers().TryUpdate(user)
+		if err != nil {
+			// never assigned so can be reused straight away
+			if releaseErr := c.fileUIDs.Release(uids[0], false); releaseErr != nil {
+				glog.Errorf("Leaked file uid %d: %+v", uids[0], releaseErr)
+			}
+			if kerrors.IsConflict(err) {
+				return ConditionsNotReady{fmt.Sprintf("Conflict assigning file uid to %s", key)}
+			}
+			return err
+		}
+		user = updated
 	}
 
 	if !user.Status.HomeProvisioned {
@@ -132,11 +196,69 @@ func (c *SystemUserController) ProcessUpdate(key string) error {
 
 }
 
-func (c *SystemUserController) ProcessDelete(key string) {
+// ProcessDelete cleans up the home directory of a deleted user according to its home
+// deletion policy and releases its file UID once no files are left owned by it: an
+// archived home is handed to root, a retained home keeps its UID allocated. The etc
+// controller regenerates passwd and shadow without the user on the same delete event.
+func (c *SystemUserController) ProcessDelete(key string) error {
+
+	c.deletedMutex.Lock()
+	user := c.deleted[key]
+	c.deletedMutex.Unlock()
+
+	if user == nil {
+		glog.Warningf("No record of deleted user %s, skipping clean up", key)
+		return nil
+	}
 
-	// Determine sitepod belong to
-	// Check associated PV
-	// Delete user owned directories
+	sitepodKey := user.Labels["sitepod"]
+	_, sitepodExists := c.Client.Sitepods().MaybeSingleByUID(sitepodKey)
+
+	policy := user.GetHomeDeletionPolicy()
+	releaseUID := true
+	if !sitepodExists {
+		// the home volume went with the sitepod
+		glog.Infof("Sitepod %s of deleted user %s no longer exists, no home to clean up", sitepodKey, key)
+	} else if policy == v1.HomeRetain {
+		glog.Infof("Retaining home of deleted user %s, file uid %d stays allocated", key, user.Status.AssignedFileUID)
+		releaseUID = false
+	} else {
+
+		if !user.HasValidUsername() {
+			return DependentConfigNotValid{fmt.Sprintf("Refusing to clean up home of user %s with invalid username", key)}
+		}
 
-	// Leave
+		var cmd []string
+		switch policy {
+		case v1.HomeRemove:
+			cmd = []string{"/bin/rm", "-rf", "--", user.GetHomeDirectory()}
+		case v1.HomeArchive:
+			// positional args so nothing from the user is interpreted by the shell, the
+			// home is handed to root before the move so a retry never skips it
+			archived := fmt.Sprintf("%s-%d", user.GetUsername(), user.Status.AssignedFileUID)
+			cmd = []string{"/bin/sh", "-c", `mkdir -p "$1" && if [ -d "$2" ]; then chown -R -h 0:0 "$2" && mv "$2" "$1/$3"; fi`,
+				"sh", "/home/.archived", user.GetHomeDirectory(), archived}
+		default:
+			return DependentConfigNotValid{fmt.Sprintf("Unknown home deletion policy %s for user %s", policy, key)}
+		}
+
+		glog.Infof("Cleaning up home of deleted user %s with policy %s", key, policy)
+		if err := SubmitManagerPodTask(c.Client, sitepodKey, "SystemUser", user.Name, "HomeRemoved", cmd, nil); err != nil {
+			return err
+		}
+	}
+
+	if uid := user.Status.AssignedFileUID; uid != 0 && releaseUID {
+		// processes of the user may still be running, hence the quarantine
+		if err := c.fileUIDs.Release(uid, true); err != nil {
+			return ConditionsNotReady{fmt.Sprintf("Unable to release file uid %d of %s: %s", uid, key, err)}
+		}
+	}
+
+	c.deletedMutex.Lock()
+	delete(c.deleted, key)
+	c.deletedMutex.Unlock()
+
+	glog.Infof("Cleaned up deleted user %s", key)
+	return nil
 }
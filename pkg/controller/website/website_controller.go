@@ -2,6 +2,7 @@ package website
 
 import (
 	"bytes"
+	"fmt"
 	. "github.com/ahmetalpbalkan/go-linq"
 	"github.com/golang/glog"
 	k8s_api "k8s.io/kubernetes/pkg/api"
@@ -12,6 +13,7 @@ import (
 	"sitepod.io/sitepod/pkg/api/v1"
 	cc "sitepod.io/sitepod/pkg/client"
 	. "sitepod.io/sitepod/pkg/controller/shared"
+	"strconv"
 	"text/template"
 )
 
@@ -23,13 +25,17 @@ func NewWebsiteController(client *cc.Client) framework.ControllerInterface {
 
 	glog.Infof("Creating website controller")
 	sc := &WebsiteController{*NewSimpleController("WebsiteController", client,
-		[]Syncer{client.ConfigMaps(), client.Sitepods()}, nil, nil)}
+		[]Syncer{client.ConfigMaps(), client.Sitepods(), client.SystemGroups()}, nil, nil)}
 	sc.SyncFunc = sc.ProcessUpdate
 	client.Websites().AddInformerHandlers(framework.ResourceEventHandlerFuncs{
 		AddFunc:    sc.QueueAdd,
 		UpdateFunc: sc.QueueUpdate,
 		//DeleteFunc: sc.QueueDelete,
 	})
+	client.SystemGroups().AddInformerHandlers(framework.ResourceEventHandlerFuncs{
+		AddFunc:    sc.QueueGroup,
+		UpdateFunc: func(old interface{}, cur interface{}) { sc.QueueGroup(cur) },
+	})
 	return sc
 }
 
@@ -45,6 +51,19 @@ func (c *WebsiteController) QueueUpdate(old interface{}, cur interface{}) {
 	c.QueueAdd(cur)
 }
 
+// QueueGroup queues the websites owned by a group, e.g. once it has been assigned a gid
+func (c *WebsiteController) QueueGroup(item interface{}) {
+	group, ok := item.(*v1.SystemGroup)
+	if !ok {
+		return
+	}
+	for _, website := range c.Client.Websites().BySitepodKey(group.Labels["sitepod"]) {
+		if len(website.Spec.Group) > 0 && website.Spec.Group == group.GetGroupname() {
+			c.QueueAdd(website)
+		}
+	}
+}
+
 func (c *WebsiteController) QueueDelete(deleted interface{}) {
 	c.EnqueueDelete(c.Client.Websites().KeyOf(deleted))
 	accessor, err := meta.Accessor(deleted)
@@ -65,6 +84,17 @@ func (c *WebsiteController) ProcessUpdate(key string) error {
 		return nil
 	}
 
+	if !website.HasValidDomain() {
+		return DependentConfigNotValid{fmt.Sprintf("Refusing website %s with invalid domain %q", key, website.GetPrimaryDomain())}
+	}
+
+	if website.Status.DirectoryCreated && len(website.Spec.Group) > 0 {
+		if err := c.ApplyGroup(website); err != nil {
+			glog.Errorf("Error applying group to website %s: %+v", key, err)
+			return err
+		}
+	}
+
 	alreadySetup := false
 	var err error
 	if !website.Status.DirectoryCreated {
@@ -99,7 +129,11 @@ func (c *WebsiteController) CreateDirectory(website *v1.Website) error {
 	sitepodKey := website.Labels["sitepod"]
 	podTasks := c.Client.PodTasks().ByIndexByKey("sitepod", sitepodKey)
 
-	cmd := []string{"/bin/mkdir" /* "-p", */, "/home/sitepod/websites/" + website.GetPrimaryDomain()}
+	documentRoot, err := website.GetDocumentRoot()
+	if err != nil {
+		return DependentConfigNotValid{err.Error()}
+	}
+	cmd := []string{"/bin/mkdir" /* "-p", */, documentRoot}
 
 	podTaskExists := false
 	podTaskExistingPod := ""
@@ -148,6 +182,64 @@ func (c *WebsiteController) CreateDirectory(website *v1.Website) error {
 	return nil
 }
 
+// ApplyGroup hands the document root to the owning group, group writable and setgid on
+// directories so new files stay with the group. The command carries the gid so a change
+// of group (or gid) results in a new pod task.
+func (c *WebsiteController) ApplyGroup(website *v1.Website) error {
+
+	sitepodKey := website.Labels["sitepod"]
+
+	var group *v1.SystemGroup
+	for _, candidate := range c.Client.SystemGroups().BySitepodKey(sitepodKey) {
+		if candidate.GetGroupname() == website.Spec.Group {
+			group = candidate
+			break
+		}
+	}
+
+	if group == nil {
+		return DependentResourcesNotReady{fmt.Sprintf("Group %s of website %s does not exist", website.Spec.Group, website.Name)}
+	}
+
+	if group.Status.AssignedGID == 0 {
+		return ConditionsNotReady{fmt.Sprintf("Group %s has no gid assigned yet", website.Spec.Group)}
+	}
+
+	documentRoot, err := website.GetDocumentRoot()
+	if err != nil {
+		return DependentConfigNotValid{err.Error()}
+	}
+
+	cmd := []string{"/bin/sh", "-c",
+		`chgrp -R "$1" "$2" && chmod -R g+rwX "$2" && find "$2" -type d -exec chmod g+s {} +`,
+		"sh", strconv.Itoa(group.Status.AssignedGID), documentRoot}
+
+	pod, exists := c.Client.Pods().MaybeSingleBySitepodKey(sitepodKey)
+	if !exists {
+		return ConditionsNotReady{"Still provisioning pod"}
+	}
+
+	for _, podTask := range c.Client.PodTasks().BySitepodKey(sitepodKey) {
+		if reflect.DeepEqual(podTask.Spec.Command, cmd) && podTask.Spec.PodName == pod.Name {
+			return nil
+		}
+	}
+
+	if !IsPodReady(pod) {
+		return ConditionsNotReady{"Pod not in ready state"}
+	}
+
+	glog.Infof("Applying group %s (gid %d) to website %s", group.GetGroupname(), group.Status.AssignedGID, website.Name)
+	podTask := c.Client.PodTasks().NewEmpty()
+	podTask.Labels["sitepod"] = sitepodKey
+	podTask.Spec.Command = cmd
+	podTask.Spec.PodName = pod.GetName()
+	podTask.Spec.ContainerName = "sitepod-manager"
+	podTask.Spec.Namespace = pod.GetNamespace()
+	c.Client.PodTasks().Add(podTask)
+	return nil
+}
+
 func (c *WebsiteController) SkeltonSetup(website *v1.Website) error {
 	website.Status.SkeltonSetup = true
 	return nil
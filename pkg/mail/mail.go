@@ -0,0 +1,142 @@
+package mail
+
+// Outbound mail of the control panel. Mailer is implemented by SMTP delivery and, for
+// development and tests, by sinks writing messages to files or the log.
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"sitepod.io/sitepod/pkg/util"
+)
+
+// Message is a plain text mail to one recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(msg *Message) error
+}
+
+// Format renders the message as RFC 5322 with a utf-8 text body
+func (m *Message) Format(from string, now time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", m.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(util.RandomBytes(16)), domainOf(from))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	buf.WriteString(strings.Replace(strings.Replace(m.Body, "\r\n", "\n", -1), "\n", "\r\n", -1))
+	return buf.Bytes()
+}
+
+func domainOf(address string) string {
+	address = strings.TrimRight(address, ">")
+	if at := strings.LastIndex(address, "@"); at >= 0 {
+		return address[at+1:]
+	}
+	return "localhost"
+}
+
+// validRecipient refuses addresses that could inject headers
+func validRecipient(to string) error {
+	if len(to) == 0 || strings.ContainsAny(to, "\r\n<>,") || !strings.Contains(to, "@") {
+		return fmt.Errorf("Invalid recipient %q", to)
+	}
+	return nil
+}
+
+// SMTPMailer delivers through a submission server, using STARTTLS when offered and
+// PLAIN auth when a username is set
+type SMTPMailer struct {
+	Address  string
+	From     string
+	Username string
+	Password string
+}
+
+func NewSMTPMailer(address string, from string, username string, password string) *SMTPMailer {
+	return &SMTPMailer{Address: address, From: from, Username: username, Password: password}
+}
+
+func (m *SMTPMailer) Send(msg *Message) error {
+
+	if err := validRecipient(msg.To); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if len(m.Username) > 0 {
+		host, _, err := net.SplitHostPort(m.Address)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", m.Username, m.Password, host)
+	}
+
+	return smtp.SendMail(m.Address, auth, m.From, []string{msg.To}, msg.Format(m.From, time.Now()))
+}
+
+// FileMailer writes each message to a .eml file in Dir
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+func NewFileMailer(dir string, from string) *FileMailer {
+	return &FileMailer{Dir: dir, From: from}
+}
+
+func (m *FileMailer) Send(msg *Message) error {
+
+	if err := validRecipient(msg.To); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.Dir, 0700); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405"), hex.EncodeToString(util.RandomBytes(4)))
+	path := filepath.Join(m.Dir, name)
+	if err := ioutil.WriteFile(path, msg.Format(m.From, now), 0600); err != nil {
+		return err
+	}
+	glog.Infof("Wrote mail to %s as %s", msg.To, path)
+	return nil
+}
+
+// LogMailer logs messages instead of sending them, including their body, so it is only
+// for development
+type LogMailer struct {
+	From string
+}
+
+func NewLogMailer(from string) *LogMailer {
+	return &LogMailer{From: from}
+}
+
+func (m *LogMailer) Send(msg *Message) error {
+	if err := validRecipient(msg.To); err != nil {
+		return err
+	}
+	glog.Infof("Mail to %s from %s: %s\n%s", msg.To, m.From, msg.Subject, msg.Body)
+	return nil
+}
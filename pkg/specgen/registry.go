@@ -1,5 +1,11 @@
 package specgen
 
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
 type SpecFn func(obj interface{}) error
 
 var specMap map[string]SpecFn
@@ -11,6 +17,65 @@ func init() {
 	specMap["php-fpm"] = SpecGenPHPFPM
 }
 
+// ComponentType is what an app component of a type runs. Web api callers may only pick
+// one of its image versions, the first is the default.
+type ComponentType struct {
+	Image    string
+	Versions []string
+	// ExposeExternally applies to components created through the web api, callers can't
+	// change it. php-fpm is reached from the web server within the pod.
+	ExposeExternally bool
+}
+
+var componentTypes = map[string]ComponentType{
+	"webserver": {Image: "sitepod/nginx", Versions: []string{"latest"}, ExposeExternally: true},
+	"phpfpm":    {Image: "sitepod/phpfpm", Versions: []string{"latest"}, ExposeExternally: false},
+	"ssh":       {Image: "sitepod/sshdftp", Versions: []string{"latest"}, ExposeExternally: true},
+}
+
+func RegisterComponentType(name string, componentType ComponentType) {
+	componentTypes[name] = componentType
+}
+
+func LookupComponentType(name string) (ComponentType, bool) {
+	componentType, exists := componentTypes[name]
+	return componentType, exists
+}
+
+// HasVersion is whether version is one of the image versions of the type
+func (t ComponentType) HasVersion(version string) bool {
+	for _, v := range t.Versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// IsGeneratedConfigFile is true for config files the app component controller maintains,
+// these are not given by users
+func IsGeneratedConfigFile(name string) bool {
+	return name == SSHDConfigName
+}
+
+// ConfigDirectoryPrefix is where user config files may be mounted, images read their
+// config from below it
+const ConfigDirectoryPrefix = "/etc/sitepod/"
+
+// ValidateConfigDirectory checks a user config file directory is a clean absolute path
+// below ConfigDirectoryPrefix and clear of the directories the controller mounts
+func ValidateConfigDirectory(directory string) error {
+	if path.Clean(directory) != directory || !strings.HasPrefix(directory, ConfigDirectoryPrefix) {
+		return fmt.Errorf("directory %q must be a clean path below %s", directory, ConfigDirectoryPrefix)
+	}
+	for _, reserved := range []string{SSHConfigDirectory, SSHHostKeyDirectory} {
+		if directory == reserved || strings.HasPrefix(directory, reserved+"/") {
+			return fmt.Errorf("directory %s is reserved", directory)
+		}
+	}
+	return nil
+}
+
 func RegisterSpecGen(key string, fn SpecFn) {
 	specMap[key] = fn
 }
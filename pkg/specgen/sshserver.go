@@ -2,10 +2,12 @@ package specgen
 
 import (
 	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
-	"crypto/x509"
-	"encoding/base64"
 	"encoding/pem"
 	"fmt"
 	"golang.org/x/crypto/ssh"
@@ -29,84 +31,169 @@ func SpecGenSSHServer(obj interface{}) error {
 	ac.Spec.Expose = true
 	ac.Spec.ExposePort = 22
 	ac.Spec.ExposeExternally = true
-
-	privateKey, publicKey := genNewKeys()
-	privateKeyPemFile := v1.AppComponentConfigFile{
-		Name:      "sshprivate",
-		Content:   privateKey,
-		Filename:  "ssh_host_rsa_key",
-		Directory: "/etc/sitepod/ssh",
-		FileMode:  "0600",
-		Uid:       0,
-		Gid:       0,
-	}
-
-	publicKeySSHFile := v1.AppComponentConfigFile{
-		Name:      "sshpublic",
-		Content:   publicKey,
-		Filename:  "ssh_host_rsa_key.pub",
-		Directory: "/etc/sitepod/ssh",
-		FileMode:  "0600",
-		Uid:       0,
-		Gid:       0,
+	if ac.Spec.SSH == nil {
+		ac.Spec.SSH = &v1.SSHOptions{
+			PasswordAuthentication:  true,
+			PublicKeyAuthentication: true,
+		}
 	}
 
-	sshdConfigContent := generateSSHDConfig()
+	// host keys are generated into a secret by the app component controller rather than
+	// kept in the spec, users are filled in by the controller as they come and go
+	sshdConfigContent := GenerateSSHDConfig(*ac.Spec.SSH, nil)
 	sshdConfigFile := v1.AppComponentConfigFile{
-		Name:      "sshdconfig",
+		Name:      SSHDConfigName,
 		Content:   sshdConfigContent,
 		Filename:  "sshd_config",
-		Directory: "/etc/sitepod/ssh",
+		Directory: SSHConfigDirectory,
 		FileMode:  "0600",
 		Uid:       0,
 		Gid:       0,
 	}
 
-	ac.Spec.ConfigFiles = append(ac.Spec.ConfigFiles,
-		privateKeyPemFile,
-		publicKeySSHFile,
-		sshdConfigFile)
+	ac.Spec.ConfigFiles = append(ac.Spec.ConfigFiles, sshdConfigFile)
 	return nil
 }
 
-func genNewKeys() (privateKeyPem string, sshPublicKey string) {
-	privateKey, err := rsa.GenerateKey(rand.Reader, 2014)
+// HostKeyTypes are the host keys generated for each ssh app component, by file name
+var HostKeyTypes = []string{"ssh_host_ed25519_key", "ssh_host_ecdsa_key", "ssh_host_rsa_key"}
+
+const (
+	// HostKeyRSABits is the minimum for new RSA host keys
+	HostKeyRSABits = 3072
+
+	// HostKeysSecretType labels (secret-type) the host key secrets
+	HostKeysSecretType = "ssh-hostkeys"
+)
+
+// HostKeysSecretName is the secret holding the host keys of an ssh app component
+func HostKeysSecretName(ac *v1.Appcomponent) string {
+	return "ssh-hostkeys-" + ac.Name
+}
+
+// GenerateHostKeys generates a full set of host keys as secret data, private keys in
+// OpenSSH format under the names in HostKeyTypes and public keys with a .pub suffix
+func GenerateHostKeys() (map[string][]byte, error) {
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, HostKeyRSABits)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
 	}
 
-	privateKeyDer := x509.MarshalPKCS1PrivateKey(privateKey)
-	privateKeyBlock := pem.Block{
-		Type:    "RSA PRIVATE KEY",
-		Headers: nil,
-		Bytes:   privateKeyDer,
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
 	}
 
-	privateKeyPem = string(pem.EncodeToMemory(&privateKeyBlock))
+	keys := map[string]crypto.PrivateKey{
+		"ssh_host_ed25519_key": ed25519Key,
+		"ssh_host_ecdsa_key":   ecdsaKey,
+		"ssh_host_rsa_key":     rsaKey,
+	}
 
-	publicKey := privateKey.PublicKey
+	data := make(map[string][]byte)
+	for name, key := range keys {
 
-	pub, err := ssh.NewPublicKey(&publicKey)
+		block, err := ssh.MarshalPrivateKey(key, "sitepod host key")
+		if err != nil {
+			return nil, err
+		}
 
-	if err != nil {
-		panic(err)
+		signer, err := ssh.NewSignerFromKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		data[name] = pem.EncodeToMemory(block)
+		data[name+".pub"] = ssh.MarshalAuthorizedKey(signer.PublicKey())
+	}
+
+	return data, nil
+}
+
+// HostKeyFingerprints are the SHA256 fingerprints of the public keys in host key secret data
+func HostKeyFingerprints(data map[string][]byte) ([]v1.SSHHostKeyFingerprint, error) {
+
+	fingerprints := []v1.SSHHostKeyFingerprint{}
+	for _, name := range HostKeyTypes {
+
+		publicKeyData, exists := data[name+".pub"]
+		if !exists {
+			continue
+		}
+
+		publicKey, _, _, _, err := ssh.ParseAuthorizedKey(publicKeyData)
+		if err != nil {
+			return nil, fmt.Errorf("Host key %s is invalid: %s", name, err)
+		}
+
+		fingerprints = append(fingerprints, v1.SSHHostKeyFingerprint{
+			Type:        publicKey.Type(),
+			Fingerprint: ssh.FingerprintSHA256(publicKey),
+		})
 	}
 
-	pkBytes := pub.Marshal()
+	return fingerprints, nil
+}
+
+const (
+	// SSHConfigDirectory is where sshd_config is mounted
+	SSHConfigDirectory = "/etc/sitepod/ssh"
+	SSHDConfigName     = "sshdconfig"
+	// SSHHostKeyDirectory is where the host key secret is mounted
+	SSHHostKeyDirectory = "/etc/sitepod/hostkeys"
+)
 
-	sshPublicKey = fmt.Sprintf("ssh-rsa %s %s", base64.StdEncoding.EncodeToString(pkBytes), "placeholder@sitepod.io")
+type sshdConfigData struct {
+	Options    v1.SSHOptions
+	HostKeys   []string
+	AllowUsers []string
+	SFTPUsers  []sshdSFTPUser
+}
 
-	return
+type sshdSFTPUser struct {
+	Username        string
+	ChrootDirectory string
+	StartDirectory  string
 }
 
-func generateSSHDConfig() string {
+// GenerateSSHDConfig renders sshd_config for the options of an ssh app component, only
+// the given users may log in and sftp users get their own Match User block
+func GenerateSSHDConfig(options v1.SSHOptions, users []*v1.SystemUser) string {
 	//TODO figure out where to store templates
 	template, err := template.ParseFiles("../../templates/sshd_config")
 	if err != nil {
 		panic(err)
 	}
+	data := sshdConfigData{
+		Options:    options,
+		HostKeys:   []string{},
+		AllowUsers: []string{},
+		SFTPUsers:  []sshdSFTPUser{},
+	}
+	for _, user := range users {
+		if !user.HasValidUsername() || user.ValidateAccess() != nil {
+			continue
+		}
+		data.AllowUsers = append(data.AllowUsers, user.GetUsername())
+		if user.IsSFTPOnly() {
+			data.SFTPUsers = append(data.SFTPUsers, sshdSFTPUser{
+				Username:        user.GetUsername(),
+				ChrootDirectory: user.GetChrootDirectory(),
+				StartDirectory:  user.GetStartDirectory(),
+			})
+		}
+	}
+	for _, name := range HostKeyTypes {
+		data.HostKeys = append(data.HostKeys, SSHHostKeyDirectory+"/"+name)
+	}
 	buffer := bytes.NewBuffer([]byte{})
-	err = template.Execute(buffer, struct{}{})
+	err = template.Execute(buffer, data)
 	if err != nil {
 		panic(err)
 	}
@@ -1,17 +1,23 @@
 package system
 
 import (
+	"fmt"
+
 	"github.com/golang/glog"
 
 	"sitepod.io/sitepod/pkg/client"
 	//"sitepod.io/sitepod/pkg/controller/appcomp"
+	//"sitepod.io/sitepod/pkg/controller/backup"
 	//"sitepod.io/sitepod/pkg/controller/etc"
+	//"sitepod.io/sitepod/pkg/controller/migration"
 	//"sitepod.io/sitepod/pkg/controller/podtask"
 	//"sitepod.io/sitepod/pkg/controller/sitepod"
+	//"sitepod.io/sitepod/pkg/controller/systemgroup"
 	//"sitepod.io/sitepod/pkg/controller/systemuser"
 	//"sitepod.io/sitepod/pkg/controller/website"
 
 	"k8s.io/kubernetes/pkg/api"
+	kerrors "k8s.io/kubernetes/pkg/api/errors"
 	k8s_v1 "k8s.io/kubernetes/pkg/api/v1"
 	ext_api "k8s.io/kubernetes/pkg/apis/extensions"
 	ext_v1 "k8s.io/kubernetes/pkg/apis/extensions/v1beta1"
@@ -27,6 +33,8 @@ type SimpleSystem struct {
 type SimpleConfig struct {
 	ApiServer string
 	Namespace string
+	Cluster   string
+	WebApi    *webapi.Config
 }
 
 func NewSimpleSystem(config *SimpleConfig) *SimpleSystem {
@@ -46,17 +54,64 @@ func BundleScheme() *runtime.Scheme {
 
 func (s *SimpleSystem) GetClient() *client.Client {
 
-	cc := client.NewClient(BundleScheme(), &client.ClientConfig{s.Config.ApiServer,
-		s.Config.Namespace})
+	cc := client.NewClient(BundleScheme(), &client.ClientConfig{
+		ApiServer: s.Config.ApiServer,
+		Namespace: s.Config.Namespace,
+		Cluster:   s.Config.Cluster,
+	})
 	return cc
 
 }
 
+// BootstrapCluster creates the active cluster with defaults on first start, an existing
+// cluster is only validated
+func BootstrapCluster(cc *client.Client) error {
+
+	name := cc.ClusterName()
+	cluster, err := cc.Clusters().FetchByName(name)
+
+	if err != nil && kerrors.IsNotFound(err) {
+		cluster = cc.Clusters().NewEmpty()
+		cluster.GenerateName = ""
+		cluster.Name = name
+		if err := cluster.Validate(); err != nil {
+			return err
+		}
+
+		glog.Infof("Bootstrapping new cluster %s", name)
+		_, err = cc.Clusters().TryAdd(cluster)
+		if err != nil && kerrors.IsAlreadyExists(err) {
+			// another instance beat us to it
+			return BootstrapCluster(cc)
+		}
+		return err
+	}
+
+	if err != nil {
+		return fmt.Errorf("Unable to get cluster %s: %v", name, err)
+	}
+
+	glog.Infof("Using existing cluster %s", name)
+	return cluster.Validate()
+}
+
 func (s *SimpleSystem) Run(stopCh <-chan struct{}) {
 	glog.Info("Starting simple system")
 
 	cc := s.GetClient()
-	webInst := webapi.NewWebApi(cc)
+
+	if err := BootstrapCluster(cc); err != nil {
+		glog.Fatalf("Cluster %s is not usable: %v", cc.ClusterName(), err)
+	}
+
+	webConfig := s.Config.WebApi
+	if webConfig == nil {
+		webConfig = webapi.DefaultConfig()
+	}
+	webInst, err := webapi.NewWebApi(cc, webConfig)
+	if err != nil {
+		glog.Fatalf("Unable to create web api: %v", err)
+	}
 	webInst.Start()
 
 	//etcController := etc.NewEtcController(cc)
@@ -71,26 +126,48 @@ func (s *SimpleSystem) Run(stopCh <-chan struct{}) {
 	//systemUserController := systemuser.NewSystemUserController(cc)
 	//go systemUserController.Run(stopCh)
 
+	//systemGroupController := systemgroup.NewSystemGroupController(cc)
+	//go systemGroupController.Run(stopCh)
+
 	//podTaskController := podtask.NewPodTaskController(cc)
 	//go podTaskController.Run(stopCh)
 
 	//websiteController := website.NewWebsiteController(cc)
 	//go websiteController.Run(stopCh)
 
+	//backupController := backup.NewBackupController(cc)
+	//go backupController.Run(stopCh)
+
+	//restoreController := backup.NewRestoreController(cc)
+	//go restoreController.Run(stopCh)
+
+	//backupScheduleController := backup.NewBackupScheduleController(cc)
+	//go backupScheduleController.Run(stopCh)
+
+	//migrationController := migration.NewSitepodMigrationController(cc)
+	//go migrationController.Run(stopCh)
+
 	glog.Infof("Starting informers")
-	//go cc.Sitepods().StartInformer(stopCh)
 	//go cc.PVClaims().StartInformer(stopCh)
 	//go cc.PVs().StartInformer(stopCh)
 	//go cc.Pods().StartInformer(stopCh)
-	//go cc.PodTasks().StartInformer(stopCh)
 	//go cc.Deployments().StartInformer(stopCh)
 	//go cc.ReplicaSets().StartInformer(stopCh)
-	//go cc.SystemUsers().StartInformer(stopCh)
+	//go cc.SystemGroups().StartInformer(stopCh)
 	//go cc.ConfigMaps().StartInformer(stopCh)
 	//go cc.Clusters().StartInformer(stopCh)
-	//go cc.AppComps().StartInformer(stopCh)
-	//go cc.Websites().StartInformer(stopCh)
+	//go cc.Secrets().StartInformer(stopCh)
+	//go cc.Backups().StartInformer(stopCh)
+	//go cc.Restores().StartInformer(stopCh)
+	//go cc.BackupSchedules().StartInformer(stopCh)
+	//go cc.SitepodMigrations().StartInformer(stopCh)
 	go cc.SitepodUsers().StartInformer(stopCh)
+	// caches behind the web api
+	go cc.Sitepods().StartInformer(stopCh)
+	go cc.Websites().StartInformer(stopCh)
+	go cc.SystemUsers().StartInformer(stopCh)
+	go cc.AppComps().StartInformer(stopCh)
+	go cc.PodTasks().StartInformer(stopCh)
 	glog.Infof("Started informers")
 	glog.Info("Started simple system")
 	<-stopCh
@@ -1,7 +1,7 @@
 package util
 
 import "io"
-import "errors"
+import "fmt"
 import "crypto/rand"
 import "crypto/sha512"
 import "golang.org/x/crypto/scrypt"
@@ -16,6 +16,14 @@ func RandomSalt(size int) string {
 	return salt[0:size]
 }
 
+func RandomBytes(size int) []byte {
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		panic(err)
+	}
+	return buf
+}
+
 // Recommended password generator
 func Scrypt(password string, salt string) (string, error) {
 
@@ -28,15 +36,49 @@ func Scrypt(password string, salt string) (string, error) {
 	return hstr, nil
 }
 
+const (
+	// Sha512CryptDefaultRounds is used when no rounds=N is given in the crypt string
+	Sha512CryptDefaultRounds = 5000
+	Sha512CryptMinRounds     = 1000
+	Sha512CryptMaxRounds     = 999999999
+	// Sha512CryptMaxSalt bytes of a salt are used, the rest is ignored
+	Sha512CryptMaxSalt = 16
+)
+
+// CheckSha512CryptParams refuses rounds and salts glibc would silently change, for
+// generating new hashes
+func CheckSha512CryptParams(salt string, rounds int) error {
+	if rounds < Sha512CryptMinRounds || rounds > Sha512CryptMaxRounds {
+		return fmt.Errorf("rounds must be between %d and %d", Sha512CryptMinRounds, Sha512CryptMaxRounds)
+	}
+	if len(salt) > Sha512CryptMaxSalt {
+		return fmt.Errorf("salt must not exceed %d bytes", Sha512CryptMaxSalt)
+	}
+	return nil
+}
+
 // Linux shadow compatible sha-512 password
 func Sha512Crypt(password string, salt string) (string, error) {
-	rounds := 5000
+	return Sha512CryptRounds(password, salt, Sha512CryptDefaultRounds)
+}
+
+// Sha512CryptRounds is Sha512Crypt with an explicit rounds count, the crypt string must
+// then carry rounds=N (unless N is the default). As in glibc rounds out of range are
+// clamped and the salt is truncated to 16 bytes, so every shadow hash verifies; use
+// CheckSha512CryptParams before generating a new hash.
+func Sha512CryptRounds(password string, salt string, rounds int) (string, error) {
+
+	if rounds < Sha512CryptMinRounds {
+		rounds = Sha512CryptMinRounds
+	} else if rounds > Sha512CryptMaxRounds {
+		rounds = Sha512CryptMaxRounds
+	}
 
 	passwordb := []byte(password)
 	saltb := []byte(salt)
 
-	if len(saltb) > 16 {
-		return "", errors.New("salt must not exceed 16 bytes")
+	if len(saltb) > Sha512CryptMaxSalt {
+		saltb = saltb[:Sha512CryptMaxSalt]
 	}
 
 	// B
@@ -0,0 +1,84 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+// glibc crypt-sha512 test vectors, the setting as passed to crypt(3) and its output
+var sha512CryptVectors = []struct {
+	setting  string
+	password string
+	expected string
+}{
+	{"$6$saltstring", "Hello world!",
+		"$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1"},
+	{"$6$rounds=10000$saltstringsaltstring", "Hello world!",
+		"$6$rounds=10000$saltstringsaltst$OW1/O6BYHV6BcXZu8QVeXbDWra3Oeqh0sbHbbMCVNSnCM/UrjmM0Dp8vOuZeHBy/YTBmSK6H9qs/y3RnOaw5v."},
+	{"$6$rounds=5000$toolongsaltstring", "This is just a test",
+		"$6$rounds=5000$toolongsaltstrin$lQ8jolhgVRVhY4b5pZKaysCLi0QBxGoNeKQzQ3glMhwllF7oGDZxUhx1yxdYcz/e1JSbq3y6JMxxl8audkUEm0"},
+	{"$6$rounds=1400$anotherlongsaltstring", "a very much longer text to encrypt.  This one even stretches over morethan one line.",
+		"$6$rounds=1400$anotherlongsalts$POfYwTEok97VWcjxIiSOjiykti.o/pQs.wPvMxQ6Fm7I6IoYN3CmLs66x9t0oSwbtEW7o7UmJEiDwGqd8p4ur1"},
+	{"$6$rounds=77777$short", "we have a short salt string but not a short password",
+		"$6$rounds=77777$short$WuQyW2YR.hBNpjjRhpYD/ifIw05xdfeEyQoMxIXbkvr0gge1a1x3yRULJ5CCaUeOxFmtlcGZelFl5CxtgfiAc0"},
+	{"$6$rounds=123456$asaltof16chars..", "a short string",
+		"$6$rounds=123456$asaltof16chars..$BtCwjqMJGx5hrJhZywWvt0RLE8uZ4oPwcelCjmw2kSYu.Ec6ycULevoBK25fs2xXgMNrCzIMVcgEJAstJeonj1"},
+	{"$6$rounds=10$roundstoolow", "the minimum number is still observed",
+		"$6$rounds=1000$roundstoolow$kUMsbe306n21p9R.FRkW3IGn.S9NPN0x50YhH1xhLsPuWGsUSklZt58jaTfF4ZEQpyUNGc0dqbpBYYBaHHrsX."},
+}
+
+func TestSha512CryptVectors(t *testing.T) {
+	for _, v := range sha512CryptVectors {
+		// settings have no hash, parse them with a placeholder
+		salt, _, rounds, err := ParseSha512Crypt(v.setting + "$x")
+		if err != nil {
+			t.Fatalf("%s: %v", v.setting, err)
+		}
+
+		hash, err := Sha512CryptRounds(v.password, salt, rounds)
+		if err != nil {
+			t.Fatalf("%s: %v", v.setting, err)
+		}
+
+		_, expected, _, _ := ParseSha512Crypt(v.expected)
+		if hash != expected {
+			t.Errorf("%s: got %s, want %s", v.setting, hash, expected)
+		}
+	}
+}
+
+func TestSha512CryptDefaultRounds(t *testing.T) {
+	hash, err := Sha512Crypt("Hello world!", "saltstring")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if FormatSha512Crypt("saltstring", hash, Sha512CryptDefaultRounds) != sha512CryptVectors[0].expected {
+		t.Errorf("got %s", hash)
+	}
+}
+
+func TestSha512CryptHasherVerifiesVectors(t *testing.T) {
+	hasher := &Sha512CryptHasher{Rounds: 100000}
+	for _, v := range sha512CryptVectors {
+		matched, err := hasher.Verify(v.password, v.expected)
+		if err != nil || !matched {
+			t.Errorf("%s: matched %v, err %v", v.expected, matched, err)
+		}
+		matched, _ = hasher.Verify(v.password+"x", v.expected)
+		if matched {
+			t.Errorf("%s: wrong password matched", v.expected)
+		}
+	}
+}
+
+func TestCheckSha512CryptParams(t *testing.T) {
+	if err := CheckSha512CryptParams("saltstring", 10); err == nil {
+		t.Error("rounds below the minimum accepted for a new hash")
+	}
+	if err := CheckSha512CryptParams(strings.Repeat("s", 17), 5000); err == nil {
+		t.Error("salt over 16 bytes accepted for a new hash")
+	}
+	if err := CheckSha512CryptParams("saltstring", 5000); err != nil {
+		t.Error(err)
+	}
+}
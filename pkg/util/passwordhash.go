@@ -0,0 +1,304 @@
+package util
+
+// Password hashes are kept in modular crypt format ($id$params$salt$hash) so the scheme
+// and its parameters travel with the hash. Each scheme registers a PasswordHasher, a
+// hash is verified by whichever hasher claims its id, and a hash made by a scheme or
+// with parameters weaker than the current default is flagged for upgrade.
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	SchemeSha512Crypt = "sha512-crypt"
+	SchemeBcrypt      = "bcrypt"
+	SchemeArgon2id    = "argon2id"
+)
+
+var (
+	// DefaultPasswordScheme is used for new hashes, hashes of other schemes need upgrade
+	DefaultPasswordScheme = SchemeArgon2id
+
+	ErrUnsupportedPasswordScheme = errors.New("unsupported password hash scheme")
+	ErrMalformedPasswordHash     = errors.New("malformed password hash")
+)
+
+type PasswordHasher interface {
+	// Scheme is the registry key e.g. bcrypt
+	Scheme() string
+	// IDs are the modular crypt format ids handled e.g. 2a, 2b and 2y
+	IDs() []string
+	// Hash produces a modular crypt format string with a new random salt
+	Hash(password string) (string, error)
+	// Verify checks a password against a modular crypt format string of this scheme
+	Verify(password string, hash string) (bool, error)
+	// NeedsUpgrade is true if the hash was made with weaker than current parameters
+	NeedsUpgrade(hash string) bool
+}
+
+var (
+	passwordHashers      = make(map[string]PasswordHasher)
+	passwordHashersByID  = make(map[string]PasswordHasher)
+	passwordHashersMutex sync.RWMutex
+)
+
+func init() {
+	RegisterPasswordHasher(&Sha512CryptHasher{Rounds: 100000})
+	RegisterPasswordHasher(&BcryptHasher{Cost: 12})
+	RegisterPasswordHasher(&Argon2idHasher{Time: 3, Memory: 64 * 1024, Threads: 2, KeyLength: 32})
+}
+
+// RegisterPasswordHasher adds or replaces the hasher of a scheme
+func RegisterPasswordHasher(hasher PasswordHasher) {
+	passwordHashersMutex.Lock()
+	defer passwordHashersMutex.Unlock()
+	passwordHashers[hasher.Scheme()] = hasher
+	for _, id := range hasher.IDs() {
+		passwordHashersByID[id] = hasher
+	}
+}
+
+func LookupPasswordHasher(scheme string) (PasswordHasher, bool) {
+	passwordHashersMutex.RLock()
+	defer passwordHashersMutex.RUnlock()
+	hasher, exists := passwordHashers[scheme]
+	return hasher, exists
+}
+
+// ParseMCF splits a modular crypt format string into its id and the fields after it
+func ParseMCF(hash string) (string, []string, error) {
+	if !strings.HasPrefix(hash, "$") {
+		return "", nil, ErrMalformedPasswordHash
+	}
+	fields := strings.Split(hash[1:], "$")
+	if len(fields) < 2 || len(fields[0]) == 0 {
+		return "", nil, ErrMalformedPasswordHash
+	}
+	return fields[0], fields[1:], nil
+}
+
+// PasswordHasherFor is the hasher of the scheme a modular crypt format string was made with
+func PasswordHasherFor(hash string) (PasswordHasher, error) {
+	id, _, err := ParseMCF(hash)
+	if err != nil {
+		return nil, err
+	}
+	passwordHashersMutex.RLock()
+	defer passwordHashersMutex.RUnlock()
+	hasher, exists := passwordHashersByID[id]
+	if !exists {
+		return nil, ErrUnsupportedPasswordScheme
+	}
+	return hasher, nil
+}
+
+// HashPassword hashes with the given scheme, blank for the default
+func HashPassword(scheme string, password string) (string, error) {
+	if len(scheme) == 0 {
+		scheme = DefaultPasswordScheme
+	}
+	hasher, exists := LookupPasswordHasher(scheme)
+	if !exists {
+		return "", ErrUnsupportedPasswordScheme
+	}
+	return hasher.Hash(password)
+}
+
+// VerifyPassword checks a password against a hash of any registered scheme. needsUpgrade
+// is only meaningful when the password matched, the caller should then rehash it with
+// HashPassword while it has the plain text.
+func VerifyPassword(password string, hash string) (matched bool, needsUpgrade bool, err error) {
+
+	hasher, err := PasswordHasherFor(hash)
+	if err != nil {
+		return false, false, err
+	}
+
+	matched, err = hasher.Verify(password, hash)
+	if err != nil || !matched {
+		return false, false, err
+	}
+
+	needsUpgrade = hasher.Scheme() != DefaultPasswordScheme || hasher.NeedsUpgrade(hash)
+	return true, needsUpgrade, nil
+}
+
+// Sha512CryptHasher is $6$[rounds=N$]salt$hash as used by glibc shadow files
+type Sha512CryptHasher struct {
+	Rounds int
+}
+
+func (h *Sha512CryptHasher) Scheme() string { return SchemeSha512Crypt }
+func (h *Sha512CryptHasher) IDs() []string  { return []string{"6"} }
+
+func (h *Sha512CryptHasher) Hash(password string) (string, error) {
+	salt := RandomSalt(Sha512CryptMaxSalt)
+	if err := CheckSha512CryptParams(salt, h.Rounds); err != nil {
+		return "", err
+	}
+	hash, err := Sha512CryptRounds(password, salt, h.Rounds)
+	if err != nil {
+		return "", err
+	}
+	return FormatSha512Crypt(salt, hash, h.Rounds), nil
+}
+
+func (h *Sha512CryptHasher) Verify(password string, hash string) (bool, error) {
+	salt, expected, rounds, err := ParseSha512Crypt(hash)
+	if err != nil {
+		return false, err
+	}
+	actual, err := Sha512CryptRounds(password, salt, rounds)
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare([]byte(actual), []byte(expected)) == 1, nil
+}
+
+func (h *Sha512CryptHasher) NeedsUpgrade(hash string) bool {
+	_, _, rounds, err := ParseSha512Crypt(hash)
+	return err != nil || rounds < h.Rounds
+}
+
+// FormatSha512Crypt omits rounds=N for the default rounds as crypt(3) does
+func FormatSha512Crypt(salt string, hash string, rounds int) string {
+	if rounds == 0 || rounds == Sha512CryptDefaultRounds {
+		return fmt.Sprintf("$6$%s$%s", salt, hash)
+	}
+	return fmt.Sprintf("$6$rounds=%d$%s$%s", rounds, salt, hash)
+}
+
+func ParseSha512Crypt(mcf string) (salt string, hash string, rounds int, err error) {
+
+	id, fields, err := ParseMCF(mcf)
+	if err != nil {
+		return "", "", 0, err
+	}
+	if id != "6" {
+		return "", "", 0, ErrUnsupportedPasswordScheme
+	}
+
+	rounds = Sha512CryptDefaultRounds
+	if len(fields) == 3 && strings.HasPrefix(fields[0], "rounds=") {
+		rounds, err = strconv.Atoi(strings.TrimPrefix(fields[0], "rounds="))
+		if err != nil {
+			return "", "", 0, ErrMalformedPasswordHash
+		}
+		fields = fields[1:]
+	}
+
+	if len(fields) != 2 {
+		return "", "", 0, ErrMalformedPasswordHash
+	}
+	return fields[0], fields[1], rounds, nil
+}
+
+type BcryptHasher struct {
+	Cost int
+}
+
+func (h *BcryptHasher) Scheme() string { return SchemeBcrypt }
+func (h *BcryptHasher) IDs() []string  { return []string{"2a", "2b", "2y"} }
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	return string(hash), err
+}
+
+func (h *BcryptHasher) Verify(password string, hash string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (h *BcryptHasher) NeedsUpgrade(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost < h.Cost
+}
+
+// Argon2idHasher is $argon2id$v=19$m=MEMORY,t=TIME,p=THREADS$salt$hash in unpadded
+// base64 as the reference implementation formats it
+type Argon2idHasher struct {
+	Time      uint32
+	Memory    uint32
+	Threads   uint8
+	KeyLength uint32
+}
+
+type argon2idParams struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+func (h *Argon2idHasher) Scheme() string { return SchemeArgon2id }
+func (h *Argon2idHasher) IDs() []string  { return []string{"argon2id"} }
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := RandomBytes(16)
+	key := argon2.IDKey([]byte(password), salt, h.Time, h.Memory, h.Threads, h.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, h.Memory, h.Time, h.Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h *Argon2idHasher) Verify(password string, hash string) (bool, error) {
+	params, err := parseArgon2id(hash)
+	if err != nil {
+		return false, err
+	}
+	key := argon2.IDKey([]byte(password), params.salt, params.time, params.memory, params.threads,
+		uint32(len(params.key)))
+	return subtle.ConstantTimeCompare(key, params.key) == 1, nil
+}
+
+func (h *Argon2idHasher) NeedsUpgrade(hash string) bool {
+	params, err := parseArgon2id(hash)
+	return err != nil || params.time < h.Time || params.memory < h.Memory ||
+		uint32(len(params.key)) < h.KeyLength
+}
+
+func parseArgon2id(mcf string) (*argon2idParams, error) {
+
+	id, fields, err := ParseMCF(mcf)
+	if err != nil {
+		return nil, err
+	}
+	if id != "argon2id" {
+		return nil, ErrUnsupportedPasswordScheme
+	}
+	if len(fields) != 4 {
+		return nil, ErrMalformedPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(fields[0], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, ErrMalformedPasswordHash
+	}
+
+	params := &argon2idParams{}
+	if _, err := fmt.Sscanf(fields[1], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return nil, ErrMalformedPasswordHash
+	}
+
+	if params.salt, err = base64.RawStdEncoding.DecodeString(fields[2]); err != nil {
+		return nil, ErrMalformedPasswordHash
+	}
+	if params.key, err = base64.RawStdEncoding.DecodeString(fields[3]); err != nil || len(params.key) == 0 {
+		return nil, ErrMalformedPasswordHash
+	}
+
+	return params, nil
+}
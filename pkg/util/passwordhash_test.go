@@ -0,0 +1,75 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashPasswordRoundTrip(t *testing.T) {
+	for _, scheme := range []string{SchemeArgon2id, SchemeBcrypt, SchemeSha512Crypt} {
+		hash, err := HashPassword(scheme, "correct horse")
+		if err != nil {
+			t.Fatalf("%s: %v", scheme, err)
+		}
+
+		hasher, err := PasswordHasherFor(hash)
+		if err != nil || hasher.Scheme() != scheme {
+			t.Fatalf("%s: hash %s not claimed by its scheme: %v", scheme, hash, err)
+		}
+
+		matched, needsUpgrade, err := VerifyPassword("correct horse", hash)
+		if err != nil || !matched {
+			t.Errorf("%s: matched %v, err %v", scheme, matched, err)
+		}
+		if needsUpgrade != (scheme != DefaultPasswordScheme) {
+			t.Errorf("%s: needsUpgrade %v", scheme, needsUpgrade)
+		}
+
+		matched, _, err = VerifyPassword("wrong horse", hash)
+		if err != nil || matched {
+			t.Errorf("%s: wrong password matched %v, err %v", scheme, matched, err)
+		}
+	}
+}
+
+func TestHashPasswordDefaultScheme(t *testing.T) {
+	hash, err := HashPassword("", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$") {
+		t.Errorf("default hash %s is not argon2id", hash)
+	}
+}
+
+func TestNeedsUpgradeWeakerParameters(t *testing.T) {
+	weak := &Argon2idHasher{Time: 1, Memory: 8 * 1024, Threads: 1, KeyLength: 32}
+	hash, err := weak.Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matched, needsUpgrade, err := VerifyPassword("correct horse", hash)
+	if err != nil || !matched || !needsUpgrade {
+		t.Errorf("weak argon2id: matched %v, needsUpgrade %v, err %v", matched, needsUpgrade, err)
+	}
+
+	cheap := &BcryptHasher{Cost: 4}
+	hash, err = cheap.Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcryptHasher, _ := LookupPasswordHasher(SchemeBcrypt)
+	if !bcryptHasher.NeedsUpgrade(hash) {
+		t.Error("bcrypt cost 4 not flagged for upgrade")
+	}
+}
+
+func TestVerifyPasswordUnsupported(t *testing.T) {
+	if _, _, err := VerifyPassword("x", "$1$salt$hash"); err != ErrUnsupportedPasswordScheme {
+		t.Errorf("md5-crypt: err %v", err)
+	}
+	if _, _, err := VerifyPassword("x", "plain"); err != ErrMalformedPasswordHash {
+		t.Errorf("no mcf: err %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package util
+
+// Time-based one time passwords (RFC 6238) as used by authenticator apps: HMAC-SHA1 of
+// the 30 second time step, truncated per RFC 4226 to 6 digits. Secrets are exchanged as
+// unpadded base32.
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	TOTPDigits = 6
+	TOTPPeriod = 30
+	// TOTPSkew is how many time steps either side of now are accepted for clock drift
+	TOTPSkew = 1
+	// TOTPSecretSize is 160 bits as RFC 4226 recommends for SHA1
+	TOTPSecretSize = 20
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+var digitsPower = []uint32{1, 10, 100, 1000, 10000, 100000, 1000000, 10000000, 100000000}
+
+func GenerateTOTPSecret() string {
+	return totpEncoding.EncodeToString(RandomBytes(TOTPSecretSize))
+}
+
+func DecodeTOTPSecret(secret string) ([]byte, error) {
+	return totpEncoding.DecodeString(strings.ToUpper(strings.Replace(strings.TrimRight(secret, "="), " ", "", -1)))
+}
+
+// TOTPCounter is the time step of t
+func TOTPCounter(t time.Time) int64 {
+	return t.Unix() / TOTPPeriod
+}
+
+// HOTP is the RFC 4226 code of a counter
+func HOTP(key []byte, counter int64, digits int) string {
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	binCode := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", digits, binCode%digitsPower[digits])
+}
+
+func TOTP(key []byte, t time.Time, digits int) string {
+	return HOTP(key, TOTPCounter(t), digits)
+}
+
+// VerifyTOTP checks a code against the time steps around t, returning the matched step.
+// Callers reject a step not after the last one accepted so a code can not be replayed.
+func VerifyTOTP(secret string, code string, t time.Time) (bool, int64) {
+
+	key, err := DecodeTOTPSecret(secret)
+	if err != nil || len(code) != TOTPDigits {
+		return false, 0
+	}
+
+	now := TOTPCounter(t)
+	for counter := now - TOTPSkew; counter <= now+TOTPSkew; counter++ {
+		if subtle.ConstantTimeCompare([]byte(HOTP(key, counter, TOTPDigits)), []byte(code)) == 1 {
+			return true, counter
+		}
+	}
+	return false, 0
+}
+
+// TOTPProvisioningURI is the otpauth URI authenticator apps read from a QR code
+func TOTPProvisioningURI(issuer string, account string, secret string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprintf("%d", TOTPDigits))
+	params.Set("period", fmt.Sprintf("%d", TOTPPeriod))
+	label := escapeLabel(issuer) + ":" + escapeLabel(account)
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// escapeLabel escapes a label part, spaces as %20 as authenticators expect
+func escapeLabel(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
@@ -0,0 +1,69 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+// RFC 6238 Appendix B test vectors for HMAC-SHA1, 8 digit codes
+var totpVectors = []struct {
+	unix     int64
+	expected string
+}{
+	{59, "94287082"},
+	{1111111109, "07081804"},
+	{1111111111, "14050471"},
+	{1234567890, "89005924"},
+	{2000000000, "69279037"},
+	{20000000000, "65353130"},
+}
+
+var totpTestKey = []byte("12345678901234567890")
+
+func TestTOTPVectors(t *testing.T) {
+	for _, v := range totpVectors {
+		if code := TOTP(totpTestKey, time.Unix(v.unix, 0), 8); code != v.expected {
+			t.Errorf("TOTP at %d: got %s, want %s", v.unix, code, v.expected)
+		}
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	secret := totpEncoding.EncodeToString(totpTestKey)
+	now := time.Unix(1111111111, 0)
+	counter := TOTPCounter(now)
+
+	for _, skew := range []int64{-TOTPSkew, 0, TOTPSkew} {
+		code := HOTP(totpTestKey, counter+skew, TOTPDigits)
+		matched, step := VerifyTOTP(secret, code, now)
+		if !matched || step != counter+skew {
+			t.Errorf("code of step %+d: matched %v, step %d", skew, matched, step-counter)
+		}
+	}
+
+	for _, skew := range []int64{-TOTPSkew - 1, TOTPSkew + 1} {
+		if matched, _ := VerifyTOTP(secret, HOTP(totpTestKey, counter+skew, TOTPDigits), now); matched {
+			t.Errorf("code of step %+d accepted", skew)
+		}
+	}
+
+	if matched, _ := VerifyTOTP(secret, "12345", now); matched {
+		t.Error("short code accepted")
+	}
+	if matched, _ := VerifyTOTP("not base32!", TOTP(totpTestKey, now, TOTPDigits), now); matched {
+		t.Error("code accepted with an invalid secret")
+	}
+}
+
+func TestDecodeTOTPSecret(t *testing.T) {
+	secret := GenerateTOTPSecret()
+	key, err := DecodeTOTPSecret(secret)
+	if err != nil || len(key) != TOTPSecretSize {
+		t.Fatalf("decoding %s: %d bytes, err %v", secret, len(key), err)
+	}
+
+	// authenticator apps show secrets in lower case groups, sometimes padded
+	if _, err := DecodeTOTPSecret("gezd gnbv gy3t qojq gezd gnbv gy3t qojq===="); err != nil {
+		t.Errorf("grouped lower case secret: %v", err)
+	}
+}
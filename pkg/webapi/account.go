@@ -0,0 +1,251 @@
+package webapi
+
+// Account recovery and email verification. Reset and verification links carry signed
+// tokens (see usertokens.go) and are mailed to the user's address. Requesting a reset
+// answers the same whether or not the account exists, the mail is sent in the background
+// so timing does not tell either. A reset logs out every session of the user, event
+// streams included, api tokens are left to be revoked separately.
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/golang/glog"
+	"sitepod.io/sitepod/pkg/api/v1"
+	"sitepod.io/sitepod/pkg/mail"
+)
+
+// AccountMailInterval is the least time between reset or verification mails to one
+// account, so the endpoints can't be used to flood a mailbox
+var AccountMailInterval = 5 * time.Minute
+
+type mailThrottle struct {
+	mutex sync.Mutex
+	sent  map[string]time.Time
+}
+
+func newMailThrottle() *mailThrottle {
+	return &mailThrottle{sent: make(map[string]time.Time)}
+}
+
+// Allow records a mail to account unless one was sent within AccountMailInterval
+func (t *mailThrottle) Allow(account string, now time.Time) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for sentTo, at := range t.sent {
+		if now.Sub(at) >= AccountMailInterval {
+			delete(t.sent, sentTo)
+		}
+	}
+	if _, recent := t.sent[account]; recent {
+		return false
+	}
+	t.sent[account] = now
+	return true
+}
+
+func (i *WebApi) addAccountRoutes(ws *restful.WebService) {
+
+	ws.Route(ws.POST("/password/request-reset").To(i.RequestPasswordReset).
+		Doc("Mail a password reset link, the response does not tell whether the account exists").
+		Metadata(tags(tagAccount)).
+		Reads(RequestPasswordResetRequest{}).
+		Returns(200, "OK", nil).
+		Returns(429, "too many requests", APIError{}))
+
+	ws.Route(ws.POST("/password/reset").To(i.ResetPassword).
+		Doc("Set a new password with the token of a reset link, this also verifies the email and logs out all sessions of the user").
+		Metadata(tags(tagAccount)).
+		Reads(ResetPasswordRequest{}).
+		Returns(200, "OK", nil).
+		Returns(400, "invalid or expired token or password too short", APIError{}))
+
+	ws.Route(ws.POST("/verify-email").To(i.VerifyEmail).
+		Doc("Verify the email of a user with the token of a verification link").
+		Metadata(tags(tagAccount)).
+		Reads(VerifyEmailRequest{}).
+		Returns(200, "OK", nil).
+		Returns(400, "invalid or expired token", APIError{}))
+
+	ws.Route(documentErrors(ws.POST("/verify-email/send").To(i.SendVerifyEmail).
+		Doc("Mail a verification link to the logged in user").
+		Metadata(tags(tagAccount)).
+		Returns(200, "OK", nil).
+		Returns(400, "email already verified", APIError{}).
+		Returns(429, "a mail was sent recently", APIError{})))
+}
+
+func (i *WebApi) RequestPasswordReset(req *restful.Request, resp *restful.Response) {
+
+	entity := &RequestPasswordResetRequest{}
+	if err := req.ReadEntity(entity); err != nil {
+		resp.WriteHeaderAndEntity(400, NewAPIError("Invalid Payload"))
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(entity.Data.Email))
+	if len(email) == 0 {
+		resp.WriteHeaderAndEntity(400, NewAPIError("email required"))
+		return
+	}
+
+	// limited by client address only, a locked out account is the one most in need
+	if !i.allowCodeAttempt(req, resp, "") {
+		return
+	}
+
+	user, exists := i.client.SitepodUsers().MaybeGetByKey("sitepod-user-" + GetMD5Hash(email))
+	if exists && i.mailThrottle.Allow(user.Name, time.Now()) {
+		token := i.userTokens.Sign(TokenPurposeResetPassword, user, time.Now().Add(ResetPasswordTokenLifetime))
+		link := i.config.baseURL() + "/ui/reset-password?token=" + url.QueryEscape(token)
+		go i.sendMail(&mail.Message{
+			To:      user.Spec.Email,
+			Subject: "Reset your sitepod password",
+			Body:    fmt.Sprintf(resetPasswordMail, link, ResetPasswordTokenLifetime),
+		})
+	}
+
+	resp.WriteHeaderAndEntity(200, struct{}{})
+}
+
+func (i *WebApi) ResetPassword(req *restful.Request, resp *restful.Response) {
+
+	entity := &ResetPasswordRequest{}
+	if err := req.ReadEntity(entity); err != nil {
+		resp.WriteHeaderAndEntity(400, NewAPIError("Invalid Payload"))
+		return
+	}
+
+	if len(entity.Data.NewPassword) < MinPasswordLength {
+		resp.WriteHeaderAndEntity(400, NewAPIError(fmt.Sprintf("password must be at least %d characters", MinPasswordLength)))
+		return
+	}
+
+	user, ok := i.userOfToken(req, resp, TokenPurposeResetPassword, entity.Data.Token)
+	if !ok {
+		return
+	}
+
+	if err := user.SetPassword(entity.Data.NewPassword); err != nil {
+		resp.WriteHeaderAndEntity(500, NewAPIError("unable to set password"))
+		return
+	}
+	// the reset link came by mail so the address is proven
+	user.Status.Status = v1.SitepodUserActive
+	// whoever knew the old password is logged out, the user logs in with the new one
+	user.RevokeSessions()
+
+	if _, err := i.client.SitepodUsers().TryUpdate(user); err != nil {
+		writeClientError(resp, err)
+		return
+	}
+
+	// a locked out owner has just proven themselves
+	i.loginLimiter.Succeeded(user.Spec.Email)
+	resp.WriteHeaderAndEntity(200, struct{}{})
+}
+
+func (i *WebApi) VerifyEmail(req *restful.Request, resp *restful.Response) {
+
+	entity := &VerifyEmailRequest{}
+	if err := req.ReadEntity(entity); err != nil {
+		resp.WriteHeaderAndEntity(400, NewAPIError("Invalid Payload"))
+		return
+	}
+
+	user, ok := i.userOfToken(req, resp, TokenPurposeVerifyEmail, entity.Data.Token)
+	if !ok {
+		return
+	}
+
+	if user.Status.Status != v1.SitepodUserActive {
+		user.Status.Status = v1.SitepodUserActive
+		if _, err := i.client.SitepodUsers().TryUpdate(user); err != nil {
+			writeClientError(resp, err)
+			return
+		}
+	}
+
+	resp.WriteHeaderAndEntity(200, struct{}{})
+}
+
+func (i *WebApi) SendVerifyEmail(req *restful.Request, resp *restful.Response) {
+
+	user, ok := i.sessionUser(req, resp)
+	if !ok {
+		return
+	}
+
+	if user.EmailVerified() {
+		resp.WriteHeaderAndEntity(400, NewAPIError("email already verified"))
+		return
+	}
+
+	if !i.mailThrottle.Allow(user.Name, time.Now()) {
+		resp.WriteHeaderAndEntity(429, NewAPIError("a mail was sent recently, check your inbox"))
+		return
+	}
+
+	token := i.userTokens.Sign(TokenPurposeVerifyEmail, user, time.Now().Add(VerifyEmailTokenLifetime))
+	link := i.config.baseURL() + "/ui/verify-email?token=" + url.QueryEscape(token)
+	err := i.mailer.Send(&mail.Message{
+		To:      user.Spec.Email,
+		Subject: "Verify your sitepod email address",
+		Body:    fmt.Sprintf(verifyEmailMail, link, VerifyEmailTokenLifetime),
+	})
+	if err != nil {
+		glog.Errorf("Unable to send verification mail to %s: %v", user.Name, err)
+		resp.WriteHeaderAndEntity(500, NewAPIError("unable to send mail"))
+		return
+	}
+
+	resp.WriteHeaderAndEntity(200, struct{}{})
+}
+
+// userOfToken verifies a mailed token and fetches the user it still applies to
+func (i *WebApi) userOfToken(req *restful.Request, resp *restful.Response, purpose string, token string) (*v1.SitepodUser, bool) {
+
+	name, binding, err := i.userTokens.Verify(purpose, token, time.Now())
+	if err != nil {
+		resp.WriteHeaderAndEntity(400, NewAPIError(err.Error()))
+		return nil, false
+	}
+
+	user, err := i.client.SitepodUsers().FetchByName(name)
+	if err != nil || !i.userTokens.Bound(purpose, binding, user) {
+		glog.Warningf("Spent or stale %s token for %s from %s", purpose, name, clientAddr(req.Request))
+		resp.WriteHeaderAndEntity(400, NewAPIError(errInvalidUserToken.Error()))
+		return nil, false
+	}
+	return user, true
+}
+
+func (i *WebApi) sendMail(msg *mail.Message) {
+	if err := i.mailer.Send(msg); err != nil {
+		glog.Errorf("Unable to send %q mail: %v", msg.Subject, err)
+	}
+}
+
+const resetPasswordMail = `Hello,
+
+a password reset was requested for your sitepod account. Open this link to choose a
+new password:
+
+%s
+
+The link is valid for %v and only once. If you did not ask for it, ignore this mail,
+your password stays unchanged.
+`
+
+const verifyEmailMail = `Hello,
+
+please confirm this is the email address of your sitepod account by opening this link:
+
+%s
+
+The link is valid for %v.
+`
@@ -0,0 +1,67 @@
+package webapi
+
+// OpenAPI description of the web api at /api/apidocs.json built from the route metadata,
+// used to generate typed clients. The document itself needs no session.
+
+import (
+	"github.com/emicklei/go-restful"
+	restfulspec "github.com/emicklei/go-restful-openapi"
+	"github.com/go-openapi/spec"
+)
+
+const APIDocsPath = "/api/apidocs.json"
+
+const (
+	tagSession   = "session"
+	tagTwoFactor = "two-factor"
+	tagAccount   = "account"
+	tagSitepods  = "sitepods"
+	tagMembers   = "members"
+	tagEvents    = "events"
+)
+
+func tags(tag string) (string, []string) {
+	return restfulspec.KeyOpenAPITags, []string{tag}
+}
+
+func sitepodParam(ws *restful.WebService) *restful.Parameter {
+	return ws.PathParameter("sitepod", "name of the sitepod").DataType("string")
+}
+
+func nameParam(ws *restful.WebService, kind string) *restful.Parameter {
+	return ws.PathParameter("name", "name of the "+kind).DataType("string")
+}
+
+// documentErrors adds the error responses common to authenticated routes
+func documentErrors(rb *restful.RouteBuilder) *restful.RouteBuilder {
+	return rb.
+		Returns(401, "not logged in or invalid token", APIError{}).
+		Returns(403, "role or token scope does not permit this", APIError{}).
+		Returns(404, "not found or no role on the sitepod", APIError{})
+}
+
+func (i *WebApi) addAPIDocs() {
+	config := restfulspec.Config{
+		WebServices:                   i.container.RegisteredWebServices(),
+		APIPath:                       APIDocsPath,
+		PostBuildSwaggerObjectHandler: describeAPI,
+	}
+	i.container.Add(restfulspec.NewOpenAPIService(config))
+}
+
+func describeAPI(swagger *spec.Swagger) {
+	swagger.Info = &spec.Info{
+		InfoProps: spec.InfoProps{
+			Title:       "Sitepod control panel api",
+			Description: "Manage sitepods and their websites, system users and app components",
+			Version:     "v1",
+		},
+	}
+	// openapi 2 knows neither cookie nor bearer auth, both are described as header keys
+	session := spec.APIKeyAuth("Cookie", "header")
+	session.Description = "sitepodfe session cookie set by POST /api/login"
+	token := spec.APIKeyAuth("Authorization", "header")
+	token.Description = "Bearer followed by an api token"
+	swagger.SecurityDefinitions = spec.SecurityDefinitions{"session": session, "token": token}
+	swagger.Security = []map[string][]string{{"session": {}}, {"token": {}}}
+}
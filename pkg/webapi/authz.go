@@ -0,0 +1,190 @@
+package webapi
+
+// Authorization of sitepod users against sitepods. A user's role on a sitepod, from the
+// sitepod owner and members, grants a fixed set of permissions. Sitepods a user has no
+// role on are reported as not found so their names do not leak; a role without the
+// permission is forbidden. A reseller is an owner of many sitepods with their customers
+// as members of their own.
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+	"sitepod.io/sitepod/pkg/api/v1"
+)
+
+type Permission string
+
+const (
+	// PermissionViewSitepod is seeing the sitepod itself
+	PermissionViewSitepod Permission = "view-sitepod"
+	// PermissionViewResources is reading websites, system users and app components
+	PermissionViewResources Permission = "view-resources"
+	// PermissionEditResources is changing websites, system users and app components
+	PermissionEditResources Permission = "edit-resources"
+	// PermissionManageSitepod is changing or deleting the sitepod and its members
+	PermissionManageSitepod Permission = "manage-sitepod"
+	PermissionViewBilling   Permission = "view-billing"
+)
+
+var rolePermissions = map[string][]Permission{
+	v1.RoleOwner: {PermissionViewSitepod, PermissionViewResources, PermissionEditResources,
+		PermissionManageSitepod, PermissionViewBilling},
+	v1.RoleDeveloper:     {PermissionViewSitepod, PermissionViewResources, PermissionEditResources},
+	v1.RoleBillingViewer: {PermissionViewSitepod, PermissionViewBilling},
+}
+
+// permissionScopes are the api token scopes needed for each permission
+var permissionScopes = map[Permission]string{
+	PermissionViewSitepod:   v1.ScopeRead,
+	PermissionViewResources: v1.ScopeRead,
+	PermissionEditResources: v1.ScopeWrite,
+	PermissionManageSitepod: v1.ScopeWrite,
+	PermissionViewBilling:   v1.ScopeBilling,
+}
+
+// hasScope is true for sessions, api tokens are limited to their scopes
+func hasScope(req *restful.Request, scope string) bool {
+	scopes, isToken := req.Attribute("scopes").([]string)
+	if !isToken {
+		return true
+	}
+	for _, granted := range scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// requireScope writes a 403 unless the caller has the scope
+func requireScope(req *restful.Request, resp *restful.Response, scope string) bool {
+	if !hasScope(req, scope) {
+		resp.WriteHeaderAndEntity(403, NewAPIError("token scope does not permit this"))
+		return false
+	}
+	return true
+}
+
+func RoleHasPermission(role string, permission Permission) bool {
+	for _, granted := range rolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizedSitepod is the sitepod named by the path if the session user has the
+// permission on it, otherwise a 404 or 403 has been written
+func (i *WebApi) authorizedSitepod(req *restful.Request, resp *restful.Response, permission Permission) (*v1.Sitepod, bool) {
+	if !requireScope(req, resp, permissionScopes[permission]) {
+		return nil, false
+	}
+	name := req.PathParameter("sitepod")
+	user := sitepodUserName(req)
+	for _, sitepod := range i.client.Sitepods().ByMember(user) {
+		if sitepod.Name != name {
+			continue
+		}
+		if !RoleHasPermission(sitepod.RoleOf(user), permission) {
+			resp.WriteHeaderAndEntity(403, NewAPIError("not permitted"))
+			return nil, false
+		}
+		return sitepod, true
+	}
+	resp.WriteHeaderAndEntity(404, NewAPIError("sitepod not found"))
+	return nil, false
+}
+
+func (i *WebApi) addMemberRoutes(ws *restful.WebService) {
+	ws.Route(documentErrors(ws.GET("/sitepods/{sitepod}/members").To(i.ListMembers).
+		Doc("List the owner and members of a sitepod").
+		Metadata(tags(tagMembers)).
+		Param(sitepodParam(ws)).
+		Writes([]MemberEntry{}).
+		Returns(200, "OK", []MemberEntry{})))
+
+	ws.Route(documentErrors(ws.PUT("/sitepods/{sitepod}/members").To(i.SetMembers).
+		Doc("Replace the members of a sitepod by email, owners only. Emails without a sitepod user are accepted alike and take effect once the user exists").
+		Metadata(tags(tagMembers)).
+		Param(sitepodParam(ws)).
+		Reads(SetMembersRequest{}).
+		Writes(v1.Sitepod{}).
+		Returns(200, "OK", v1.Sitepod{}).
+		Returns(409, "changed since read", APIError{}).
+		Returns(422, "invalid", APIError{})))
+}
+
+// ListMembers is the owner and members by email, visible to anyone with a role
+func (i *WebApi) ListMembers(req *restful.Request, resp *restful.Response) {
+
+	sitepod, ok := i.authorizedSitepod(req, resp, PermissionViewSitepod)
+	if !ok {
+		return
+	}
+
+	emails := make(map[string]string)
+	for _, member := range sitepod.Spec.Members {
+		emails[member.User] = member.Email
+	}
+	for _, user := range i.client.SitepodUsers().List() {
+		emails[user.Name] = user.Spec.Email
+	}
+
+	members := []MemberEntry{}
+	for _, user := range sitepod.MemberUsers() {
+		members = append(members, MemberEntry{Email: emails[user], Role: sitepod.RoleOf(user)})
+	}
+	resp.WriteHeaderAndEntity(200, members)
+}
+
+// SetMembers replaces the members of a sitepod, the owner is unchanged and may not be
+// listed. Members are given by email and are accepted alike whether or not a sitepod
+// user with the email exists, so owners can not probe for accounts; the role applies
+// once the user does exist.
+func (i *WebApi) SetMembers(req *restful.Request, resp *restful.Response) {
+
+	sitepod, ok := i.authorizedSitepod(req, resp, PermissionManageSitepod)
+	if !ok {
+		return
+	}
+
+	entity := &SetMembersRequest{}
+	if err := req.ReadEntity(entity); err != nil {
+		resp.WriteHeaderAndEntity(400, NewAPIError("Invalid Payload"))
+		return
+	}
+
+	if !checkResourceVersion(resp, entity.ResourceVersion, sitepod.ResourceVersion) {
+		return
+	}
+
+	members := []v1.SitepodMember{}
+	for _, member := range entity.Members {
+		email := strings.ToLower(strings.TrimSpace(member.Email))
+		if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+			writeInvalid(resp, fmt.Errorf("member email %q is not valid", member.Email))
+			return
+		}
+		user := "sitepod-user-" + GetMD5Hash(email)
+		members = append(members, v1.SitepodMember{User: user, Role: member.Role, Email: email})
+	}
+
+	updated := i.client.Sitepods().CloneItem(sitepod)
+	updated.Spec.Members = members
+
+	if err := updated.ValidateMembers(); err != nil {
+		writeInvalid(resp, err)
+		return
+	}
+
+	updated, err := i.client.Sitepods().TryUpdate(updated)
+	if err != nil {
+		writeClientError(resp, err)
+		return
+	}
+	resp.WriteHeaderAndEntity(200, updated)
+}
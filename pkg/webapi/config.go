@@ -0,0 +1,247 @@
+package webapi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+	"sitepod.io/sitepod/pkg/client"
+	"sitepod.io/sitepod/pkg/mail"
+)
+
+const (
+	SessionStoreFilesystem = "filesystem"
+	SessionStoreMemory     = "memory"
+	// SessionStoreShared keeps sessions as web session resources so they are shared by
+	// all web api instances
+	SessionStoreShared = "shared"
+
+	// SessionKeysSecretKey is the entry of the session keys secret holding the keys
+	SessionKeysSecretKey = "session-keys"
+	// SessionKeyLength is a 32 byte HMAC key followed by a 32 byte AES-256 key
+	SessionKeyLength = 64
+
+	DefaultBindAddress   = ":8081"
+	DefaultSessionMaxAge = 86400 // one day
+
+	MailTransportSMTP = "smtp"
+	MailTransportFile = "file"
+	// MailTransportLog logs mails including their links, for development only
+	MailTransportLog = "log"
+
+	DefaultMailFrom = "sitepod@localhost"
+)
+
+type Config struct {
+	BindAddress string
+	// TLSCertFile and TLSKeyFile serve https when both are set
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// SessionStore is filesystem, memory or shared
+	SessionStore string
+	// SessionDir is where the filesystem store writes, blank for the os temp dir
+	SessionDir string
+	// SessionKeysFile and SessionKeysSecret name where the session keys are loaded from,
+	// the secret takes precedence. Without either a random key is used and sessions do not
+	// survive a restart.
+	SessionKeysFile   string
+	SessionKeysSecret string
+	SessionMaxAge     int
+
+	// UIDir is the frontend build served under /ui, blank for the embedded ui if any
+	UIDir string
+
+	// SecureCookies for when TLS is terminated in front of the web api, implied when the
+	// web api serves TLS itself
+	SecureCookies bool
+
+	// PublicURL is where users reach the panel, for links in mails. It is never taken
+	// from request headers, which a client could use to send a reset link elsewhere.
+	PublicURL string
+
+	// MailTransport is smtp, file or log
+	MailTransport string
+	MailFrom      string
+	SMTPAddress   string
+	SMTPUsername  string
+	// SMTPPasswordFile holds the smtp password so it stays off the command line
+	SMTPPasswordFile string
+	// MailDir is where the file transport writes messages
+	MailDir string
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		BindAddress:   DefaultBindAddress,
+		SessionStore:  SessionStoreFilesystem,
+		SessionMaxAge: DefaultSessionMaxAge,
+		MailTransport: MailTransportLog,
+		MailFrom:      DefaultMailFrom,
+	}
+}
+
+func (c *Config) ServesTLS() bool {
+	return len(c.TLSCertFile) > 0 && len(c.TLSKeyFile) > 0
+}
+
+func (c *Config) Validate() error {
+	if (len(c.TLSCertFile) > 0) != (len(c.TLSKeyFile) > 0) {
+		return fmt.Errorf("Both a TLS certificate and key are required")
+	}
+	switch c.SessionStore {
+	case SessionStoreFilesystem, SessionStoreMemory, SessionStoreShared:
+	default:
+		return fmt.Errorf("Unknown session store %s", c.SessionStore)
+	}
+	if c.SessionStore == SessionStoreShared && len(c.SessionKeysFile) == 0 && len(c.SessionKeysSecret) == 0 {
+		return fmt.Errorf("The shared session store needs session keys common to all instances")
+	}
+	switch c.MailTransport {
+	case MailTransportSMTP:
+		if len(c.SMTPAddress) == 0 || len(c.PublicURL) == 0 {
+			return fmt.Errorf("Mail by smtp needs an smtp address and the public url of the panel")
+		}
+	case MailTransportFile:
+		if len(c.MailDir) == 0 {
+			return fmt.Errorf("Mail to files needs a mail directory")
+		}
+	case MailTransportLog:
+	default:
+		return fmt.Errorf("Unknown mail transport %s", c.MailTransport)
+	}
+	return nil
+}
+
+// baseURL is the public url without trailing slash, defaulting to the bind address for
+// development
+func (c *Config) baseURL() string {
+	if len(c.PublicURL) > 0 {
+		return strings.TrimRight(c.PublicURL, "/")
+	}
+	scheme := "http"
+	if c.ServesTLS() {
+		scheme = "https"
+	}
+	host := c.BindAddress
+	if strings.HasPrefix(host, ":") {
+		host = "localhost" + host
+	}
+	return scheme + "://" + host
+}
+
+func newMailer(c *Config) (mail.Mailer, error) {
+	switch c.MailTransport {
+	case MailTransportSMTP:
+		var password string
+		if len(c.SMTPPasswordFile) > 0 {
+			raw, err := ioutil.ReadFile(c.SMTPPasswordFile)
+			if err != nil {
+				return nil, fmt.Errorf("Unable to read smtp password file: %v", err)
+			}
+			password = strings.TrimSpace(string(raw))
+		}
+		return mail.NewSMTPMailer(c.SMTPAddress, c.MailFrom, c.SMTPUsername, password), nil
+	case MailTransportFile:
+		return mail.NewFileMailer(c.MailDir, c.MailFrom), nil
+	}
+	glog.Warningf("Logging mails instead of sending them, reset links will be in the log")
+	return mail.NewLogMailer(c.MailFrom), nil
+}
+
+func (c *Config) cookieOptions() *sessions.Options {
+	return &sessions.Options{
+		Path:     "/",
+		MaxAge:   c.SessionMaxAge,
+		Secure:   c.SecureCookies || c.ServesTLS(),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// LoadSessionKeys reads the session keys as gorilla key pairs. Keys are base64, one per
+// line, newest first: the first key signs and encrypts new cookies and the rest are only
+// tried when decoding, so a key is rotated by adding a new first line and dropping the
+// last once sessions made with it have expired.
+func LoadSessionKeys(config *Config, cc *client.Client) ([][]byte, error) {
+
+	var raw []byte
+	var source string
+
+	if len(config.SessionKeysSecret) > 0 {
+		secret, err := cc.Secrets().FetchByName(config.SessionKeysSecret)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to get session keys secret %s: %v", config.SessionKeysSecret, err)
+		}
+		raw = secret.Data[SessionKeysSecretKey]
+		source = "secret " + config.SessionKeysSecret
+	} else if len(config.SessionKeysFile) > 0 {
+		var err error
+		raw, err = ioutil.ReadFile(config.SessionKeysFile)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read session keys file: %v", err)
+		}
+		source = config.SessionKeysFile
+	} else {
+		glog.Warningf("No session keys configured, using a random key, sessions will not survive a restart")
+		key := securecookie.GenerateRandomKey(SessionKeyLength)
+		return [][]byte{key[:32], key[32:]}, nil
+	}
+
+	keyPairs, err := parseSessionKeys(raw)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid session keys in %s: %v", source, err)
+	}
+	glog.Infof("Loaded %d session keys from %s", len(keyPairs)/2, source)
+	return keyPairs, nil
+}
+
+func parseSessionKeys(raw []byte) ([][]byte, error) {
+
+	var keyPairs [][]byte
+	for i, line := range bytes.Split(raw, []byte("\n")) {
+		text := strings.TrimSpace(string(line))
+		if len(text) == 0 || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, err := base64.StdEncoding.DecodeString(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d is not base64", i+1)
+		}
+		if len(key) != SessionKeyLength {
+			return nil, fmt.Errorf("line %d is %d bytes, expected %d", i+1, len(key), SessionKeyLength)
+		}
+		keyPairs = append(keyPairs, key[:32], key[32:])
+	}
+
+	if len(keyPairs) == 0 {
+		return nil, fmt.Errorf("no keys")
+	}
+	return keyPairs, nil
+}
+
+// GenerateSessionKey is a new random session key in the session keys format
+func GenerateSessionKey() string {
+	return base64.StdEncoding.EncodeToString(securecookie.GenerateRandomKey(SessionKeyLength))
+}
+
+func newSessionStore(config *Config, cc *client.Client, keyPairs [][]byte) sessions.Store {
+
+	switch config.SessionStore {
+	case SessionStoreMemory:
+		return newBackendStore(newMemorySessionBackend(), config.cookieOptions(), keyPairs)
+	case SessionStoreShared:
+		return newBackendStore(&sharedSessionBackend{cc}, config.cookieOptions(), keyPairs)
+	}
+
+	fileStore := sessions.NewFilesystemStore(config.SessionDir, keyPairs...)
+	fileStore.Options = config.cookieOptions()
+	fileStore.MaxAge(config.SessionMaxAge)
+	return fileStore
+}
@@ -2,12 +2,23 @@ package webapi
 
 import (
 	"time"
+
+	"sitepod.io/sitepod/pkg/api/v1"
 )
 
 type SitepodSession struct {
 	LastSeen      *time.Time `json:"last_seen,omitempty"`
 	MaxAge        int        `json:"max_age,omitempty"`
 	Authenticated bool       `json:"authenticated?"`
+	Email         string     `json:"email,omitempty"`
+	// TwoFactorRequired means the password was right and POST /api/login/2fa must
+	// follow to authenticate the session
+	TwoFactorRequired bool `json:"two_factor_required,omitempty"`
+	// PasswordVerifiedAt is when the first login step passed, the second must follow
+	// within TwoFactorLoginTimeout
+	PasswordVerifiedAt *time.Time `json:"-"`
+	// Generation is the user's session generation at login, see SitepodUser.RevokeSessions
+	Generation int64 `json:"-"`
 }
 
 type LoginRequest struct {
@@ -18,6 +29,104 @@ type LoginRequest struct {
 	} `json:"data"`
 }
 
+type SetPasswordRequest struct {
+	Action string `json:"action"`
+	Data   struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	} `json:"data"`
+}
+
+type RequestPasswordResetRequest struct {
+	Action string `json:"action"`
+	Data   struct {
+		Email string `json:"email"`
+	} `json:"data"`
+}
+
+// ResetPasswordRequest sets a new password with the token of a reset mail
+type ResetPasswordRequest struct {
+	Action string `json:"action"`
+	Data   struct {
+		Token       string `json:"token"`
+		NewPassword string `json:"new_password"`
+	} `json:"data"`
+}
+
+type VerifyEmailRequest struct {
+	Action string `json:"action"`
+	Data   struct {
+		Token string `json:"token"`
+	} `json:"data"`
+}
+
+// TwoFactorCodeRequest carries an authenticator or recovery code
+type TwoFactorCodeRequest struct {
+	Action string `json:"action"`
+	Data   struct {
+		Code string `json:"code"`
+	} `json:"data"`
+}
+
+type DisableTwoFactorRequest struct {
+	Action string `json:"action"`
+	Data   struct {
+		Password string `json:"password"`
+		Code     string `json:"code"`
+	} `json:"data"`
+}
+
+type TwoFactorStatus struct {
+	Enabled           bool `json:"enabled"`
+	RecoveryCodesLeft int  `json:"recovery_codes_left"`
+}
+
+// TOTPEnrolment is shown as a QR code of the URI, with the secret for manual entry
+type TOTPEnrolment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// RecoveryCodes are shown once, only hashes are kept
+type RecoveryCodes struct {
+	Codes []string `json:"codes"`
+}
+
+// Resource requests create or replace the spec of a resource, status and labels are
+// managed by sitepod. ResourceVersion, when given, must match for a replace to succeed.
+
+type SitepodRequest struct {
+	Name            string         `json:"name,omitempty"`
+	ResourceVersion string         `json:"resourceVersion,omitempty"`
+	Spec            v1.SitepodSpec `json:"spec"`
+}
+
+type WebsiteRequest struct {
+	ResourceVersion string         `json:"resourceVersion,omitempty"`
+	Spec            v1.WebsiteSpec `json:"spec"`
+}
+
+type SystemUserRequest struct {
+	ResourceVersion string            `json:"resourceVersion,omitempty"`
+	Spec            v1.SystemUserSpec `json:"spec"`
+}
+
+type AppComponentRequest struct {
+	ResourceVersion string              `json:"resourceVersion,omitempty"`
+	Spec            v1.AppComponentSpec `json:"spec"`
+}
+
+// MemberEntry is a sitepod user with a role on a sitepod
+type MemberEntry struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type SetMembersRequest struct {
+	ResourceVersion string        `json:"resourceVersion,omitempty"`
+	Members         []MemberEntry `json:"members"`
+}
+
 type APIError struct {
 	Reason  string `json:"reason"`
 	Message string `json:"message"`
@@ -0,0 +1,226 @@
+package webapi
+
+// Live resource events as Server-Sent Events. Informer handlers publish every change of
+// sitepods, websites, pod tasks and system users to an event hub which forwards each to
+// the streams of users with a role on the sitepod concerned. Streams re-check the caller
+// on every keep-alive. A stream that falls too far behind is closed rather than holding
+// up the informers, the browser then reconnects and reloads.
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/golang/glog"
+	"k8s.io/kubernetes/pkg/api/meta"
+	"k8s.io/kubernetes/pkg/client/cache"
+	"k8s.io/kubernetes/pkg/controller/framework"
+	"sitepod.io/sitepod/pkg/api/v1"
+)
+
+const (
+	EventAdded   = "added"
+	EventUpdated = "updated"
+	EventDeleted = "deleted"
+)
+
+var (
+	// EventStreamBuffer is how many events a stream may fall behind before it is closed
+	EventStreamBuffer = 64
+	// EventStreamKeepAlive is how often an idle stream is sent a comment so proxies keep
+	// the connection open
+	EventStreamKeepAlive = 30 * time.Second
+)
+
+type ResourceEvent struct {
+	Type    string      `json:"type"`
+	Kind    string      `json:"kind"`
+	Sitepod string      `json:"sitepod"`
+	Object  interface{} `json:"object"`
+}
+
+type eventSubscriber struct {
+	user   string
+	events chan *ResourceEvent
+}
+
+type eventHub struct {
+	mutex       sync.Mutex
+	subscribers map[*eventSubscriber]bool
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subscribers: make(map[*eventSubscriber]bool)}
+}
+
+func (h *eventHub) Subscribe(user string) *eventSubscriber {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	subscriber := &eventSubscriber{user, make(chan *ResourceEvent, EventStreamBuffer)}
+	h.subscribers[subscriber] = true
+	return subscriber
+}
+
+func (h *eventHub) Unsubscribe(subscriber *eventSubscriber) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.subscribers[subscriber] {
+		delete(h.subscribers, subscriber)
+		close(subscriber.events)
+	}
+}
+
+// Publish sends the event to subscribers with the permission on the sitepod, it never
+// blocks
+func (h *eventHub) Publish(event *ResourceEvent, sitepod *v1.Sitepod, permission Permission) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for subscriber := range h.subscribers {
+		if !RoleHasPermission(sitepod.RoleOf(subscriber.user), permission) {
+			continue
+		}
+		select {
+		case subscriber.events <- event:
+		default:
+			glog.Warningf("Closing event stream of %s, too far behind", subscriber.user)
+			delete(h.subscribers, subscriber)
+			close(subscriber.events)
+		}
+	}
+}
+
+// watchResources attaches the event hub to the informers
+func (i *WebApi) watchResources() {
+
+	i.client.Sitepods().AddInformerHandlers(i.eventHandlers("Sitepod"))
+	i.client.Websites().AddInformerHandlers(i.eventHandlers("Website"))
+	i.client.PodTasks().AddInformerHandlers(i.eventHandlers("PodTask"))
+	i.client.SystemUsers().AddInformerHandlers(i.eventHandlers("SystemUser"))
+}
+
+func (i *WebApi) eventHandlers(kind string) framework.ResourceEventHandlerFuncs {
+	return framework.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			i.publish(EventAdded, kind, obj)
+		},
+		UpdateFunc: func(old interface{}, cur interface{}) {
+			i.publish(EventUpdated, kind, cur)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			i.publish(EventDeleted, kind, obj)
+		},
+	}
+}
+
+func (i *WebApi) publish(eventType string, kind string, obj interface{}) {
+
+	if user, ok := obj.(*v1.SystemUser); ok {
+		obj = withoutPassword(user)
+	}
+
+	if sitepod, ok := obj.(*v1.Sitepod); ok {
+		event := &ResourceEvent{eventType, kind, sitepod.Name, obj}
+		i.events.Publish(event, sitepod, PermissionViewSitepod)
+		return
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		glog.Warningf("Unexpected %s event object %+v", kind, obj)
+		return
+	}
+
+	// resources of a sitepod deleted along with it can no longer be authorized
+	sitepod, exists := i.client.Sitepods().MaybeSingleByUID(accessor.GetLabels()["sitepod"])
+	if !exists {
+		return
+	}
+
+	event := &ResourceEvent{eventType, kind, sitepod.Name, obj}
+	i.events.Publish(event, sitepod, PermissionViewResources)
+}
+
+// Events streams changes to the resources of the user's sitepods until the client goes
+// away, the session expires or the caller is no longer authorized, see streamAuthorized
+func (i *WebApi) Events(req *restful.Request, resp *restful.Response) {
+
+	if !requireScope(req, resp, v1.ScopeRead) {
+		return
+	}
+
+	flusher, ok := resp.ResponseWriter.(http.Flusher)
+	if !ok {
+		resp.WriteHeaderAndEntity(500, NewAPIError("streaming not supported"))
+		return
+	}
+
+	subscriber := i.events.Subscribe(sitepodUserName(req))
+	defer i.events.Unsubscribe(subscriber)
+
+	resp.Header().Set("Content-Type", "text/event-stream")
+	resp.Header().Set("Cache-Control", "no-cache")
+	resp.Header().Set("X-Accel-Buffering", "no")
+	resp.WriteHeader(200)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(EventStreamKeepAlive)
+	defer keepAlive.Stop()
+	expired := time.After(time.Duration(i.config.SessionMaxAge) * time.Second)
+
+	for {
+		select {
+		case event, open := <-subscriber.events:
+			if !open {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				glog.Errorf("Unable to marshal %s event: %v", event.Kind, err)
+				continue
+			}
+			if _, err = fmt.Fprintf(resp, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if !i.streamAuthorized(req) {
+				return
+			}
+			if _, err := fmt.Fprint(resp, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case <-expired:
+			return
+		case <-req.Request.Context().Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// streamAuthorized re-checks the caller of a stream on each keep-alive, so a logout,
+// password change or reset, or a revoked or expired api token ends it. The session is
+// loaded afresh from the store rather than from the request's session registry.
+func (i *WebApi) streamAuthorized(req *restful.Request) bool {
+
+	if name, isToken := req.Attribute("token").(string); isToken {
+		token, err := i.client.ApiTokens().FetchByName(name)
+		if err != nil || token.IsExpired(time.Now()) {
+			return false
+		}
+		_, err = i.client.SitepodUsers().FetchByName(token.Spec.User)
+		return err == nil
+	}
+
+	session, err := i.sessionStore.New(req.Request, "sitepodfe")
+	if err != nil || session.IsNew {
+		return false
+	}
+	sitepodSession := sessionOf(session)
+	return sitepodSession != nil && sitepodSession.Authenticated && i.sessionCurrent(sitepodSession)
+}
@@ -0,0 +1,112 @@
+package webapi
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	// MaxLoginAttemptsPerWindow from one client address, successful or not
+	MaxLoginAttemptsPerWindow = 20
+	LoginAttemptWindow        = time.Minute
+
+	// MaxLoginFailures in a row before an account is locked
+	MaxLoginFailures = 5
+	LoginLockout     = 15 * time.Minute
+)
+
+type loginAttempts struct {
+	count       int
+	windowStart time.Time
+}
+
+type loginFailures struct {
+	count       int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// loginLimiter rate limits login attempts by client address and locks accounts after
+// repeated failures. State is in memory so it is per web api instance and lost on restart.
+type loginLimiter struct {
+	mutex    sync.Mutex
+	attempts map[string]*loginAttempts
+	failures map[string]*loginFailures
+	now      func() time.Time
+}
+
+func newLoginLimiter() *loginLimiter {
+	return &loginLimiter{
+		attempts: make(map[string]*loginAttempts),
+		failures: make(map[string]*loginFailures),
+		now:      time.Now,
+	}
+}
+
+// Allow records an attempt from addr for account, if refused the duration is how long
+// until a retry may succeed
+func (l *loginLimiter) Allow(addr string, account string) (bool, time.Duration) {
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	l.expire(now)
+
+	if f, exists := l.failures[account]; exists && now.Before(f.lockedUntil) {
+		return false, f.lockedUntil.Sub(now)
+	}
+
+	a, exists := l.attempts[addr]
+	if !exists {
+		a = &loginAttempts{windowStart: now}
+		l.attempts[addr] = a
+	}
+
+	if a.count >= MaxLoginAttemptsPerWindow {
+		return false, a.windowStart.Add(LoginAttemptWindow).Sub(now)
+	}
+
+	a.count++
+	return true, 0
+}
+
+// Failed counts a failed login against the account, locking it at MaxLoginFailures
+func (l *loginLimiter) Failed(account string) {
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	f, exists := l.failures[account]
+	if !exists {
+		f = &loginFailures{}
+		l.failures[account] = f
+	}
+
+	f.count++
+	f.lastFailure = l.now()
+	if f.count >= MaxLoginFailures {
+		f.lockedUntil = l.now().Add(LoginLockout)
+		f.count = 0
+	}
+}
+
+func (l *loginLimiter) Succeeded(account string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	delete(l.failures, account)
+}
+
+func (l *loginLimiter) expire(now time.Time) {
+	for addr, a := range l.attempts {
+		if now.Sub(a.windowStart) >= LoginAttemptWindow {
+			delete(l.attempts, addr)
+		}
+	}
+	for account, f := range l.failures {
+		// failures are forgotten a lockout period after the last one
+		if !now.Before(f.lockedUntil) && now.Sub(f.lastFailure) >= LoginLockout {
+			delete(l.failures, account)
+		}
+	}
+}
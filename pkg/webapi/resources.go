@@ -0,0 +1,725 @@
+package webapi
+
+// REST endpoints for the sitepods of the logged in user and their websites, system users
+// and app components. Reads are served from the informer caches, writes go straight to
+// the api server so a read just after a write may briefly see the previous state.
+// Access is by the user's role on the sitepod, see authz.go.
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/emicklei/go-restful"
+	"github.com/golang/glog"
+	kerrors "k8s.io/kubernetes/pkg/api/errors"
+	"sitepod.io/sitepod/pkg/api/v1"
+	"sitepod.io/sitepod/pkg/specgen"
+)
+
+var resourceNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+func (i *WebApi) addResourceRoutes(ws *restful.WebService) {
+
+	ws.Route(documentErrors(ws.GET("/sitepods").To(i.ListSitepods).
+		Doc("List the sitepods the user has a role on").
+		Metadata(tags(tagSitepods)).
+		Writes([]v1.Sitepod{}).
+		Returns(200, "OK", []v1.Sitepod{})))
+
+	ws.Route(documentErrors(ws.POST("/sitepods").To(i.CreateSitepod).
+		Doc("Create a sitepod owned by the user").
+		Metadata(tags(tagSitepods)).
+		Reads(SitepodRequest{}).
+		Writes(v1.Sitepod{}).
+		Returns(201, "Created", v1.Sitepod{}).
+		Returns(409, "already exists", APIError{}).
+		Returns(422, "invalid", APIError{})))
+
+	ws.Route(documentErrors(ws.GET("/sitepods/{sitepod}").To(i.GetSitepod).
+		Doc("Get a sitepod").
+		Metadata(tags(tagSitepods)).
+		Param(sitepodParam(ws)).
+		Writes(v1.Sitepod{}).
+		Returns(200, "OK", v1.Sitepod{})))
+
+	ws.Route(documentErrors(ws.PUT("/sitepods/{sitepod}").To(i.UpdateSitepod).
+		Doc("Change the display name and description of a sitepod").
+		Metadata(tags(tagSitepods)).
+		Param(sitepodParam(ws)).
+		Reads(SitepodRequest{}).
+		Writes(v1.Sitepod{}).
+		Returns(200, "OK", v1.Sitepod{}).
+		Returns(409, "changed since read", APIError{})))
+
+	ws.Route(documentErrors(ws.DELETE("/sitepods/{sitepod}").To(i.DeleteSitepod).
+		Doc("Delete a sitepod").
+		Metadata(tags(tagSitepods)).
+		Param(sitepodParam(ws)).
+		Returns(200, "OK", nil)))
+
+	addChildRoutes(ws, childRoutes{"websites", "website", WebsiteRequest{}, v1.Website{}, []v1.Website{},
+		i.ListWebsites, i.CreateWebsite, i.GetWebsite, i.UpdateWebsite, i.DeleteWebsite})
+	addChildRoutes(ws, childRoutes{"systemusers", "system user", SystemUserRequest{}, v1.SystemUser{}, []v1.SystemUser{},
+		i.ListSystemUsers, i.CreateSystemUser, i.GetSystemUser, i.UpdateSystemUser, i.DeleteSystemUser})
+	addChildRoutes(ws, childRoutes{"appcomponents", "app component", AppComponentRequest{}, v1.Appcomponent{}, []v1.Appcomponent{},
+		i.ListAppComponents, i.CreateAppComponent, i.GetAppComponent, i.UpdateAppComponent, i.DeleteAppComponent})
+}
+
+// childRoutes are the routes of a resource kind within a sitepod
+type childRoutes struct {
+	path    string
+	kind    string
+	request interface{}
+	model   interface{}
+	list    interface{}
+
+	listFunc, createFunc, getFunc, updateFunc, deleteFunc restful.RouteFunction
+}
+
+func addChildRoutes(ws *restful.WebService, r childRoutes) {
+
+	collection := "/sitepods/{sitepod}/" + r.path
+	item := collection + "/{name}"
+
+	ws.Route(documentErrors(ws.GET(collection).To(r.listFunc).
+		Doc("List the "+r.kind+"s of a sitepod").
+		Metadata(tags(r.path)).
+		Param(sitepodParam(ws)).
+		Writes(r.list).
+		Returns(200, "OK", r.list)))
+
+	ws.Route(documentErrors(ws.POST(collection).To(r.createFunc).
+		Doc("Create a "+r.kind+" in a sitepod").
+		Metadata(tags(r.path)).
+		Param(sitepodParam(ws)).
+		Reads(r.request).
+		Writes(r.model).
+		Returns(201, "Created", r.model).
+		Returns(422, "invalid", APIError{})))
+
+	ws.Route(documentErrors(ws.GET(item).To(r.getFunc).
+		Doc("Get a "+r.kind).
+		Metadata(tags(r.path)).
+		Param(sitepodParam(ws)).
+		Param(nameParam(ws, r.kind)).
+		Writes(r.model).
+		Returns(200, "OK", r.model)))
+
+	ws.Route(documentErrors(ws.PUT(item).To(r.updateFunc).
+		Doc("Replace the spec of a "+r.kind).
+		Metadata(tags(r.path)).
+		Param(sitepodParam(ws)).
+		Param(nameParam(ws, r.kind)).
+		Reads(r.request).
+		Writes(r.model).
+		Returns(200, "OK", r.model).
+		Returns(409, "changed since read", APIError{}).
+		Returns(422, "invalid", APIError{})))
+
+	ws.Route(documentErrors(ws.DELETE(item).To(r.deleteFunc).
+		Doc("Delete a "+r.kind).
+		Metadata(tags(r.path)).
+		Param(sitepodParam(ws)).
+		Param(nameParam(ws, r.kind)).
+		Returns(200, "OK", nil)))
+}
+
+// sitepodUserName is the sitepod user resource name of the session
+func sitepodUserName(req *restful.Request) string {
+	sitepodSession, _ := req.Attribute("session").(*SitepodSession)
+	if sitepodSession == nil || len(sitepodSession.Email) == 0 {
+		return ""
+	}
+	return "sitepod-user-" + GetMD5Hash(sitepodSession.Email)
+}
+
+// writeClientError maps an api server error to a response
+func writeClientError(resp *restful.Response, err error) {
+	switch {
+	case kerrors.IsNotFound(err):
+		resp.WriteHeaderAndEntity(404, NewAPIError("not found"))
+	case kerrors.IsConflict(err):
+		resp.WriteHeaderAndEntity(409, NewAPIError("changed since read, try again"))
+	case kerrors.IsAlreadyExists(err):
+		resp.WriteHeaderAndEntity(409, NewAPIError("already exists"))
+	case kerrors.IsInvalid(err) || kerrors.IsBadRequest(err):
+		resp.WriteHeaderAndEntity(422, NewAPIError(err.Error()))
+	default:
+		glog.Errorf("Web api request failed: %v", err)
+		resp.WriteHeaderAndEntity(500, NewAPIError("internal error"))
+	}
+}
+
+func writeInvalid(resp *restful.Response, err error) {
+	resp.WriteHeaderAndEntity(422, NewAPIError(err.Error()))
+}
+
+// checkResourceVersion rejects a replace based on a stale read
+func checkResourceVersion(resp *restful.Response, requested string, current string) bool {
+	if len(requested) > 0 && requested != current {
+		resp.WriteHeaderAndEntity(409, NewAPIError("changed since read, try again"))
+		return false
+	}
+	return true
+}
+
+func (i *WebApi) ListSitepods(req *restful.Request, resp *restful.Response) {
+	if !requireScope(req, resp, v1.ScopeRead) {
+		return
+	}
+	sitepods := append([]*v1.Sitepod{}, i.client.Sitepods().ByMember(sitepodUserName(req))...)
+	resp.WriteHeaderAndEntity(200, sitepods)
+}
+
+func (i *WebApi) GetSitepod(req *restful.Request, resp *restful.Response) {
+	if sitepod, ok := i.authorizedSitepod(req, resp, PermissionViewSitepod); ok {
+		resp.WriteHeaderAndEntity(200, sitepod)
+	}
+}
+
+// CreateSitepod makes the user the owner, members are added afterwards and volume
+// claims are assigned by an operator
+func (i *WebApi) CreateSitepod(req *restful.Request, resp *restful.Response) {
+
+	if !requireScope(req, resp, v1.ScopeWrite) {
+		return
+	}
+
+	entity := &SitepodRequest{}
+	if err := req.ReadEntity(entity); err != nil {
+		resp.WriteHeaderAndEntity(400, NewAPIError("Invalid Payload"))
+		return
+	}
+
+	if len(entity.Name) > 0 && (len(entity.Name) > 63 || !resourceNamePattern.MatchString(entity.Name)) {
+		writeInvalid(resp, fmt.Errorf("name must be lower case letters, digits and dashes"))
+		return
+	}
+
+	sitepod := i.client.Sitepods().NewEmpty()
+	if len(entity.Name) > 0 {
+		sitepod.GenerateName = ""
+		sitepod.Name = entity.Name
+	}
+	sitepod.Spec = entity.Spec
+	sitepod.Spec.VolumeClaims = nil
+	sitepod.Spec.Owner = sitepodUserName(req)
+	sitepod.Spec.Members = nil
+
+	sitepod, err := i.client.Sitepods().TryAdd(sitepod)
+	if err != nil {
+		writeClientError(resp, err)
+		return
+	}
+	resp.WriteHeaderAndEntity(201, sitepod)
+}
+
+func (i *WebApi) UpdateSitepod(req *restful.Request, resp *restful.Response) {
+
+	sitepod, ok := i.authorizedSitepod(req, resp, PermissionManageSitepod)
+	if !ok {
+		return
+	}
+
+	entity := &SitepodRequest{}
+	if err := req.ReadEntity(entity); err != nil {
+		resp.WriteHeaderAndEntity(400, NewAPIError("Invalid Payload"))
+		return
+	}
+
+	if !checkResourceVersion(resp, entity.ResourceVersion, sitepod.ResourceVersion) {
+		return
+	}
+
+	updated := i.client.Sitepods().CloneItem(sitepod)
+	updated.Spec.DisplayName = entity.Spec.DisplayName
+	updated.Spec.Description = entity.Spec.Description
+
+	updated, err := i.client.Sitepods().TryUpdate(updated)
+	if err != nil {
+		writeClientError(resp, err)
+		return
+	}
+	resp.WriteHeaderAndEntity(200, updated)
+}
+
+func (i *WebApi) DeleteSitepod(req *restful.Request, resp *restful.Response) {
+	sitepod, ok := i.authorizedSitepod(req, resp, PermissionManageSitepod)
+	if !ok {
+		return
+	}
+	if err := i.client.Sitepods().TryDelete(sitepod); err != nil {
+		writeClientError(resp, err)
+		return
+	}
+	resp.WriteHeaderAndEntity(200, struct{}{})
+}
+
+// validateWebsite checks the domain is a hostname and unique among the websites of all
+// sitepods
+func validateWebsite(website *v1.Website, others []*v1.Website) error {
+	if len(website.GetPrimaryDomain()) == 0 {
+		return fmt.Errorf("domain is required")
+	}
+	if !website.HasValidDomain() {
+		return fmt.Errorf("domain must be a lowercase hostname")
+	}
+	for _, other := range others {
+		if other.Name != website.Name && other.GetPrimaryDomain() == website.GetPrimaryDomain() {
+			return fmt.Errorf("domain %s is already in use", website.GetPrimaryDomain())
+		}
+	}
+	return nil
+}
+
+func (i *WebApi) sitepodWebsite(req *restful.Request, resp *restful.Response, permission Permission) (*v1.Sitepod, *v1.Website, bool) {
+	sitepod, ok := i.authorizedSitepod(req, resp, permission)
+	if !ok {
+		return nil, nil, false
+	}
+	name := req.PathParameter("name")
+	for _, website := range i.client.Websites().BySitepodKey(string(sitepod.UID)) {
+		if website.Name == name {
+			return sitepod, website, true
+		}
+	}
+	resp.WriteHeaderAndEntity(404, NewAPIError("website not found"))
+	return nil, nil, false
+}
+
+func (i *WebApi) ListWebsites(req *restful.Request, resp *restful.Response) {
+	if sitepod, ok := i.authorizedSitepod(req, resp, PermissionViewResources); ok {
+		websites := append([]*v1.Website{}, i.client.Websites().BySitepodKey(string(sitepod.UID))...)
+		resp.WriteHeaderAndEntity(200, websites)
+	}
+}
+
+func (i *WebApi) GetWebsite(req *restful.Request, resp *restful.Response) {
+	if _, website, ok := i.sitepodWebsite(req, resp, PermissionViewResources); ok {
+		resp.WriteHeaderAndEntity(200, website)
+	}
+}
+
+func (i *WebApi) CreateWebsite(req *restful.Request, resp *restful.Response) {
+
+	sitepod, ok := i.authorizedSitepod(req, resp, PermissionEditResources)
+	if !ok {
+		return
+	}
+
+	entity := &WebsiteRequest{}
+	if err := req.ReadEntity(entity); err != nil {
+		resp.WriteHeaderAndEntity(400, NewAPIError("Invalid Payload"))
+		return
+	}
+
+	website := i.client.Websites().NewEmpty()
+	website.Labels["sitepod"] = string(sitepod.UID)
+	website.Spec = entity.Spec
+
+	if err := validateWebsite(website, i.client.Websites().List()); err != nil {
+		writeInvalid(resp, err)
+		return
+	}
+
+	website, err := i.client.Websites().TryAdd(website)
+	if err != nil {
+		writeClientError(resp, err)
+		return
+	}
+	resp.WriteHeaderAndEntity(201, website)
+}
+
+func (i *WebApi) UpdateWebsite(req *restful.Request, resp *restful.Response) {
+
+	_, website, ok := i.sitepodWebsite(req, resp, PermissionEditResources)
+	if !ok {
+		return
+	}
+
+	entity := &WebsiteRequest{}
+	if err := req.ReadEntity(entity); err != nil {
+		resp.WriteHeaderAndEntity(400, NewAPIError("Invalid Payload"))
+		return
+	}
+
+	if !checkResourceVersion(resp, entity.ResourceVersion, website.ResourceVersion) {
+		return
+	}
+
+	updated := i.client.Websites().CloneItem(website)
+	updated.Spec = entity.Spec
+
+	if err := validateWebsite(updated, i.client.Websites().List()); err != nil {
+		writeInvalid(resp, err)
+		return
+	}
+
+	updated, err := i.client.Websites().TryUpdate(updated)
+	if err != nil {
+		writeClientError(resp, err)
+		return
+	}
+	resp.WriteHeaderAndEntity(200, updated)
+}
+
+func (i *WebApi) DeleteWebsite(req *restful.Request, resp *restful.Response) {
+	_, website, ok := i.sitepodWebsite(req, resp, PermissionEditResources)
+	if !ok {
+		return
+	}
+	if err := i.client.Websites().TryDelete(website); err != nil {
+		writeClientError(resp, err)
+		return
+	}
+	resp.WriteHeaderAndEntity(200, struct{}{})
+}
+
+func validateSystemUser(user *v1.SystemUser, others []*v1.SystemUser) error {
+	if v1.ReservedNames[user.GetUsername()] {
+		return fmt.Errorf("username %s is reserved", user.GetUsername())
+	}
+	if !user.HasValidUsername() {
+		return fmt.Errorf("username %s is not valid", user.GetUsername())
+	}
+	if err := user.ValidateAuthorizedKeys(); err != nil {
+		return err
+	}
+	if err := user.ValidateAccess(); err != nil {
+		return err
+	}
+	if err := user.ValidateShell(); err != nil {
+		return err
+	}
+	if err := user.ValidateHomeDeletionPolicy(); err != nil {
+		return err
+	}
+	if err := user.ValidateAging(); err != nil {
+		return err
+	}
+	for _, other := range others {
+		if other.Name != user.Name && other.GetUsername() == user.GetUsername() {
+			return fmt.Errorf("username %s is already used", user.GetUsername())
+		}
+	}
+	return nil
+}
+
+// withoutPassword strips the password hash from system users sent to the browser
+func withoutPassword(user *v1.SystemUser) *v1.SystemUser {
+	stripped := *user
+	stripped.Spec.Password = v1.HashedPassword{}
+	return &stripped
+}
+
+func (i *WebApi) sitepodSystemUser(req *restful.Request, resp *restful.Response, permission Permission) (*v1.Sitepod, *v1.SystemUser, bool) {
+	sitepod, ok := i.authorizedSitepod(req, resp, permission)
+	if !ok {
+		return nil, nil, false
+	}
+	name := req.PathParameter("name")
+	for _, user := range i.client.SystemUsers().BySitepodKey(string(sitepod.UID)) {
+		if user.Name == name {
+			return sitepod, user, true
+		}
+	}
+	resp.WriteHeaderAndEntity(404, NewAPIError("system user not found"))
+	return nil, nil, false
+}
+
+func (i *WebApi) ListSystemUsers(req *restful.Request, resp *restful.Response) {
+	if sitepod, ok := i.authorizedSitepod(req, resp, PermissionViewResources); ok {
+		users := []*v1.SystemUser{}
+		for _, user := range i.client.SystemUsers().BySitepodKey(string(sitepod.UID)) {
+			users = append(users, withoutPassword(user))
+		}
+		resp.WriteHeaderAndEntity(200, users)
+	}
+}
+
+func (i *WebApi) GetSystemUser(req *restful.Request, resp *restful.Response) {
+	if _, user, ok := i.sitepodSystemUser(req, resp, PermissionViewResources); ok {
+		resp.WriteHeaderAndEntity(200, withoutPassword(user))
+	}
+}
+
+// CreateSystemUser leaves the password unset, it is not writable through this api
+func (i *WebApi) CreateSystemUser(req *restful.Request, resp *restful.Response) {
+
+	sitepod, ok := i.authorizedSitepod(req, resp, PermissionEditResources)
+	if !ok {
+		return
+	}
+
+	entity := &SystemUserRequest{}
+	if err := req.ReadEntity(entity); err != nil {
+		resp.WriteHeaderAndEntity(400, NewAPIError("Invalid Payload"))
+		return
+	}
+
+	sitepodKey := string(sitepod.UID)
+	user := i.client.SystemUsers().NewEmpty()
+	user.Labels["sitepod"] = sitepodKey
+	user.Spec = entity.Spec
+	user.Spec.Password = v1.HashedPassword{}
+
+	if err := validateSystemUser(user, i.client.SystemUsers().BySitepodKey(sitepodKey)); err != nil {
+		writeInvalid(resp, err)
+		return
+	}
+	user.GenerateName = "systemuser-" + user.GetUsername() + "-"
+
+	user, err := i.client.SystemUsers().TryAdd(user)
+	if err != nil {
+		writeClientError(resp, err)
+		return
+	}
+	resp.WriteHeaderAndEntity(201, withoutPassword(user))
+}
+
+func (i *WebApi) UpdateSystemUser(req *restful.Request, resp *restful.Response) {
+
+	sitepod, user, ok := i.sitepodSystemUser(req, resp, PermissionEditResources)
+	if !ok {
+		return
+	}
+
+	entity := &SystemUserRequest{}
+	if err := req.ReadEntity(entity); err != nil {
+		resp.WriteHeaderAndEntity(400, NewAPIError("Invalid Payload"))
+		return
+	}
+
+	if !checkResourceVersion(resp, entity.ResourceVersion, user.ResourceVersion) {
+		return
+	}
+
+	if entity.Spec.Username != user.Spec.Username {
+		writeInvalid(resp, fmt.Errorf("username can not be changed"))
+		return
+	}
+
+	updated := i.client.SystemUsers().CloneItem(user)
+	password := updated.Spec.Password
+	updated.Spec = entity.Spec
+	updated.Spec.Password = password
+
+	if err := validateSystemUser(updated, i.client.SystemUsers().BySitepodKey(string(sitepod.UID))); err != nil {
+		writeInvalid(resp, err)
+		return
+	}
+
+	updated, err := i.client.SystemUsers().TryUpdate(updated)
+	if err != nil {
+		writeClientError(resp, err)
+		return
+	}
+	resp.WriteHeaderAndEntity(200, withoutPassword(updated))
+}
+
+func (i *WebApi) DeleteSystemUser(req *restful.Request, resp *restful.Response) {
+	_, user, ok := i.sitepodSystemUser(req, resp, PermissionEditResources)
+	if !ok {
+		return
+	}
+	if err := i.client.SystemUsers().TryDelete(user); err != nil {
+		writeClientError(resp, err)
+		return
+	}
+	resp.WriteHeaderAndEntity(200, struct{}{})
+}
+
+// configKeyPattern is a valid config map key, also used for the file name it is mounted as
+var configKeyPattern = regexp.MustCompile(`^[-._a-zA-Z0-9]{1,253}$`)
+
+var fileModePattern = regexp.MustCompile(`^0?[0-7]{3}$`)
+
+// restrictAppComponent sets what web api callers don't choose: the image is that of the
+// type, external exposure stays as it was (or the type default) and generated config
+// files are kept from the existing component rather than taken from the caller
+func restrictAppComponent(ac *v1.Appcomponent, existing *v1.Appcomponent) error {
+
+	componentType, exists := specgen.LookupComponentType(ac.Spec.Type)
+	if !exists {
+		return fmt.Errorf("type %s is not known", ac.Spec.Type)
+	}
+
+	if len(ac.Spec.Image) > 0 && ac.Spec.Image != componentType.Image {
+		return fmt.Errorf("type %s runs image %s", ac.Spec.Type, componentType.Image)
+	}
+	ac.Spec.Image = componentType.Image
+	if len(ac.Spec.ImageVersion) == 0 {
+		ac.Spec.ImageVersion = componentType.Versions[0]
+	}
+	if !componentType.HasVersion(ac.Spec.ImageVersion) {
+		return fmt.Errorf("image version %s of type %s is not available", ac.Spec.ImageVersion, ac.Spec.Type)
+	}
+
+	configFiles := []v1.AppComponentConfigFile{}
+	for _, configFile := range ac.Spec.ConfigFiles {
+		if !specgen.IsGeneratedConfigFile(configFile.Name) {
+			configFiles = append(configFiles, configFile)
+		}
+	}
+
+	if existing == nil {
+		ac.Spec.ExposeExternally = componentType.ExposeExternally
+	} else {
+		ac.Spec.ExposeExternally = existing.Spec.ExposeExternally
+		for _, configFile := range existing.Spec.ConfigFiles {
+			if specgen.IsGeneratedConfigFile(configFile.Name) {
+				configFiles = append(configFiles, configFile)
+			}
+		}
+	}
+	ac.Spec.ConfigFiles = configFiles
+	return nil
+}
+
+// validateAppComponent checks an app component after restrictAppComponent. Config files
+// are mounted from config maps so their directory must not shadow system directories.
+func validateAppComponent(ac *v1.Appcomponent) error {
+	if len(ac.Spec.Type) == 0 {
+		return fmt.Errorf("type is required")
+	}
+	if ac.Spec.Expose && (ac.Spec.ExposePort <= 0 || ac.Spec.ExposePort > 65535) {
+		return fmt.Errorf("expose port %d is not valid", ac.Spec.ExposePort)
+	}
+	names := make(map[string]bool)
+	for _, configFile := range ac.Spec.ConfigFiles {
+		if specgen.IsGeneratedConfigFile(configFile.Name) {
+			continue
+		}
+		if !configKeyPattern.MatchString(configFile.Name) || configFile.Name == "." || configFile.Name == ".." {
+			return fmt.Errorf("config file name %q is not a valid key", configFile.Name)
+		}
+		if !configKeyPattern.MatchString(configFile.Filename) || configFile.Filename == "." || configFile.Filename == ".." {
+			return fmt.Errorf("config file %s filename %q must be a plain file name", configFile.Name, configFile.Filename)
+		}
+		if names[configFile.Name] {
+			return fmt.Errorf("config file name %s is used twice", configFile.Name)
+		}
+		names[configFile.Name] = true
+		if err := specgen.ValidateConfigDirectory(configFile.Directory); err != nil {
+			return fmt.Errorf("config file %s: %v", configFile.Name, err)
+		}
+		if len(configFile.FileMode) > 0 && !fileModePattern.MatchString(configFile.FileMode) {
+			return fmt.Errorf("config file %s mode %s must be octal permission bits only", configFile.Name, configFile.FileMode)
+		}
+		if configFile.Uid <= 0 || configFile.Gid <= 0 {
+			return fmt.Errorf("config file %s uid and gid must be set and not root", configFile.Name)
+		}
+	}
+	return nil
+}
+
+func (i *WebApi) sitepodAppComponent(req *restful.Request, resp *restful.Response, permission Permission) (*v1.Sitepod, *v1.Appcomponent, bool) {
+	sitepod, ok := i.authorizedSitepod(req, resp, permission)
+	if !ok {
+		return nil, nil, false
+	}
+	name := req.PathParameter("name")
+	for _, ac := range i.client.AppComps().BySitepodKey(string(sitepod.UID)) {
+		if ac.Name == name {
+			return sitepod, ac, true
+		}
+	}
+	resp.WriteHeaderAndEntity(404, NewAPIError("app component not found"))
+	return nil, nil, false
+}
+
+func (i *WebApi) ListAppComponents(req *restful.Request, resp *restful.Response) {
+	if sitepod, ok := i.authorizedSitepod(req, resp, PermissionViewResources); ok {
+		acs := append([]*v1.Appcomponent{}, i.client.AppComps().BySitepodKey(string(sitepod.UID))...)
+		resp.WriteHeaderAndEntity(200, acs)
+	}
+}
+
+func (i *WebApi) GetAppComponent(req *restful.Request, resp *restful.Response) {
+	if _, ac, ok := i.sitepodAppComponent(req, resp, PermissionViewResources); ok {
+		resp.WriteHeaderAndEntity(200, ac)
+	}
+}
+
+func (i *WebApi) CreateAppComponent(req *restful.Request, resp *restful.Response) {
+
+	sitepod, ok := i.authorizedSitepod(req, resp, PermissionEditResources)
+	if !ok {
+		return
+	}
+
+	entity := &AppComponentRequest{}
+	if err := req.ReadEntity(entity); err != nil {
+		resp.WriteHeaderAndEntity(400, NewAPIError("Invalid Payload"))
+		return
+	}
+
+	ac := i.client.AppComps().NewEmpty()
+	ac.Labels["sitepod"] = string(sitepod.UID)
+	ac.Spec = entity.Spec
+
+	if err := restrictAppComponent(ac, nil); err != nil {
+		writeInvalid(resp, err)
+		return
+	}
+	if err := validateAppComponent(ac); err != nil {
+		writeInvalid(resp, err)
+		return
+	}
+
+	ac, err := i.client.AppComps().TryAdd(ac)
+	if err != nil {
+		writeClientError(resp, err)
+		return
+	}
+	resp.WriteHeaderAndEntity(201, ac)
+}
+
+func (i *WebApi) UpdateAppComponent(req *restful.Request, resp *restful.Response) {
+
+	_, ac, ok := i.sitepodAppComponent(req, resp, PermissionEditResources)
+	if !ok {
+		return
+	}
+
+	entity := &AppComponentRequest{}
+	if err := req.ReadEntity(entity); err != nil {
+		resp.WriteHeaderAndEntity(400, NewAPIError("Invalid Payload"))
+		return
+	}
+
+	if !checkResourceVersion(resp, entity.ResourceVersion, ac.ResourceVersion) {
+		return
+	}
+
+	updated := i.client.AppComps().CloneItem(ac)
+	updated.Spec = entity.Spec
+
+	if err := restrictAppComponent(updated, ac); err != nil {
+		writeInvalid(resp, err)
+		return
+	}
+	if err := validateAppComponent(updated); err != nil {
+		writeInvalid(resp, err)
+		return
+	}
+
+	updated, err := i.client.AppComps().TryUpdate(updated)
+	if err != nil {
+		writeClientError(resp, err)
+		return
+	}
+	resp.WriteHeaderAndEntity(200, updated)
+}
+
+func (i *WebApi) DeleteAppComponent(req *restful.Request, resp *restful.Response) {
+	_, ac, ok := i.sitepodAppComponent(req, resp, PermissionEditResources)
+	if !ok {
+		return
+	}
+	if err := i.client.AppComps().TryDelete(ac); err != nil {
+		writeClientError(resp, err)
+		return
+	}
+	resp.WriteHeaderAndEntity(200, struct{}{})
+}
@@ -0,0 +1,239 @@
+package webapi
+
+// backendStore is a gorilla session store keeping only a signed session id in the cookie
+// and the values, encoded with the same keys, in a backend: memory for a single instance
+// or web session resources shared by all instances.
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+	kerrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/labels"
+	"sitepod.io/sitepod/pkg/client"
+)
+
+var errSessionNotFound = errors.New("session not found")
+
+type sessionBackend interface {
+	// Load returns errSessionNotFound for unknown or expired sessions
+	Load(id string) (string, error)
+	Save(id string, data string, expiresAt time.Time) error
+	Delete(id string) error
+	// Expire removes sessions past their expiry
+	Expire(now time.Time) error
+}
+
+type backendStore struct {
+	Codecs  []securecookie.Codec
+	Options *sessions.Options
+	backend sessionBackend
+}
+
+func newBackendStore(backend sessionBackend, options *sessions.Options, keyPairs [][]byte) *backendStore {
+
+	codecs := securecookie.CodecsFromPairs(keyPairs...)
+	for _, codec := range codecs {
+		if sc, ok := codec.(*securecookie.SecureCookie); ok {
+			sc.MaxAge(options.MaxAge)
+			// values are not limited by cookie size
+			sc.MaxLength(0)
+		}
+	}
+	return &backendStore{codecs, options, backend}
+}
+
+func (s *backendStore) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(s, name)
+}
+
+func (s *backendStore) New(r *http.Request, name string) (*sessions.Session, error) {
+
+	session := sessions.NewSession(s, name)
+	options := *s.Options
+	session.Options = &options
+	session.IsNew = true
+
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return session, nil
+	}
+
+	if err = securecookie.DecodeMulti(name, cookie.Value, &session.ID, s.Codecs...); err != nil {
+		return session, err
+	}
+
+	data, err := s.backend.Load(session.ID)
+	if err == errSessionNotFound {
+		// expired or logged out elsewhere, a new session gets a new id
+		session.ID = ""
+		return session, nil
+	}
+	if err != nil {
+		return session, err
+	}
+
+	if err = securecookie.DecodeMulti(name, data, &session.Values, s.Codecs...); err != nil {
+		return session, err
+	}
+	session.IsNew = false
+	return session, nil
+}
+
+func (s *backendStore) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+
+	if session.Options.MaxAge < 0 {
+		if len(session.ID) > 0 {
+			if err := s.backend.Delete(session.ID); err != nil {
+				return err
+			}
+		}
+		http.SetCookie(w, sessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+
+	if len(session.ID) == 0 {
+		session.ID = hex.EncodeToString(securecookie.GenerateRandomKey(32))
+	}
+
+	data, err := securecookie.EncodeMulti(session.Name(), session.Values, s.Codecs...)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(time.Duration(session.Options.MaxAge) * time.Second)
+	if err = s.backend.Save(session.ID, data, expiresAt); err != nil {
+		return err
+	}
+
+	encoded, err := securecookie.EncodeMulti(session.Name(), session.ID, s.Codecs...)
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, sessions.NewCookie(session.Name(), encoded, session.Options))
+	return nil
+}
+
+type memorySession struct {
+	data      string
+	expiresAt time.Time
+}
+
+type memorySessionBackend struct {
+	mutex    sync.Mutex
+	sessions map[string]memorySession
+}
+
+func newMemorySessionBackend() *memorySessionBackend {
+	return &memorySessionBackend{sessions: make(map[string]memorySession)}
+}
+
+func (b *memorySessionBackend) Load(id string) (string, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	session, exists := b.sessions[id]
+	if !exists || !time.Now().Before(session.expiresAt) {
+		return "", errSessionNotFound
+	}
+	return session.data, nil
+}
+
+func (b *memorySessionBackend) Save(id string, data string, expiresAt time.Time) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.sessions[id] = memorySession{data, expiresAt}
+	return nil
+}
+
+func (b *memorySessionBackend) Delete(id string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	delete(b.sessions, id)
+	return nil
+}
+
+func (b *memorySessionBackend) Expire(now time.Time) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for id, session := range b.sessions {
+		if !now.Before(session.expiresAt) {
+			delete(b.sessions, id)
+		}
+	}
+	return nil
+}
+
+// sharedSessionBackend reads through to the api server rather than an informer so a
+// session saved by one instance is immediately visible to the others
+type sharedSessionBackend struct {
+	client *client.Client
+}
+
+func webSessionName(id string) string {
+	return "websession-" + id
+}
+
+func (b *sharedSessionBackend) Load(id string) (string, error) {
+	session, err := b.client.WebSessions().FetchByName(webSessionName(id))
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", errSessionNotFound
+		}
+		return "", err
+	}
+	if session.IsExpired(time.Now()) {
+		return "", errSessionNotFound
+	}
+	return session.Spec.Data, nil
+}
+
+func (b *sharedSessionBackend) Save(id string, data string, expiresAt time.Time) error {
+
+	session, err := b.client.WebSessions().FetchByName(webSessionName(id))
+
+	if err != nil && kerrors.IsNotFound(err) {
+		session = b.client.WebSessions().NewEmpty()
+		session.GenerateName = ""
+		session.Name = webSessionName(id)
+		session.Spec.Data = data
+		session.Spec.ExpiresAt = unversioned.NewTime(expiresAt)
+		_, err = b.client.WebSessions().TryAdd(session)
+		return err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	session.Spec.Data = data
+	session.Spec.ExpiresAt = unversioned.NewTime(expiresAt)
+	_, err = b.client.WebSessions().TryUpdate(session)
+	return err
+}
+
+func (b *sharedSessionBackend) Delete(id string) error {
+	session := b.client.WebSessions().NewEmpty()
+	session.Name = webSessionName(id)
+	err := b.client.WebSessions().TryDelete(session)
+	if err != nil && kerrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func (b *sharedSessionBackend) Expire(now time.Time) error {
+	for _, session := range b.client.WebSessions().FetchList(labels.Everything()) {
+		if session.IsExpired(now) {
+			if err := b.client.WebSessions().TryDelete(session); err != nil && !kerrors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package webapi
+
+// API tokens let scripts and services call the web api without a browser session. A
+// token is "sp.<id>.<secret>": the id names the ApiToken resource and only a sha256 of
+// the secret is stored, a lookup by id then a constant time compare authenticates it.
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/golang/glog"
+	"sitepod.io/sitepod/pkg/util"
+)
+
+const apiTokenPrefix = "sp"
+
+// GenerateAPIToken is a new token and the ApiToken resource name it is stored under
+func GenerateAPIToken() (name string, secret string, token string) {
+	id := hex.EncodeToString(util.RandomBytes(8))
+	secret = hex.EncodeToString(util.RandomBytes(32))
+	return apiTokenName(id), secret, apiTokenPrefix + "." + id + "." + secret
+}
+
+func apiTokenName(id string) string {
+	return "apitoken-" + id
+}
+
+// ParseAPIToken splits a token into its resource name and secret
+func ParseAPIToken(token string) (name string, secret string, ok bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != apiTokenPrefix || len(parts[1]) == 0 || len(parts[2]) == 0 {
+		return "", "", false
+	}
+	if _, err := hex.DecodeString(parts[1]); err != nil {
+		return "", "", false
+	}
+	return apiTokenName(parts[1]), parts[2], true
+}
+
+func HashAPITokenSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// tokenFilter authenticates a bearer token in place of a session, the token's scopes
+// are kept as the "scopes" attribute for authorization
+func (i *WebApi) tokenFilter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain, bearer string) {
+
+	name, secret, ok := ParseAPIToken(bearer)
+	if !ok {
+		resp.WriteHeaderAndEntity(401, NewAPIError("invalid token"))
+		return
+	}
+
+	token, err := i.client.ApiTokens().FetchByName(name)
+	if err != nil {
+		resp.WriteHeaderAndEntity(401, NewAPIError("invalid token"))
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(HashAPITokenSecret(secret)), []byte(token.Spec.SecretHash)) != 1 {
+		glog.Warningf("Wrong secret for api token %s from %s", name, clientAddr(req.Request))
+		resp.WriteHeaderAndEntity(401, NewAPIError("invalid token"))
+		return
+	}
+
+	if token.IsExpired(time.Now()) {
+		resp.WriteHeaderAndEntity(401, NewAPIError("token expired"))
+		return
+	}
+
+	user, err := i.client.SitepodUsers().FetchByName(token.Spec.User)
+	if err != nil {
+		resp.WriteHeaderAndEntity(401, NewAPIError("invalid token"))
+		return
+	}
+
+	req.SetAttribute("session", &SitepodSession{Authenticated: true, Email: user.Spec.Email})
+	req.SetAttribute("scopes", token.Spec.Scopes)
+	req.SetAttribute("token", token.Name)
+	chain.ProcessFilter(req, resp)
+}
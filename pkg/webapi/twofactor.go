@@ -0,0 +1,285 @@
+package webapi
+
+// Two-factor authentication. Login of a user with an enrolled authenticator is two
+// steps: the password step leaves an unauthenticated session marked TwoFactorRequired,
+// POST /api/login/2fa with a code then authenticates it under a new session id.
+// Enrolment and recovery codes are managed by the logged in user from a browser session,
+// api tokens are refused.
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"sitepod.io/sitepod/pkg/api/v1"
+	"sitepod.io/sitepod/pkg/util"
+)
+
+var (
+	// TwoFactorLoginTimeout is how long after the password the code may be given
+	TwoFactorLoginTimeout = 5 * time.Minute
+	// TOTPIssuer names the panel in authenticator apps
+	TOTPIssuer = "Sitepod"
+)
+
+func (i *WebApi) addTwoFactorRoutes(ws *restful.WebService) {
+
+	ws.Route(documentErrors(ws.GET("/2fa").To(i.TwoFactorStatus).
+		Doc("Whether two-factor authentication is enabled for the logged in user").
+		Metadata(tags(tagTwoFactor)).
+		Writes(TwoFactorStatus{}).
+		Returns(200, "OK", TwoFactorStatus{})))
+
+	ws.Route(documentErrors(ws.POST("/2fa/totp").To(i.BeginTOTPEnrolment).
+		Doc("Start enrolling an authenticator app, the uri is shown as a QR code").
+		Metadata(tags(tagTwoFactor)).
+		Writes(TOTPEnrolment{}).
+		Returns(200, "OK", TOTPEnrolment{}).
+		Returns(409, "already enabled", APIError{})))
+
+	ws.Route(documentErrors(ws.POST("/2fa/totp/confirm").To(i.ConfirmTOTPEnrolment).
+		Doc("Enable two-factor authentication with a code of the enrolling authenticator, returning recovery codes").
+		Metadata(tags(tagTwoFactor)).
+		Reads(TwoFactorCodeRequest{}).
+		Writes(RecoveryCodes{}).
+		Returns(200, "OK", RecoveryCodes{}).
+		Returns(400, "code incorrect or no enrolment in progress", APIError{})))
+
+	ws.Route(documentErrors(ws.POST("/2fa/recovery-codes").To(i.RegenerateRecoveryCodes).
+		Doc("Replace the recovery codes, given a current code").
+		Metadata(tags(tagTwoFactor)).
+		Reads(TwoFactorCodeRequest{}).
+		Writes(RecoveryCodes{}).
+		Returns(200, "OK", RecoveryCodes{}).
+		Returns(400, "code incorrect or not enabled", APIError{}).
+		Returns(429, "too many attempts", APIError{})))
+
+	ws.Route(documentErrors(ws.POST("/2fa/disable").To(i.DisableTwoFactor).
+		Doc("Disable two-factor authentication, given the password and a current code").
+		Metadata(tags(tagTwoFactor)).
+		Reads(DisableTwoFactorRequest{}).
+		Returns(200, "OK", nil).
+		Returns(400, "password or code incorrect", APIError{}).
+		Returns(429, "too many attempts", APIError{})))
+}
+
+// LoginSecondFactor completes a login pending the second factor
+func (i *WebApi) LoginSecondFactor(req *restful.Request, resp *restful.Response) {
+
+	session, err := i.sessionStore.Get(req.Request, "sitepodfe")
+	pending := sessionOf(session)
+	if err != nil || session.IsNew || pending == nil || !pending.TwoFactorRequired || pending.PasswordVerifiedAt == nil {
+		resp.WriteHeaderAndEntity(401, NewAPIError("log in with email and password first"))
+		return
+	}
+
+	if time.Since(*pending.PasswordVerifiedAt) > TwoFactorLoginTimeout {
+		resp.WriteHeaderAndEntity(401, NewAPIError("login timed out, log in again"))
+		return
+	}
+
+	entity := &TwoFactorCodeRequest{}
+	if err = req.ReadEntity(entity); err != nil || len(entity.Data.Code) == 0 {
+		resp.WriteHeaderAndEntity(400, NewAPIError("Invalid Payload"))
+		return
+	}
+
+	if !i.allowCodeAttempt(req, resp, pending.Email) {
+		return
+	}
+
+	user, err := i.client.SitepodUsers().FetchByName("sitepod-user-" + GetMD5Hash(pending.Email))
+	if err != nil {
+		resp.WriteHeaderAndEntity(401, NewAPIError("log in with email and password first"))
+		return
+	}
+
+	if !user.VerifySecondFactor(entity.Data.Code, time.Now()) {
+		i.loginLimiter.Failed(pending.Email)
+		resp.WriteHeaderAndEntity(401, NewAPIError("code incorrect"))
+		return
+	}
+
+	// saving the used code is what makes it single use, a conflict means a concurrent
+	// login may have used it
+	if _, err = i.client.SitepodUsers().TryUpdate(user); err != nil {
+		writeClientError(resp, err)
+		return
+	}
+
+	i.loginLimiter.Succeeded(pending.Email)
+	i.startSession(req, resp, &SitepodSession{Authenticated: true, Email: user.Spec.Email, Generation: user.Spec.SessionGeneration})
+}
+
+// allowCodeAttempt applies the login limits to guessing codes
+func (i *WebApi) allowCodeAttempt(req *restful.Request, resp *restful.Response, email string) bool {
+	if allowed, retryAfter := i.loginLimiter.Allow(clientAddr(req.Request), email); !allowed {
+		resp.AddHeader("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+		resp.WriteHeaderAndEntity(429, NewAPIError("too many attempts, try again later"))
+		return false
+	}
+	return true
+}
+
+// sessionUser fetches the user of a browser session, the account is not managed with
+// api tokens
+func (i *WebApi) sessionUser(req *restful.Request, resp *restful.Response) (*v1.SitepodUser, bool) {
+
+	if req.Attribute("token") != nil {
+		resp.WriteHeaderAndEntity(403, NewAPIError("api tokens can not manage the account"))
+		return nil, false
+	}
+
+	name := sitepodUserName(req)
+	if len(name) == 0 {
+		resp.WriteHeaderAndEntity(401, NewAPIError("not logged in"))
+		return nil, false
+	}
+
+	user, err := i.client.SitepodUsers().FetchByName(name)
+	if err != nil {
+		resp.WriteHeaderAndEntity(404, NewAPIError("user not found"))
+		return nil, false
+	}
+	return user, true
+}
+
+func (i *WebApi) TwoFactorStatus(req *restful.Request, resp *restful.Response) {
+
+	user, ok := i.sessionUser(req, resp)
+	if !ok {
+		return
+	}
+
+	resp.WriteHeaderAndEntity(200, TwoFactorStatus{
+		Enabled:           user.TwoFactorEnabled(),
+		RecoveryCodesLeft: len(user.Spec.TwoFactor.RecoveryCodes),
+	})
+}
+
+// BeginTOTPEnrolment starts a new enrolment, replacing one left unconfirmed
+func (i *WebApi) BeginTOTPEnrolment(req *restful.Request, resp *restful.Response) {
+
+	user, ok := i.sessionUser(req, resp)
+	if !ok {
+		return
+	}
+
+	if user.TwoFactorEnabled() {
+		resp.WriteHeaderAndEntity(409, NewAPIError("two-factor authentication already enabled"))
+		return
+	}
+
+	secret := user.BeginTOTPEnrolment()
+	if _, err := i.client.SitepodUsers().TryUpdate(user); err != nil {
+		writeClientError(resp, err)
+		return
+	}
+
+	resp.WriteHeaderAndEntity(200, TOTPEnrolment{
+		Secret: secret,
+		URI:    util.TOTPProvisioningURI(TOTPIssuer, user.Spec.Email, secret),
+	})
+}
+
+func (i *WebApi) ConfirmTOTPEnrolment(req *restful.Request, resp *restful.Response) {
+
+	user, ok := i.sessionUser(req, resp)
+	if !ok {
+		return
+	}
+
+	entity := &TwoFactorCodeRequest{}
+	if err := req.ReadEntity(entity); err != nil {
+		resp.WriteHeaderAndEntity(400, NewAPIError("Invalid Payload"))
+		return
+	}
+
+	codes, err := user.ConfirmTOTPEnrolment(entity.Data.Code, time.Now())
+	if err != nil {
+		resp.WriteHeaderAndEntity(400, NewAPIError(err.Error()))
+		return
+	}
+
+	if _, err = i.client.SitepodUsers().TryUpdate(user); err != nil {
+		writeClientError(resp, err)
+		return
+	}
+
+	resp.WriteHeaderAndEntity(200, RecoveryCodes{codes})
+}
+
+func (i *WebApi) RegenerateRecoveryCodes(req *restful.Request, resp *restful.Response) {
+
+	user, ok := i.sessionUser(req, resp)
+	if !ok {
+		return
+	}
+
+	entity := &TwoFactorCodeRequest{}
+	if err := req.ReadEntity(entity); err != nil {
+		resp.WriteHeaderAndEntity(400, NewAPIError("Invalid Payload"))
+		return
+	}
+
+	if !user.TwoFactorEnabled() {
+		resp.WriteHeaderAndEntity(400, NewAPIError("two-factor authentication not enabled"))
+		return
+	}
+
+	if !i.allowCodeAttempt(req, resp, user.Spec.Email) {
+		return
+	}
+
+	if !user.VerifySecondFactor(entity.Data.Code, time.Now()) {
+		i.loginLimiter.Failed(user.Spec.Email)
+		resp.WriteHeaderAndEntity(400, NewAPIError("code incorrect"))
+		return
+	}
+
+	codes := user.GenerateRecoveryCodes()
+	if _, err := i.client.SitepodUsers().TryUpdate(user); err != nil {
+		writeClientError(resp, err)
+		return
+	}
+
+	resp.WriteHeaderAndEntity(200, RecoveryCodes{codes})
+}
+
+func (i *WebApi) DisableTwoFactor(req *restful.Request, resp *restful.Response) {
+
+	user, ok := i.sessionUser(req, resp)
+	if !ok {
+		return
+	}
+
+	entity := &DisableTwoFactorRequest{}
+	if err := req.ReadEntity(entity); err != nil {
+		resp.WriteHeaderAndEntity(400, NewAPIError("Invalid Payload"))
+		return
+	}
+
+	if !user.TwoFactorEnabled() {
+		resp.WriteHeaderAndEntity(400, NewAPIError("two-factor authentication not enabled"))
+		return
+	}
+
+	if !i.allowCodeAttempt(req, resp, user.Spec.Email) {
+		return
+	}
+
+	matched, _, err := user.VerifyPassword(entity.Data.Password)
+	if err != nil || !matched || !user.VerifySecondFactor(entity.Data.Code, time.Now()) {
+		i.loginLimiter.Failed(user.Spec.Email)
+		resp.WriteHeaderAndEntity(400, NewAPIError("password or code incorrect"))
+		return
+	}
+
+	user.DisableTwoFactor()
+	if _, err = i.client.SitepodUsers().TryUpdate(user); err != nil {
+		writeClientError(resp, err)
+		return
+	}
+
+	resp.WriteHeaderAndEntity(200, struct{}{})
+}
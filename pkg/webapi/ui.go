@@ -0,0 +1,178 @@
+package webapi
+
+// Serves the frontend build under /ui from a configured directory, or from a file system
+// compiled into the binary by setting EmbeddedUI. Assets whose names carry a content
+// hash (app.3f2a9c1e.js) never change so are cached for a year, anything else including
+// index.html is revalidated against an ETag of its content. Paths without an extension
+// are client side routes and get index.html.
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/golang/glog"
+)
+
+// EmbeddedUI is used when no ui directory is configured, a build embedding the frontend
+// sets it from an init func
+var EmbeddedUI http.FileSystem
+
+var (
+	hashedAssetPattern = regexp.MustCompile(`[.-][0-9a-f]{8,}\.[a-z0-9]+$`)
+
+	uiContentTypes = map[string]string{
+		".html":  "text/html; charset=utf-8",
+		".js":    "application/javascript; charset=utf-8",
+		".css":   "text/css; charset=utf-8",
+		".json":  "application/json",
+		".map":   "application/json",
+		".svg":   "image/svg+xml",
+		".png":   "image/png",
+		".jpg":   "image/jpeg",
+		".gif":   "image/gif",
+		".ico":   "image/x-icon",
+		".woff":  "font/woff",
+		".woff2": "font/woff2",
+		".ttf":   "font/ttf",
+		".txt":   "text/plain; charset=utf-8",
+	}
+)
+
+type uiAssets struct {
+	fs http.FileSystem
+	// etags by path, invalidated by modification time and size
+	etags      map[string]uiETag
+	etagsMutex sync.Mutex
+}
+
+type uiETag struct {
+	modTime time.Time
+	size    int64
+	etag    string
+}
+
+func newUIAssets(config *Config) *uiAssets {
+	var fs http.FileSystem
+	if len(config.UIDir) > 0 {
+		fs = http.Dir(config.UIDir)
+	} else if EmbeddedUI != nil {
+		fs = EmbeddedUI
+	} else {
+		glog.Warningf("No ui directory configured and no embedded ui, /ui will not be served")
+	}
+	return &uiAssets{fs: fs, etags: make(map[string]uiETag)}
+}
+
+// cleanUIPath is the asset path rooted at / or false if it is not acceptable. Clean of
+// a rooted path can not climb above the root but dot files, backslashes and NULs are
+// refused outright.
+func cleanUIPath(subPath string) (string, bool) {
+	if strings.ContainsAny(subPath, "\\\x00") {
+		return "", false
+	}
+	cleaned := path.Clean("/" + subPath)
+	for _, segment := range strings.Split(cleaned, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return "", false
+		}
+	}
+	return cleaned, true
+}
+
+func (u *uiAssets) Serve(req *restful.Request, resp *restful.Response) {
+
+	if u.fs == nil {
+		http.NotFound(resp.ResponseWriter, req.Request)
+		return
+	}
+
+	assetPath, ok := cleanUIPath(req.PathParameter("subpath"))
+	if !ok {
+		http.NotFound(resp.ResponseWriter, req.Request)
+		return
+	}
+
+	if assetPath == "/" || len(path.Ext(assetPath)) == 0 {
+		assetPath = "/index.html"
+	}
+
+	file, err := u.fs.Open(assetPath)
+	if err != nil {
+		http.NotFound(resp.ResponseWriter, req.Request)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(resp.ResponseWriter, req.Request)
+		return
+	}
+
+	etag, err := u.etagOf(assetPath, file, info)
+	if err != nil {
+		glog.Errorf("Unable to read ui asset %s: %v", assetPath, err)
+		resp.WriteHeader(500)
+		return
+	}
+
+	header := resp.Header()
+	header.Set("Content-Type", uiContentType(assetPath))
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("ETag", etag)
+	if hashedAssetPattern.MatchString(path.Base(assetPath)) {
+		header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		header.Set("Cache-Control", "no-cache")
+	}
+
+	http.ServeContent(resp.ResponseWriter, req.Request, assetPath, info.ModTime(), file)
+}
+
+// etagOf hashes the content of an asset, leaving the file positioned at the start
+func (u *uiAssets) etagOf(assetPath string, file http.File, info os.FileInfo) (string, error) {
+
+	u.etagsMutex.Lock()
+	cached, exists := u.etags[assetPath]
+	u.etagsMutex.Unlock()
+
+	if exists && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.etag, nil
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	etag := fmt.Sprintf(`"%s"`, hex.EncodeToString(hash.Sum(nil))[:32])
+	u.etagsMutex.Lock()
+	u.etags[assetPath] = uiETag{info.ModTime(), info.Size(), etag}
+	u.etagsMutex.Unlock()
+	return etag, nil
+}
+
+func uiContentType(assetPath string) string {
+	ext := strings.ToLower(path.Ext(assetPath))
+	if contentType, exists := uiContentTypes[ext]; exists {
+		return contentType
+	}
+	if contentType := mime.TypeByExtension(ext); len(contentType) > 0 {
+		return contentType
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,119 @@
+package webapi
+
+// Signed, expiring tokens mailed to users to reset a password or verify an email
+// address. Nothing is stored: a token is "<payload>.<hmac>", both base64url, with the
+// payload "purpose|user|expiry|binding". The binding is a hash of what the token must
+// not outlive, the password hash for resets and the email for verification, so a reset
+// token is spent once the password changes. Tokens are signed with a key derived from
+// the newest session key and verified with any, rotating session keys rotates them too.
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"sitepod.io/sitepod/pkg/api/v1"
+)
+
+const (
+	TokenPurposeResetPassword = "reset-password"
+	TokenPurposeVerifyEmail   = "verify-email"
+)
+
+var (
+	ResetPasswordTokenLifetime = time.Hour
+	VerifyEmailTokenLifetime   = 48 * time.Hour
+)
+
+var errInvalidUserToken = errors.New("invalid or expired token")
+
+var userTokenEncoding = base64.RawURLEncoding
+
+type userTokenSigner struct {
+	keys [][]byte
+}
+
+// newUserTokenSigner derives signing keys from the hash keys of the session key pairs,
+// a token signature is then never valid as a cookie signature
+func newUserTokenSigner(keyPairs [][]byte) *userTokenSigner {
+	signer := &userTokenSigner{}
+	for i := 0; i < len(keyPairs); i += 2 {
+		mac := hmac.New(sha256.New, keyPairs[i])
+		mac.Write([]byte("sitepod user tokens"))
+		signer.keys = append(signer.keys, mac.Sum(nil))
+	}
+	return signer
+}
+
+func (s *userTokenSigner) mac(key []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+func (s *userTokenSigner) Sign(purpose string, user *v1.SitepodUser, expires time.Time) string {
+	payload := strings.Join([]string{purpose, user.Name, strconv.FormatInt(expires.Unix(), 10), tokenBinding(purpose, user)}, "|")
+	return userTokenEncoding.EncodeToString([]byte(payload)) + "." + userTokenEncoding.EncodeToString(s.mac(s.keys[0], payload))
+}
+
+// Verify checks the signature, purpose and expiry of a token and returns the user it was
+// issued to, the caller must then check the binding against the user with Bound
+func (s *userTokenSigner) Verify(purpose string, token string, now time.Time) (user string, binding string, err error) {
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return "", "", errInvalidUserToken
+	}
+	payload, err := userTokenEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", "", errInvalidUserToken
+	}
+	signature, err := userTokenEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", "", errInvalidUserToken
+	}
+
+	signed := false
+	for _, key := range s.keys {
+		if hmac.Equal(signature, s.mac(key, string(payload))) {
+			signed = true
+			break
+		}
+	}
+	if !signed {
+		return "", "", errInvalidUserToken
+	}
+
+	fields := strings.Split(string(payload), "|")
+	if len(fields) != 4 || fields[0] != purpose {
+		return "", "", errInvalidUserToken
+	}
+	expires, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil || now.Unix() > expires {
+		return "", "", errInvalidUserToken
+	}
+	return fields[1], fields[3], nil
+}
+
+// Bound is whether a token binding still matches the user
+func (s *userTokenSigner) Bound(purpose string, binding string, user *v1.SitepodUser) bool {
+	return subtle.ConstantTimeCompare([]byte(binding), []byte(tokenBinding(purpose, user))) == 1
+}
+
+func tokenBinding(purpose string, user *v1.SitepodUser) string {
+	var bound string
+	switch purpose {
+	case TokenPurposeResetPassword:
+		bound = user.Spec.PasswordHash + "|" + user.Spec.SaltedPassword + "|" + user.Spec.Email
+	default:
+		bound = user.Spec.Email
+	}
+	hash := sha256.Sum256([]byte(purpose + "|" + bound))
+	return hex.EncodeToString(hash[:16])
+}
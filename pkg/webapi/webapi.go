@@ -1,85 +1,210 @@
 package webapi
 
 import "encoding/gob"
+import "github.com/golang/glog"
+import kerrors "k8s.io/kubernetes/pkg/api/errors"
 import "github.com/gorilla/sessions"
 import "github.com/gorilla/context"
 import "crypto/md5"
 import "encoding/hex"
 import "fmt"
+import "net"
 import "net/http"
 import "sitepod.io/sitepod/pkg/client"
+import "sitepod.io/sitepod/pkg/mail"
 import "sitepod.io/sitepod/pkg/util"
 import "github.com/emicklei/go-restful"
 import "path"
 
+import "strconv"
 import "strings"
 import "time"
 
+// MinPasswordLength applies to new passwords
+const MinPasswordLength = 8
+
+// SessionExpireInterval is how often expired sessions are removed from the session store
+var SessionExpireInterval = 10 * time.Minute
+
 type WebApi struct {
+	config       *Config
 	container    *restful.Container
 	client       *client.Client
 	sessionStore sessions.Store
+	loginLimiter *loginLimiter
+	events       *eventHub
+	mailer       mail.Mailer
+	mailThrottle *mailThrottle
+	userTokens   *userTokenSigner
 }
 
-func NewWebApi(cc *client.Client) *WebApi {
+var dummyPasswordHash string
 
-	inst := &WebApi{}
-	inst.container = restful.NewContainer()
+func init() {
+	dummyPasswordHash, _ = util.HashPassword("", "sitepod-dummy-password")
+}
 
-	fileStore := sessions.NewFilesystemStore("", []byte("session1"))
-	//	fileStore.Options.Secure = true
-	fileStore.MaxAge(86400) //one day
+func NewWebApi(cc *client.Client, config *Config) (*WebApi, error) {
 
-	inst.sessionStore = fileStore
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	keyPairs, err := LoadSessionKeys(config, cc)
+	if err != nil {
+		return nil, err
+	}
+
+	mailer, err := newMailer(config)
+	if err != nil {
+		return nil, err
+	}
+
+	inst := &WebApi{}
+	inst.config = config
+	inst.container = restful.NewContainer()
+	inst.sessionStore = newSessionStore(config, cc, keyPairs)
 
 	inst.client = cc
+	inst.loginLimiter = newLoginLimiter()
+	inst.mailer = mailer
+	inst.mailThrottle = newMailThrottle()
+	inst.userTokens = newUserTokenSigner(keyPairs)
+	inst.events = newEventHub()
+	inst.watchResources()
 
 	ws := new(restful.WebService)
 	ws.Path("/api").Consumes(restful.MIME_JSON).Produces(restful.MIME_JSON)
-	ws.Route(ws.POST("/login").To(inst.Login))
-	ws.Route(ws.POST("/logout").To(inst.Logout))
-	ws.Route(ws.GET("/context").To(inst.WhoAmI))
-
+	ws.Filter(inst.SessionFilter)
+	ws.Route(ws.POST("/login").To(inst.Login).
+		Doc("Log in with email and password, starting a session. Users with two-factor authentication get two_factor_required and must follow with POST /api/login/2fa").
+		Metadata(tags(tagSession)).
+		Reads(LoginRequest{}).
+		Writes(SitepodSession{}).
+		Returns(200, "OK", SitepodSession{}).
+		Returns(401, "email or password incorrect", APIError{}).
+		Returns(429, "too many login attempts", APIError{}))
+
+	ws.Route(ws.POST("/login/2fa").To(inst.LoginSecondFactor).
+		Doc("Complete a login of a user with two-factor authentication with an authenticator or recovery code").
+		Metadata(tags(tagSession)).
+		Reads(TwoFactorCodeRequest{}).
+		Writes(SitepodSession{}).
+		Returns(200, "OK", SitepodSession{}).
+		Returns(401, "code incorrect or no login in progress", APIError{}).
+		Returns(429, "too many login attempts", APIError{}))
+
+	ws.Route(ws.POST("/logout").To(inst.Logout).
+		Doc("End the session").
+		Metadata(tags(tagSession)).
+		Returns(200, "OK", nil))
+
+	ws.Route(documentErrors(ws.GET("/context").To(inst.WhoAmI).
+		Doc("The session of the caller").
+		Metadata(tags(tagSession)).
+		Writes(SitepodSession{}).
+		Returns(200, "OK", SitepodSession{})))
+
+	ws.Route(documentErrors(ws.PUT("/password").To(inst.SetPassword).
+		Doc("Change the password of the logged in user, logging out their other sessions, not available to api tokens").
+		Metadata(tags(tagSession)).
+		Reads(SetPasswordRequest{}).
+		Returns(200, "OK", nil).
+		Returns(400, "new password too short", APIError{}).
+		Returns(429, "too many attempts", APIError{})))
+
+	inst.addTwoFactorRoutes(ws)
+	inst.addAccountRoutes(ws)
+	inst.addResourceRoutes(ws)
+	inst.addMemberRoutes(ws)
+
+	ws.Route(documentErrors(ws.GET("/events").To(inst.Events).
+		Doc("Server-sent events of changes to the sitepods, websites, pod tasks and system users of the user").
+		Metadata(tags(tagEvents)).
+		Produces("text/event-stream").
+		Writes(ResourceEvent{}).
+		Returns(200, "stream of events", ResourceEvent{})))
+
+	ui := newUIAssets(config)
 	staticWs := new(restful.WebService)
-	staticWs.Route(ws.GET("/ui/{subpath:*}").To(staticFromPathParam))
+	staticWs.Path("/ui")
+	staticWs.Route(staticWs.GET("/").To(ui.Serve))
+	staticWs.Route(staticWs.GET("/{subpath:*}").To(ui.Serve))
 
 	gob.Register(&SitepodSession{})
 	inst.container.Add(ws)
 	inst.container.Add(staticWs)
-	return inst
+	inst.addAPIDocs()
+	return inst, nil
 }
 
-func staticFromPathParam(req *restful.Request, resp *restful.Response) {
+// publicPaths are posted to without a session, the login steps and account recovery
+var publicPaths = map[string]bool{
+	"/api/login":                  true,
+	"/api/login/2fa":              true,
+	"/api/password/request-reset": true,
+	"/api/password/reset":         true,
+	"/api/verify-email":           true,
+}
 
-	subPath := req.Request.URL.Path[4:]
+// SessionFilter rejects calls without an authenticated session or api token, except to
+// login. The session is refreshed and made available to routes as the "session" attribute.
+func (i *WebApi) SessionFilter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
 
-	baseDir := "/home/matt/ws/sitepodfe/resources/public"
-	fmt.Println(subPath)
-	if strings.Index(subPath, "css/") == 0 || strings.Index(subPath, "js/") == 0 || strings.Index(subPath, "img/") == 0 {
-		actual := path.Join(baseDir, subPath)
-		if strings.Index(subPath, "js/") == 0 {
-			resp.Header().Set("Content-Type", "text/javascript; charset=UTF-8")
-		}
+	if req.Request.Method == "POST" && publicPaths[path.Clean(req.Request.URL.Path)] {
+		chain.ProcessFilter(req, resp)
+		return
+	}
 
-		http.ServeFile(resp.ResponseWriter, req.Request, actual)
+	if authorization := req.HeaderParameter("Authorization"); strings.HasPrefix(authorization, "Bearer ") {
+		i.tokenFilter(req, resp, chain, strings.TrimSpace(strings.TrimPrefix(authorization, "Bearer ")))
 		return
 	}
 
-	http.ServeFile(resp.ResponseWriter, req.Request, path.Join(baseDir, "index.html"))
-}
+	session, err := i.sessionStore.Get(req.Request, "sitepodfe")
+	if err != nil {
+		resp.WriteHeaderAndEntity(401, NewAPIError("not logged in"))
+		return
+	}
+
+	sitepodSession := sessionOf(session)
+	if session.IsNew || sitepodSession == nil || !sitepodSession.Authenticated {
+		resp.WriteHeaderAndEntity(401, NewAPIError("not logged in"))
+		return
+	}
+
+	if !i.sessionCurrent(sitepodSession) {
+		delete(session.Values, "sess")
+		session.Options.MaxAge = -1
+		session.Save(req.Request, resp.ResponseWriter)
+		resp.WriteHeaderAndEntity(401, NewAPIError("session ended, log in again"))
+		return
+	}
 
-//func (i *WebApi) SessionFilter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	lastSeen := time.Now().UTC().Round(time.Second)
+	sitepodSession.LastSeen = &lastSeen
+	session.Values["sess"] = sitepodSession
+	session.Save(req.Request, resp.ResponseWriter)
 
-//session, err := i.sessionStore.Get(req.Request, "sitepodfe")
-//if err != nil {
-//resp.WriteHeaderAndEntity(500, NewAPIError("No session"))
-//return
-//}
+	req.SetAttribute("session", sitepodSession)
+	chain.ProcessFilter(req, resp)
+}
 
-//session.Values["lastSeen"] = &time.Now()
+// sessionCurrent is false once the user is gone or has revoked their sessions. A session
+// newer than the cached user was started after a revocation the cache has yet to see.
+func (i *WebApi) sessionCurrent(sitepodSession *SitepodSession) bool {
+	user, exists := i.client.SitepodUsers().MaybeGetByKey("sitepod-user-" + GetMD5Hash(sitepodSession.Email))
+	return exists && sitepodSession.Generation >= user.Spec.SessionGeneration
+}
 
-//chain.ProcessFilter(req, resp)
-//}
+// clientAddr is the remote address without port, proxies are not trusted
+func clientAddr(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
 
 func (i *WebApi) Logout(req *restful.Request, resp *restful.Response) {
 	session, _ := i.sessionStore.Get(req.Request, "sitepodfe")
@@ -91,20 +216,10 @@ func (i *WebApi) Logout(req *restful.Request, resp *restful.Response) {
 	resp.WriteHeaderAndEntity(200, struct{}{})
 }
 
+// Login verifies the email and password of a sitepod user and starts an authenticated
+// session. Unknown users and wrong passwords get the same response.
 func (i *WebApi) Login(req *restful.Request, resp *restful.Response) {
 
-	session, _ := i.sessionStore.New(req.Request, "sitepodfe")
-	lastSeen := time.Now().UTC()
-	lastSeen = lastSeen.Round(time.Second)
-	sitepodSession := SitepodSession{&lastSeen, session.Options.MaxAge, true}
-	session.Values["sess"] = sitepodSession
-	session.Save(req.Request, resp.ResponseWriter)
-	resp.WriteHeaderAndEntity(200, sitepodSession)
-	return
-}
-
-func (i *WebApi) LoginX(req *restful.Request, resp *restful.Response) {
-
 	entity := &LoginRequest{}
 	err := req.ReadEntity(entity)
 
@@ -120,57 +235,212 @@ func (i *WebApi) LoginX(req *restful.Request, resp *restful.Response) {
 		return
 	}
 
+	if allowed, retryAfter := i.loginLimiter.Allow(clientAddr(req.Request), entity.Data.Email); !allowed {
+		resp.AddHeader("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+		resp.WriteHeaderAndEntity(429, NewAPIError("too many login attempts, try again later"))
+		return
+	}
+
 	key := "sitepod-user-" + GetMD5Hash(entity.Data.Email)
 
 	user, exists := i.client.SitepodUsers().MaybeGetByKey(key)
 
 	if !exists {
-		resp.WriteHeaderAndEntity(404, NewAPIError("user not found: "+entity.Data.Email))
+		// same work as a wrong password so response times don't reveal accounts
+		util.VerifyPassword(entity.Data.Password, dummyPasswordHash)
+		i.loginLimiter.Failed(entity.Data.Email)
+		resp.WriteHeaderAndEntity(401, NewAPIError("email or password incorrect"))
 		return
 	}
 
-	saltedPassword, err := Hash(entity.Data.Password, user.Spec.Salt)
+	matched, needsUpgrade, err := user.VerifyPassword(entity.Data.Password)
 
 	if err != nil {
-		resp.WriteHeaderAndEntity(500, NewAPIError("user not found"))
+		glog.Errorf("Unable to verify password of %s: %v", user.Name, err)
+		resp.WriteHeaderAndEntity(500, NewAPIError("unable to verify password"))
 		return
 	}
 
-	if saltedPassword != user.Spec.SaltedPassword {
-		resp.WriteHeaderAndEntity(403, NewAPIError("password incorrect"))
+	if !matched {
+		i.loginLimiter.Failed(entity.Data.Email)
+		resp.WriteHeaderAndEntity(401, NewAPIError("email or password incorrect"))
+		return
+	}
+
+	if needsUpgrade {
+		i.upgradePassword(user.Name, entity.Data.Password)
+	}
+
+	if user.TwoFactorEnabled() {
+		// failures are only forgiven once the second step passes too
+		verifiedAt := time.Now().UTC()
+		i.startSession(req, resp, &SitepodSession{
+			Email:              user.Spec.Email,
+			TwoFactorRequired:  true,
+			PasswordVerifiedAt: &verifiedAt,
+		})
 		return
 	}
 
-	session, err := i.sessionStore.New(req.Request, "sitepodfe")
+	i.loginLimiter.Succeeded(entity.Data.Email)
+	i.startSession(req, resp, &SitepodSession{Authenticated: true, Email: user.Spec.Email, Generation: user.Spec.SessionGeneration})
+}
+
+// startSession saves the sitepod session in a new gorilla session and responds with it
+func (i *WebApi) startSession(req *restful.Request, resp *restful.Response, sitepodSession *SitepodSession) {
+	i.saveNewSession(req, resp, sitepodSession)
+	resp.WriteHeaderAndEntity(200, sitepodSession)
+}
+
+func (i *WebApi) saveNewSession(req *restful.Request, resp *restful.Response, sitepodSession *SitepodSession) {
+
+	session, _ := i.sessionStore.New(req.Request, "sitepodfe")
+	// never carry on a session id from before login
+	session.ID = ""
+	session.Values = make(map[interface{}]interface{})
 	lastSeen := time.Now().UTC()
 	lastSeen = lastSeen.Round(time.Second)
-	sitepodSession := SitepodSession{&lastSeen, session.Options.MaxAge, true}
+	sitepodSession.LastSeen = &lastSeen
+	sitepodSession.MaxAge = session.Options.MaxAge
 	session.Values["sess"] = sitepodSession
 	session.Save(req.Request, resp.ResponseWriter)
-	resp.WriteHeaderAndEntity(200, sitepodSession)
-	return
 }
 
-func (i *WebApi) WhoAmI(req *restful.Request, resp *restful.Response) {
+// upgradePassword rehashes a legacy or weaker hash while the plain text is at hand, a
+// failure only means trying again on the next login
+func (i *WebApi) upgradePassword(name string, password string) {
 
-	session, _ := i.sessionStore.Get(req.Request, "sitepodfe")
+	user, err := i.client.SitepodUsers().FetchByName(name)
+	if err == nil {
+		err = user.SetPassword(password)
+	}
+	if err == nil {
+		_, err = i.client.SitepodUsers().TryUpdate(user)
+	}
+	if err != nil {
+		glog.Warningf("Unable to upgrade password hash of %s: %v", name, err)
+	}
+}
+
+// SetPassword changes the password of the logged in user, the current password is required
+// and guesses count against the login limits. Other sessions of the user are logged out.
+func (i *WebApi) SetPassword(req *restful.Request, resp *restful.Response) {
 
-	if session.IsNew {
-		resp.WriteHeaderAndEntity(200, &SitepodSession{})
+	sitepodSession, _ := req.Attribute("session").(*SitepodSession)
+
+	if sitepodSession == nil || len(sitepodSession.Email) == 0 {
+		resp.WriteHeaderAndEntity(401, NewAPIError("not logged in"))
 		return
 	}
 
-	resp.WriteHeaderAndEntity(200, session.Values["sess"])
+	if req.Attribute("token") != nil {
+		resp.WriteHeaderAndEntity(403, NewAPIError("api tokens can not change passwords"))
+		return
+	}
+
+	entity := &SetPasswordRequest{}
+	err := req.ReadEntity(entity)
+
+	if err != nil {
+		resp.WriteHeaderAndEntity(400, NewAPIError("Invalid Payload"))
+		return
+	}
+
+	if len(entity.Data.NewPassword) < MinPasswordLength {
+		resp.WriteHeaderAndEntity(400, NewAPIError(fmt.Sprintf("password must be at least %d characters", MinPasswordLength)))
+		return
+	}
+
+	user, err := i.client.SitepodUsers().FetchByName("sitepod-user-" + GetMD5Hash(sitepodSession.Email))
+
+	if err != nil {
+		resp.WriteHeaderAndEntity(404, NewAPIError("user not found"))
+		return
+	}
+
+	if !i.allowCodeAttempt(req, resp, user.Spec.Email) {
+		return
+	}
+
+	matched, _, err := user.VerifyPassword(entity.Data.CurrentPassword)
+
+	if err != nil || !matched {
+		i.loginLimiter.Failed(user.Spec.Email)
+		resp.WriteHeaderAndEntity(403, NewAPIError("password incorrect"))
+		return
+	}
+
+	i.loginLimiter.Succeeded(user.Spec.Email)
+
+	if err = user.SetPassword(entity.Data.NewPassword); err != nil {
+		resp.WriteHeaderAndEntity(500, NewAPIError("unable to set password"))
+		return
+	}
+	user.RevokeSessions()
+
+	if _, err = i.client.SitepodUsers().TryUpdate(user); err != nil {
+		if kerrors.IsConflict(err) {
+			resp.WriteHeaderAndEntity(409, NewAPIError("user changed, try again"))
+			return
+		}
+		resp.WriteHeaderAndEntity(500, NewAPIError("unable to set password"))
+		return
+	}
+
+	// the caller carries on under a new session, all others are logged out
+	i.saveNewSession(req, resp, &SitepodSession{Authenticated: true, Email: user.Spec.Email, Generation: user.Spec.SessionGeneration})
+	resp.WriteHeaderAndEntity(200, struct{}{})
+}
+
+// sessionOf is the sitepod session held in a gorilla session, stored as a value it comes
+// back from the store as a pointer
+func sessionOf(session *sessions.Session) *SitepodSession {
+	if session == nil {
+		return nil
+	}
+	switch sess := session.Values["sess"].(type) {
+	case *SitepodSession:
+		return sess
+	case SitepodSession:
+		return &sess
+	}
+	return nil
+}
+
+func (i *WebApi) WhoAmI(req *restful.Request, resp *restful.Response) {
+
+	resp.WriteHeaderAndEntity(200, req.Attribute("session"))
 }
 
 func (i *WebApi) Start() {
-	server := &http.Server{Addr: ":8081", Handler: context.ClearHandler(i.container)}
+	server := &http.Server{Addr: i.config.BindAddress, Handler: context.ClearHandler(i.container)}
 	go func() {
-		err := server.ListenAndServe()
+		var err error
+		if i.config.ServesTLS() {
+			glog.Infof("Serving web api with TLS on %s", i.config.BindAddress)
+			err = server.ListenAndServeTLS(i.config.TLSCertFile, i.config.TLSKeyFile)
+		} else {
+			glog.Infof("Serving web api on %s", i.config.BindAddress)
+			err = server.ListenAndServe()
+		}
 		if err != nil {
 			panic(err)
 		}
 	}()
+
+	if store, ok := i.sessionStore.(*backendStore); ok {
+		go i.expireSessions(store)
+	}
+}
+
+// expireSessions removes expired sessions from memory or the shared store, the
+// filesystem store leaves that to the os temp dir clean up
+func (i *WebApi) expireSessions(store *backendStore) {
+	for range time.Tick(SessionExpireInterval) {
+		if err := store.backend.Expire(time.Now()); err != nil {
+			glog.Warningf("Unable to expire sessions: %v", err)
+		}
+	}
 }
 
 func GetMD5Hash(text string) string {
@@ -178,14 +448,3 @@ func GetMD5Hash(text string) string {
 	hasher.Write([]byte(text))
 	return hex.EncodeToString(hasher.Sum(nil))
 }
-
-func Hash(clearPassword string, salt string) (string, error) {
-
-	hash, err := util.Sha512Crypt(clearPassword, salt)
-
-	if err != nil {
-		return "", err
-	}
-
-	return hash, nil
-}
@@ -6,7 +6,14 @@ kubectl -s=http://localhost:9080 create -f cluster.yaml
 kubectl -s=http://localhost:9080 create -f sitepod.yaml
 kubectl -s=http://localhost:9080 create -f appcomponent.yaml
 kubectl -s=http://localhost:9080 create -f systemuser.yaml
+kubectl -s=http://localhost:9080 create -f systemgroup.yaml
 kubectl -s=http://localhost:9080 create -f podtask.yaml
 kubectl -s=http://localhost:9080 create -f website.yaml
 kubectl -s=http://localhost:9080 create -f sitepoduser.yaml
+kubectl -s=http://localhost:9080 create -f websession.yaml
+kubectl -s=http://localhost:9080 create -f apitoken.yaml
+kubectl -s=http://localhost:9080 create -f backup.yaml
+kubectl -s=http://localhost:9080 create -f restore.yaml
+kubectl -s=http://localhost:9080 create -f backupschedule.yaml
 
+kubectl -s=http://localhost:9080 create -f sitepodmigration.yaml